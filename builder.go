@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/query"
 )
@@ -20,6 +21,12 @@ type TableChooser[TModel any] interface {
 	Table(table string) ColumnsAppender[TModel]
 }
 
+// ForcePrimary returns a context whose reads go to the writer adapter even on
+// repositories with readers (executor.WithReaders) — for read-your-writes
+// paths that cannot afford the replication lag. It re-exports
+// executor.ForcePrimary.
+var ForcePrimary = executor.ForcePrimary
+
 // AdapterChooser is the first step in the fluent builder chain returned by
 // gerpo.New[T](). Pick the executor.Adapter that wraps your SQL driver (pgx5,
 // pgx4, database/sql) with Adapter(...); executor-level options (cache,
@@ -47,7 +54,9 @@ func (b *builder[TModel]) Table(table string) ColumnsAppender[TModel] {
 
 // Adapter binds the executor.Adapter (wrapping pgx5, pgx4, or database/sql)
 // that the repository will execute through; executor-level options — cache
-// storage, tracing, etc. — are applied here as well.
+// storage, tracing, etc. — are applied here as well. With
+// executor.WithReaders among them, a is the writer and the reads go to the
+// readers.
 func (b *builder[TModel]) Adapter(a executor.Adapter, opts ...executor.Option) TableChooser[TModel] {
 	b.adapter = a
 	b.executorOptions = opts
@@ -93,6 +102,14 @@ func (b *builder[TModel]) WithBeforeUpdate(fn func(ctx context.Context, m *TMode
 	return b
 }
 
+// WithBeforeUpdateMany registers a function executed before a batched UPDATE.
+// The callback receives the full slice in one call — a non-nil error aborts
+// the call without running any SQL.
+func (b *builder[TModel]) WithBeforeUpdateMany(fn func(ctx context.Context, models []*TModel) error) Builder[TModel] {
+	b.opts = append(b.opts, WithBeforeUpdateMany[TModel](fn))
+	return b
+}
+
 // WithAfterSelect registers a callback executed after GetFirst/GetList with the
 // scanned models. A non-nil error is surfaced to the caller after the rows are
 // already fetched.
@@ -125,6 +142,14 @@ func (b *builder[TModel]) WithAfterInsertMany(fn func(ctx context.Context, model
 	return b
 }
 
+// WithAfterUpdateMany registers a callback executed after a successful batched
+// UPDATE with the full slice. A non-nil error is surfaced after the rows were
+// already modified.
+func (b *builder[TModel]) WithAfterUpdateMany(fn func(ctx context.Context, models []*TModel) error) Builder[TModel] {
+	b.opts = append(b.opts, WithAfterUpdateMany[TModel](fn))
+	return b
+}
+
 // WithErrorTransformer registers a function to transform or customize errors during repository operations.
 func (b *builder[TModel]) WithErrorTransformer(fn func(err error) error) Builder[TModel] {
 	b.opts = append(b.opts, WithErrorTransformer[TModel](fn))
@@ -139,6 +164,13 @@ func (b *builder[TModel]) WithTracer(tracer Tracer) Builder[TModel] {
 	return b
 }
 
+// WithIterateBatchSize sets how many streamed models Iterate hands to the
+// AfterSelect hook at once.
+func (b *builder[TModel]) WithIterateBatchSize(size int) Builder[TModel] {
+	b.opts = append(b.opts, WithIterateBatchSize[TModel](size))
+	return b
+}
+
 // Build finalizes the builder configuration and returns a Repository instance or an error if essential elements are missing.
 func (b *builder[TModel]) Build() (Repository[TModel], error) {
 	if b.adapter == nil {
@@ -148,5 +180,9 @@ func (b *builder[TModel]) Build() (Repository[TModel], error) {
 		return nil, errors.New("no table found")
 	}
 	exec := executor.New[TModel](b.adapter, b.executorOptions...)
-	return newRepository(exec, b.table, b.columnBuilderFn, b.opts...)
+	opts := append([]Option[TModel]{withAdapter[TModel](b.adapter)}, b.opts...)
+	if d := b.adapter.Dialect(); d != nil && d != dialect.PostgreSQL {
+		opts = append([]Option[TModel]{withDialect[TModel](d)}, opts...)
+	}
+	return newRepository(exec, b.table, b.columnBuilderFn, opts...)
 }
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
 	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/executor/adapters/databasesql"
 	"github.com/insei/gerpo/query"
@@ -208,3 +211,50 @@ func TestBuilder_WithErrorTransformer(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Readers(t *testing.T) {
+	writerDB, writerMock, err := sqlmock.New()
+	require.NoError(t, err)
+	readerDB, readerMock, err := sqlmock.New()
+	require.NoError(t, err)
+	writer := databasesql.NewAdapter(writerDB)
+
+	repo, err := New[mockModel]().
+		Adapter(writer, executor.WithReaders(databasesql.NewAdapter(readerDB))).
+		Table("users").
+		Columns(func(m *mockModel, columns *ColumnBuilder[mockModel]) {
+			columns.Field(&m.ID)
+			columns.Field(&m.Name)
+		}).
+		Build()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	readerMock.ExpectQuery(`^SELECT users.id, users.name FROM users$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	readerMock.ExpectQuery(`^SELECT count\(\*\) over\(\) AS count FROM users LIMIT 1$`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	writerMock.ExpectExec(`^INSERT INTO users`).WillReturnResult(sqlmock.NewResult(0, 1))
+	writerMock.ExpectQuery(`^SELECT users.id, users.name FROM users LIMIT 1$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "b"))
+	writerMock.ExpectBegin()
+	writerMock.ExpectQuery(`^SELECT users.id, users.name FROM users$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	writerMock.ExpectCommit()
+
+	_, err = repo.GetList(ctx)
+	require.NoError(t, err)
+	_, err = repo.Count(ctx)
+	require.NoError(t, err)
+	require.NoError(t, repo.Insert(ctx, &mockModel{ID: 2, Name: "b"}))
+	_, err = repo.GetFirst(ForcePrimary(ctx))
+	require.NoError(t, err, "ForcePrimary reads from the writer")
+	err = RunInTx(ctx, writer, func(ctx context.Context) error {
+		_, err := repo.GetList(ctx)
+		return err
+	})
+	require.NoError(t, err, "reads in a transaction stay on it")
+
+	require.NoError(t, writerMock.ExpectationsWereMet())
+	require.NoError(t, readerMock.ExpectationsWereMet())
+}
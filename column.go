@@ -90,6 +90,7 @@ func (b *ColumnBuilder[TModel]) build() (types.ColumnsStorage, error) {
 	if len(b.errors) > 0 {
 		return nil, b.errors[0]
 	}
+	var versionField string
 	for _, cb := range b.builders {
 		cl, err := cb.Build()
 		if err != nil {
@@ -105,6 +106,20 @@ func (b *ColumnBuilder[TModel]) build() (types.ColumnsStorage, error) {
 				}
 			}
 		}
+		if cl.IsPrimaryKey() {
+			if table, _ := cl.Table(); table != b.table {
+				return nil, fmt.Errorf("primary key column %s must belong to table %s", cl.GetField().GetStructPath(), b.table)
+			}
+		}
+		if cl.IsVersion() {
+			if table, _ := cl.Table(); table != b.table {
+				return nil, fmt.Errorf("version column %s must belong to table %s", cl.GetField().GetStructPath(), b.table)
+			}
+			if versionField != "" {
+				return nil, fmt.Errorf("table %s has two version columns: %s and %s", b.table, versionField, cl.GetField().GetStructPath())
+			}
+			versionField = cl.GetField().GetStructPath()
+		}
 		b.columns.Add(cl)
 	}
 	return b.columns, nil
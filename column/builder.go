@@ -81,6 +81,26 @@ func (b *Builder) ReturnedOnUpdate() *Builder {
 	return b
 }
 
+// Version marks the column as the optimistic-locking version counter. Update
+// and UpdateMany then match each row on the current version of its model, SET
+// it one higher and write the new value back into the model; a row changed in
+// between is reported as gerpo.ErrStaleVersion instead of being overwritten.
+// The field must be an integer, and a table has at most one version column.
+func (b *Builder) Version() *Builder {
+	b.opts = append(b.opts, WithVersion())
+	return b
+}
+
+// PrimaryKey marks the column as (a part of) the table's primary key. Mark
+// several columns for a composite key; their declaration order is the order
+// key values are passed in to GetByID, GetByIDs and DeleteByID. Update without
+// a WHERE then matches the row on the model's key, and UpdateMany uses the key
+// unless the request sets its own.
+func (b *Builder) PrimaryKey() *Builder {
+	b.opts = append(b.opts, WithPrimaryKey())
+	return b
+}
+
 // Build constructs and returns a types.Column instance based on the field and options configured in the Builder.
 func (b *Builder) Build() (types.Column, error) {
 	return New(b.field, b.opts...)
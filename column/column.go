@@ -3,11 +3,13 @@ package column
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 
 	"github.com/insei/fmap/v3"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/filters"
 	"github.com/insei/gerpo/types"
 )
@@ -71,6 +73,14 @@ func (c *column) IsReturned(action types.SQLAction) bool {
 	return c.base.IsReturned(action)
 }
 
+func (c *column) IsVersion() bool {
+	return c.base.IsVersion()
+}
+
+func (c *column) IsPrimaryKey() bool {
+	return c.base.IsPrimaryKey()
+}
+
 func generateSQLColumnString(opt *options) string {
 	sql := opt.name
 	if opt.table != "" {
@@ -99,7 +109,13 @@ func New(field fmap.Field, opts ...Option) (types.Column, error) {
 	}
 
 	sqlColumnString := generateSQLColumnString(forOpts)
-	base := types.NewColumnBase(field, generateToSQLFn(sqlColumnString, forOpts.alias), types.NewFilterManagerForField(field))
+	toSQLFn := generateToSQLFn(sqlColumnString, forOpts.alias)
+	var quoted *quotedColumn
+	if dialect.NeedsQuote(forOpts.table) || dialect.NeedsQuote(forOpts.name) {
+		quoted = newQuotedColumn(field, forOpts)
+		toSQLFn = quoted.toSQL
+	}
+	base := types.NewColumnBase(field, toSQLFn, types.NewFilterManagerForField(field))
 	c := &column{
 		name:  forOpts.name,
 		table: forOpts.table,
@@ -107,6 +123,9 @@ func New(field fmap.Field, opts ...Option) (types.Column, error) {
 		query: sqlColumnString,
 	}
 	for op, fn := range filters.Registry.Apply(field, sqlColumnString) {
+		if quoted != nil {
+			fn = quoted.filter(op)
+		}
 		c.base.Filters.AddFilterFnArgsRaw(op, fn)
 	}
 	c.base.AllowedActions = []types.SQLAction{types.SQLActionInsert, types.SQLActionSelect, types.SQLActionUpdate,
@@ -115,5 +134,18 @@ func New(field fmap.Field, opts ...Option) (types.Column, error) {
 		return slices.Contains(forOpts.notAvailActions, action)
 	})
 	c.base.ReturnedActions = forOpts.returnedActions
+	if forOpts.version {
+		switch field.GetDereferencedType().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("version column %s must be an integer, got %s", field.GetStructPath(), field.GetType())
+		}
+		if field.GetType().Kind() == reflect.Ptr {
+			return nil, fmt.Errorf("version column %s must not be a pointer", field.GetStructPath())
+		}
+		c.base.Version = true
+	}
+	c.base.PrimaryKey = forOpts.primaryKey
 	return c, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/insei/fmap/v3"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/types"
 )
 
@@ -224,3 +225,52 @@ func TestGenerateToSQLFn(t *testing.T) {
 		})
 	}
 }
+
+func TestNew_Version(t *testing.T) {
+	fields, _ := fmap.Get[TestModel]()
+
+	c, err := New(fields.MustFind("Age"), WithVersion())
+	assert.NoError(t, err)
+	assert.True(t, c.IsVersion())
+
+	c, err = New(fields.MustFind("Age"))
+	assert.NoError(t, err)
+	assert.False(t, c.IsVersion())
+
+	_, err = New(fields.MustFind("Name"), WithVersion())
+	assert.Error(t, err, "a string field cannot be a version counter")
+}
+
+func TestNew_PrimaryKey(t *testing.T) {
+	fields, _ := fmap.Get[TestModel]()
+
+	c, err := New(fields.MustFind("Name"), WithPrimaryKey())
+	assert.NoError(t, err)
+	assert.True(t, c.IsPrimaryKey())
+
+	c, err = New(fields.MustFind("Name"))
+	assert.NoError(t, err)
+	assert.False(t, c.IsPrimaryKey())
+}
+
+func TestNew_QuotesReservedNames(t *testing.T) {
+	fields, _ := fmap.Get[TestModel]()
+	c, err := New(fields.MustFind("Name"), WithTable("shop"), WithColumnName("order"))
+	assert.NoError(t, err)
+
+	pg := context.Background()
+	mysql := dialect.WithContext(pg, dialect.MySQL)
+	assert.Equal(t, `shop."order"`, c.ToSQL(pg))
+	assert.Equal(t, "shop.`order`", c.ToSQL(mysql))
+
+	eq, ok := c.GetFilterFn(types.OperationEQ)
+	assert.True(t, ok)
+	sql, args, err := eq(mysql, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, "shop.`order` = ?", sql)
+	assert.Equal(t, []any{"x"}, args)
+
+	plain, err := New(fields.MustFind("Name"), WithTable("shop"))
+	assert.NoError(t, err)
+	assert.Equal(t, "shop.name", plain.ToSQL(mysql))
+}
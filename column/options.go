@@ -12,6 +12,8 @@ type options struct {
 	name            string
 	notAvailActions []types.SQLAction
 	returnedActions []types.SQLAction
+	version         bool
+	primaryKey      bool
 }
 
 type Option interface {
@@ -87,3 +89,18 @@ func WithReturnedOnUpdate() Option {
 		c.returnedActions = append(c.returnedActions, types.SQLActionUpdate)
 	})
 }
+
+// WithVersion marks the column as the optimistic-locking version counter of
+// the table. The field must be an integer.
+func WithVersion() Option {
+	return columnOptionFn(func(c *options) {
+		c.version = true
+	})
+}
+
+// WithPrimaryKey marks the column as (a part of) the table's primary key.
+func WithPrimaryKey() Option {
+	return columnOptionFn(func(c *options) {
+		c.primaryKey = true
+	})
+}
@@ -0,0 +1,74 @@
+package column
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/insei/fmap/v3"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/filters"
+	"github.com/insei/gerpo/types"
+)
+
+// quotedColumn renders a column whose table or name needs quoting (a reserved
+// word, see dialect.Ident). The quote character depends on the dialect of the
+// request, so the column SQL and the filters built from it are resolved per
+// dialect on first use.
+type quotedColumn struct {
+	field fmap.Field
+	table string
+	name  string
+	alias string
+
+	byDialect sync.Map // dialect name -> *quotedSQL
+}
+
+type quotedSQL struct {
+	sql     string
+	filters map[types.Operation]filters.Filter
+}
+
+func newQuotedColumn(field fmap.Field, opt *options) *quotedColumn {
+	return &quotedColumn{
+		field: field,
+		table: strings.TrimSpace(opt.table),
+		name:  opt.name,
+		alias: strings.TrimSpace(opt.alias),
+	}
+}
+
+func (q *quotedColumn) get(ctx context.Context) *quotedSQL {
+	d := dialect.FromContext(ctx)
+	if v, ok := q.byDialect.Load(d.Name()); ok {
+		return v.(*quotedSQL)
+	}
+	sql := dialect.Ident(d, q.name)
+	if q.table != "" {
+		sql = dialect.Ident(d, q.table) + "." + sql
+	}
+	v, _ := q.byDialect.LoadOrStore(d.Name(), &quotedSQL{sql: sql, filters: filters.Registry.Apply(q.field, sql)})
+	return v.(*quotedSQL)
+}
+
+func (q *quotedColumn) toSQL(ctx context.Context) string {
+	sql := q.get(ctx).sql
+	if q.alias != "" {
+		sql += " AS " + q.alias
+	}
+	return sql
+}
+
+// filter returns the filter of op that resolves the column SQL of the request
+// dialect before rendering.
+func (q *quotedColumn) filter(op types.Operation) filters.Filter {
+	return func(ctx context.Context, value any) (string, []any, error) {
+		fn, ok := q.get(ctx).filters[op]
+		if !ok {
+			return "", nil, fmt.Errorf("filter %s is not available for %s", op, q.field.GetStructPath())
+		}
+		return fn(ctx, value)
+	}
+}
@@ -0,0 +1,464 @@
+// Package dialect describes the SQL flavour of the database behind an
+// adapter: the fragments gerpo emits differently per database and the
+// features it has to emulate where the database lacks them.
+//
+// Every executor.Adapter declares its dialect. The repository binds it to
+// the context of every call (see WithContext); statement renderers and filter
+// templates read it back with FromContext. A context without a dialect means
+// PostgreSQL — the dialect gerpo has always emitted.
+package dialect
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the SQL flavour of a database.
+type Dialect interface {
+	// Name is the human-readable name of the dialect, e.g. "postgresql".
+	Name() string
+	// Quote quotes an identifier; dotted names (schema.table) are quoted part
+	// by part. Renderers go through Ident, which quotes only the identifiers
+	// that need it.
+	Quote(ident string) string
+	// BoolLiteral renders a boolean constant usable as a predicate.
+	BoolLiteral(v bool) string
+	// TextCast wraps a SQL expression — usually a bound "?" — into a cast to
+	// the dialect's text type, so the database can infer the type of a
+	// parameter inside CONCAT / LOWER.
+	TextCast(expr string) string
+	// Lower folds the case of a text expression for the case-insensitive
+	// comparisons (EQFold, NotEQFold).
+	Lower(expr string) string
+	// Like renders a LIKE-family predicate (Contains, StartsWithFold, ...).
+	Like(l Like) string
+	// LimitOffset renders the row-limiting tail of a SELECT, with a leading
+	// space; zero means "not set" for either value, and both zero render "".
+	// ordered reports whether the SELECT has an ORDER BY already, which some
+	// dialects require before they page.
+	LimitOffset(limit, offset uint64, ordered bool) string
+	// CountOver is the window aggregate that reports the size of the whole
+	// filtered set on every row.
+	CountOver() string
+	// Returning renders the trailing clause that hands the given columns of
+	// the written rows back, with a leading space, or "" when the dialect has
+	// none (see SupportsReturning).
+	Returning(cols []string) string
+	// Output renders the same for dialects that hand the columns back in the
+	// middle of the statement — after the column list of an INSERT, after
+	// the SET clause of an UPDATE — or "". A dialect implements at most one
+	// of Returning and Output.
+	Output(cols []string) string
+	// SupportsReturning reports whether INSERT / UPDATE can hand written
+	// columns back. Without it the executor reads server-generated values
+	// back itself (see the executor package).
+	SupportsReturning() bool
+	// SupportsUpdateFrom reports whether UPDATE accepts a FROM clause to join
+	// other rows in. Without it batched updates join them with
+	// UPDATE t JOIN (...) AS v ON ... SET ... instead.
+	SupportsUpdateFrom() bool
+	// SupportsOnConflict reports whether INSERT takes the ON CONFLICT clause
+	// of Upsert and of the OnConflict specs. Without it the repository
+	// rejects them before any SQL is sent.
+	SupportsOnConflict() bool
+	// SupportsConflictConstraint reports whether ON CONFLICT takes a named
+	// constraint as its target (ON CONFLICT ON CONSTRAINT name). Without it
+	// the repository rejects OnConflictConstraint before any SQL is sent.
+	SupportsConflictConstraint() bool
+	// MaxParams is the largest number of bound parameters one statement may
+	// carry; the executor splits batches to stay under it.
+	MaxParams() int
+	// MaxInsertRows is the largest number of rows one INSERT ... VALUES may
+	// list, 0 for no limit of its own.
+	MaxInsertRows() int
+	// Savepoint, RollbackToSavepoint and ReleaseSavepoint render the
+	// statements that set, roll back to and release the savepoint name.
+	// ReleaseSavepoint is "" where savepoints live until the transaction
+	// ends.
+	Savepoint(name string) string
+	RollbackToSavepoint(name string) string
+	ReleaseSavepoint(name string) string
+}
+
+// Like describes a LIKE-family predicate for Dialect.Like: Expr matched
+// against Value with the any-string wildcard in front of it (Prefix), after it
+// (Suffix) or both.
+type Like struct {
+	// Expr is the matched SQL expression, usually a column.
+	Expr string
+	// Value is the SQL of the searched text, usually the bound text cast.
+	Value string
+	// Prefix and Suffix put the wildcard before and after Value: both for
+	// Contains, Suffix for StartsWith, Prefix for EndsWith.
+	Prefix, Suffix bool
+	// Fold asks for a case-insensitive match.
+	Fold bool
+	// Not negates the predicate.
+	Not bool
+}
+
+var (
+	// PostgreSQL is the dialect of PostgreSQL and compatible databases
+	// (CockroachDB, YugabyteDB). It is the default.
+	PostgreSQL Dialect = postgres{}
+	// MySQL is the dialect of MySQL 8.0+.
+	MySQL Dialect = mysql{}
+	// SQLite is the dialect of SQLite 3.35+, the first release with
+	// RETURNING.
+	SQLite Dialect = sqlite{}
+	// SQLServer is the dialect of Microsoft SQL Server 2012+, the first
+	// release with OFFSET / FETCH.
+	SQLServer Dialect = sqlserver{}
+)
+
+type postgres struct{}
+
+func (postgres) Name() string                     { return "postgresql" }
+func (postgres) Quote(ident string) string        { return quote(ident, '"', '"') }
+func (postgres) BoolLiteral(v bool) string        { return strconv.FormatBool(v) }
+func (postgres) TextCast(expr string) string      { return "CAST(" + expr + " AS text)" }
+func (postgres) Lower(expr string) string         { return "LOWER(" + expr + ")" }
+func (postgres) CountOver() string                { return "count(*) over()" }
+func (postgres) SupportsReturning() bool          { return true }
+func (postgres) SupportsUpdateFrom() bool         { return true }
+func (postgres) SupportsOnConflict() bool         { return true }
+func (postgres) SupportsConflictConstraint() bool { return true }
+func (postgres) Output(_ []string) string         { return "" }
+func (postgres) MaxParams() int                   { return 65535 }
+func (postgres) MaxInsertRows() int               { return 0 }
+
+func (postgres) Savepoint(name string) string           { return "SAVEPOINT " + name }
+func (postgres) RollbackToSavepoint(name string) string { return "ROLLBACK TO SAVEPOINT " + name }
+func (postgres) ReleaseSavepoint(name string) string    { return "RELEASE SAVEPOINT " + name }
+
+func (postgres) LimitOffset(limit, offset uint64, _ bool) string {
+	return limitOffset(limit, offset)
+}
+
+func (d postgres) Like(l Like) string { return concatLike(d, l) }
+
+func (postgres) Returning(cols []string) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	return " RETURNING " + strings.Join(cols, ", ")
+}
+
+type mysql struct{}
+
+func (mysql) Name() string                     { return "mysql" }
+func (mysql) Quote(ident string) string        { return quote(ident, '`', '`') }
+func (mysql) BoolLiteral(v bool) string        { return strconv.FormatBool(v) }
+func (mysql) TextCast(expr string) string      { return "CAST(" + expr + " AS CHAR)" }
+func (mysql) Lower(expr string) string         { return "LOWER(" + expr + ")" }
+func (mysql) CountOver() string                { return "count(*) over()" }
+func (mysql) Returning(_ []string) string      { return "" }
+func (mysql) SupportsReturning() bool          { return false }
+func (mysql) SupportsUpdateFrom() bool         { return false }
+func (mysql) SupportsOnConflict() bool         { return false }
+func (mysql) SupportsConflictConstraint() bool { return false }
+func (mysql) Output(_ []string) string         { return "" }
+func (mysql) MaxParams() int                   { return 65535 }
+func (mysql) MaxInsertRows() int               { return 0 }
+
+func (mysql) Savepoint(name string) string           { return "SAVEPOINT " + name }
+func (mysql) RollbackToSavepoint(name string) string { return "ROLLBACK TO SAVEPOINT " + name }
+func (mysql) ReleaseSavepoint(name string) string    { return "RELEASE SAVEPOINT " + name }
+
+// Like matches the case-sensitive variants under a binary collation: the
+// default collation of MySQL 8, utf8mb4_0900_ai_ci, ignores case and accents.
+// The column must be of the utf8mb4 character set.
+func (d mysql) Like(l Like) string {
+	if !l.Fold {
+		l.Expr += " COLLATE utf8mb4_bin"
+	}
+	return concatLike(d, l)
+}
+
+func (mysql) LimitOffset(limit, offset uint64, _ bool) string {
+	// MySQL has no OFFSET without LIMIT; the largest row count stands in for
+	// "all rows", as its manual suggests.
+	if limit == 0 && offset > 0 {
+		limit = 18446744073709551615
+	}
+	return limitOffset(limit, offset)
+}
+
+type sqlite struct{}
+
+func (sqlite) Name() string                     { return "sqlite" }
+func (sqlite) Quote(ident string) string        { return quote(ident, '"', '"') }
+func (sqlite) BoolLiteral(v bool) string        { return strconv.FormatBool(v) }
+func (sqlite) TextCast(expr string) string      { return "CAST(" + expr + " AS TEXT)" }
+func (sqlite) Lower(expr string) string         { return "LOWER(" + expr + ")" }
+func (sqlite) CountOver() string                { return "count(*) over()" }
+func (sqlite) SupportsReturning() bool          { return true }
+func (sqlite) SupportsUpdateFrom() bool         { return true }
+func (sqlite) SupportsOnConflict() bool         { return true }
+func (sqlite) SupportsConflictConstraint() bool { return false }
+func (sqlite) Output(_ []string) string         { return "" }
+func (sqlite) MaxParams() int                   { return 32766 }
+func (sqlite) MaxInsertRows() int               { return 0 }
+
+func (sqlite) Savepoint(name string) string           { return "SAVEPOINT " + name }
+func (sqlite) RollbackToSavepoint(name string) string { return "ROLLBACK TO SAVEPOINT " + name }
+func (sqlite) ReleaseSavepoint(name string) string    { return "RELEASE SAVEPOINT " + name }
+
+func (sqlite) LimitOffset(limit, offset uint64, _ bool) string {
+	// SQLite has no OFFSET without LIMIT; a negative limit means "all rows".
+	if limit == 0 && offset > 0 {
+		return " LIMIT -1 OFFSET " + strconv.FormatUint(offset, 10)
+	}
+	return limitOffset(limit, offset)
+}
+
+func (sqlite) Returning(cols []string) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	return " RETURNING " + strings.Join(cols, ", ")
+}
+
+// Like matches with LIKE only for the fold variants: SQLite's LIKE ignores the
+// case of ASCII letters already, so LOWER would add nothing. The
+// case-sensitive variants use GLOB, whose any-string wildcard is "*".
+func (sqlite) Like(l Like) string {
+	op, wildcard := " GLOB ", "'*'"
+	if l.Fold {
+		op, wildcard = " LIKE ", "'%'"
+	}
+	if l.Not {
+		op = " NOT" + op
+	}
+	pattern := l.Value
+	if l.Prefix {
+		pattern = wildcard + " || " + pattern
+	}
+	if l.Suffix {
+		pattern += " || " + wildcard
+	}
+	return l.Expr + op + pattern
+}
+
+type sqlserver struct{}
+
+func (sqlserver) Name() string                     { return "sqlserver" }
+func (sqlserver) Quote(ident string) string        { return quote(ident, '[', ']') }
+func (sqlserver) TextCast(expr string) string      { return "CAST(" + expr + " AS NVARCHAR(MAX))" }
+func (sqlserver) Lower(expr string) string         { return "LOWER(" + expr + ")" }
+func (sqlserver) CountOver() string                { return "count(*) over()" }
+func (sqlserver) Returning(_ []string) string      { return "" }
+func (sqlserver) SupportsReturning() bool          { return true }
+func (sqlserver) SupportsUpdateFrom() bool         { return true }
+func (sqlserver) SupportsOnConflict() bool         { return false }
+func (sqlserver) SupportsConflictConstraint() bool { return false }
+func (sqlserver) MaxParams() int                   { return 2100 }
+func (sqlserver) MaxInsertRows() int               { return 1000 }
+
+// T-SQL has no RELEASE: a savepoint lives until the transaction ends, and a
+// name set twice rolls back to the latest one.
+func (sqlserver) Savepoint(name string) string           { return "SAVE TRANSACTION " + name }
+func (sqlserver) RollbackToSavepoint(name string) string { return "ROLLBACK TRANSACTION " + name }
+func (sqlserver) ReleaseSavepoint(_ string) string       { return "" }
+
+// BoolLiteral renders a comparison: T-SQL has no boolean literals a WHERE
+// clause accepts.
+func (sqlserver) BoolLiteral(v bool) string {
+	if v {
+		return "1 = 1"
+	}
+	return "1 = 0"
+}
+
+// LimitOffset renders OFFSET / FETCH, which T-SQL accepts only after an
+// ORDER BY; an unordered SELECT gets ORDER BY (SELECT NULL), which keeps the
+// rows in no particular order, as LIMIT does elsewhere.
+func (sqlserver) LimitOffset(limit, offset uint64, ordered bool) string {
+	if limit == 0 && offset == 0 {
+		return ""
+	}
+	sb := strings.Builder{}
+	if !ordered {
+		sb.WriteString(" ORDER BY (SELECT NULL)")
+	}
+	sb.WriteString(" OFFSET ")
+	sb.WriteString(strconv.FormatUint(offset, 10))
+	sb.WriteString(" ROWS")
+	if limit > 0 {
+		sb.WriteString(" FETCH NEXT ")
+		sb.WriteString(strconv.FormatUint(limit, 10))
+		sb.WriteString(" ROWS ONLY")
+	}
+	return sb.String()
+}
+
+// Output renders OUTPUT INSERTED.<col>: the new values of the written rows,
+// for INSERT and UPDATE alike. SQL Server refuses OUTPUT without INTO on a
+// table with enabled triggers.
+func (sqlserver) Output(cols []string) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	return " OUTPUT INSERTED." + strings.Join(cols, ", INSERTED.")
+}
+
+// Like matches the case-sensitive variants under a case-sensitive collation:
+// the default collations of SQL Server ignore case. Note that "[" opens a
+// character class in a T-SQL LIKE pattern, next to "%" and "_".
+func (d sqlserver) Like(l Like) string {
+	if !l.Fold {
+		l.Expr += " COLLATE Latin1_General_CS_AS"
+	}
+	return concatLike(d, l)
+}
+
+// concatLike renders l with LIKE and CONCAT; the fold variants lower both
+// sides.
+func concatLike(d Dialect, l Like) string {
+	parts := make([]string, 0, 3)
+	if l.Prefix {
+		parts = append(parts, "'%'")
+	}
+	parts = append(parts, l.Value)
+	if l.Suffix {
+		parts = append(parts, "'%'")
+	}
+	expr, pattern := l.Expr, "CONCAT("+strings.Join(parts, ", ")+")"
+	if l.Fold {
+		expr, pattern = d.Lower(expr), d.Lower(pattern)
+	}
+	op := " LIKE "
+	if l.Not {
+		op = " NOT LIKE "
+	}
+	return expr + op + pattern
+}
+
+func limitOffset(limit, offset uint64) string {
+	sb := strings.Builder{}
+	if limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.FormatUint(limit, 10))
+	}
+	if offset > 0 {
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(strconv.FormatUint(offset, 10))
+	}
+	return sb.String()
+}
+
+// quote wraps every dot-separated part of ident in left and right, doubling
+// right inside.
+func quote(ident string, left, right byte) string {
+	parts := strings.Split(ident, ".")
+	sb := strings.Builder{}
+	sb.Grow(len(ident) + 2*len(parts))
+	for i, part := range parts {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteByte(left)
+		sb.WriteString(strings.ReplaceAll(part, string(right), string([]byte{right, right})))
+		sb.WriteByte(right)
+	}
+	return sb.String()
+}
+
+// Ident renders an identifier for d: parts that are plain names — a letter or
+// underscore followed by letters, digits, underscores or dollars — and not
+// reserved words are written as they are, so unquoted case folding keeps
+// working; the others are quoted with d.Quote. Dotted names are checked part
+// by part.
+func Ident(d Dialect, ident string) string {
+	if !NeedsQuote(ident) {
+		return ident
+	}
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if needsQuote(part) {
+			parts[i] = d.Quote(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// NeedsQuote reports whether Ident would quote any part of ident. Parts that
+// are quoted already are left alone.
+func NeedsQuote(ident string) bool {
+	if ident == "" {
+		return false
+	}
+	for _, part := range strings.Split(ident, ".") {
+		if needsQuote(part) {
+			return true
+		}
+	}
+	return false
+}
+
+func needsQuote(part string) bool {
+	if part == "" || isQuoted(part) {
+		return false
+	}
+	for i := 0; i < len(part); i++ {
+		c := part[i]
+		switch {
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '$'):
+		default:
+			return true
+		}
+	}
+	_, reserved := reservedWords[strings.ToLower(part)]
+	return reserved
+}
+
+func isQuoted(part string) bool {
+	if len(part) < 2 {
+		return false
+	}
+	switch part[0] {
+	case '"', '`':
+		return part[len(part)-1] == part[0]
+	case '[':
+		return part[len(part)-1] == ']'
+	}
+	return false
+}
+
+// reservedWords are the keywords PostgreSQL, MySQL or SQL Server reserve that
+// tend to turn up as table or column names.
+var reservedWords = map[string]struct{}{
+	"all": {}, "and": {}, "any": {}, "as": {}, "asc": {}, "between": {}, "both": {}, "by": {},
+	"case": {}, "check": {}, "collate": {}, "column": {}, "constraint": {}, "create": {}, "cross": {},
+	"current_date": {}, "current_time": {}, "current_timestamp": {}, "current_user": {},
+	"default": {}, "delete": {}, "desc": {}, "distinct": {}, "drop": {}, "else": {}, "end": {},
+	"except": {}, "exists": {}, "false": {}, "fetch": {}, "for": {}, "foreign": {}, "from": {},
+	"full": {}, "grant": {}, "group": {}, "having": {}, "in": {}, "index": {}, "inner": {},
+	"insert": {}, "intersect": {}, "interval": {}, "into": {}, "is": {}, "join": {}, "key": {},
+	"leading": {}, "left": {}, "like": {}, "limit": {}, "lock": {}, "not": {}, "null": {},
+	"offset": {}, "on": {}, "or": {}, "order": {}, "outer": {}, "over": {}, "primary": {},
+	"range": {}, "read": {}, "references": {}, "returning": {}, "right": {}, "rows": {},
+	"select": {}, "session_user": {}, "set": {}, "table": {}, "then": {}, "to": {},
+	"trailing": {}, "true": {}, "union": {}, "unique": {}, "update": {}, "user": {},
+	"using": {}, "values": {}, "when": {}, "where": {}, "window": {}, "with": {},
+}
+
+type ctxKey struct{}
+
+// WithContext returns a context carrying d.
+func WithContext(ctx context.Context, d Dialect) context.Context {
+	return context.WithValue(ctx, ctxKey{}, d)
+}
+
+// FromContext returns the dialect carried by ctx, PostgreSQL if none.
+func FromContext(ctx context.Context) Dialect {
+	if ctx == nil {
+		return PostgreSQL
+	}
+	if d, ok := ctx.Value(ctxKey{}).(Dialect); ok {
+		return d
+	}
+	return PostgreSQL
+}
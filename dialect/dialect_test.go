@@ -0,0 +1,111 @@
+package dialect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"users"`, PostgreSQL.Quote("users"))
+	assert.Equal(t, `"public"."users"`, PostgreSQL.Quote("public.users"))
+	assert.Equal(t, "`order`", MySQL.Quote("order"))
+	assert.Equal(t, "`we``ird`", MySQL.Quote("we`ird"))
+	assert.Equal(t, "[dbo].[order]", SQLServer.Quote("dbo.order"))
+	assert.Equal(t, "[we]]ird]", SQLServer.Quote("we]ird"))
+}
+
+func TestTextCast(t *testing.T) {
+	assert.Equal(t, "CAST(? AS text)", PostgreSQL.TextCast("?"))
+	assert.Equal(t, "CAST(? AS CHAR)", MySQL.TextCast("?"))
+	assert.Equal(t, "CAST(? AS TEXT)", SQLite.TextCast("?"))
+	assert.Equal(t, "CAST(? AS NVARCHAR(MAX))", SQLServer.TextCast("?"))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, PostgreSQL, FromContext(context.Background()))
+	assert.Equal(t, PostgreSQL, FromContext(nil)) //nolint:staticcheck // nil ctx is tolerated on purpose
+	assert.Equal(t, MySQL, FromContext(WithContext(context.Background(), MySQL)))
+}
+
+func TestIdent(t *testing.T) {
+	assert.Equal(t, "users", Ident(PostgreSQL, "users"))
+	assert.Equal(t, "Users", Ident(PostgreSQL, "Users"), "plain names keep unquoted case folding")
+	assert.Equal(t, "public.users", Ident(PostgreSQL, "public.users"))
+	assert.Equal(t, `"order"`, Ident(PostgreSQL, "order"))
+	assert.Equal(t, "shop.`order`", Ident(MySQL, "shop.order"))
+	assert.Equal(t, `"my-table"`, Ident(PostgreSQL, "my-table"))
+	assert.Equal(t, `"2fa"`, Ident(PostgreSQL, "2fa"))
+	assert.Equal(t, `"order"`, Ident(MySQL, `"order"`), "quoted parts are left alone")
+	assert.Equal(t, "dbo.[user]", Ident(SQLServer, "dbo.user"))
+}
+
+func TestLimitOffset(t *testing.T) {
+	assert.Equal(t, "", PostgreSQL.LimitOffset(0, 0, false))
+	assert.Equal(t, " LIMIT 10 OFFSET 20", PostgreSQL.LimitOffset(10, 20, false))
+	assert.Equal(t, " OFFSET 20", PostgreSQL.LimitOffset(0, 20, false))
+	assert.Equal(t, " LIMIT 18446744073709551615 OFFSET 20", MySQL.LimitOffset(0, 20, false))
+	assert.Equal(t, " LIMIT 1", MySQL.LimitOffset(1, 0, false))
+	assert.Equal(t, " LIMIT -1 OFFSET 20", SQLite.LimitOffset(0, 20, false))
+	assert.Equal(t, " LIMIT 10 OFFSET 20", SQLite.LimitOffset(10, 20, false))
+	assert.Equal(t, "", SQLServer.LimitOffset(0, 0, false))
+	assert.Equal(t, " ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY", SQLServer.LimitOffset(1, 0, false))
+	assert.Equal(t, " OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", SQLServer.LimitOffset(10, 20, true))
+	assert.Equal(t, " OFFSET 20 ROWS", SQLServer.LimitOffset(0, 20, true))
+}
+
+func TestReturning(t *testing.T) {
+	assert.Equal(t, " RETURNING id, created_at", PostgreSQL.Returning([]string{"id", "created_at"}))
+	assert.Equal(t, "", PostgreSQL.Returning(nil))
+	assert.Equal(t, "", MySQL.Returning([]string{"id"}))
+	assert.Equal(t, " RETURNING id", SQLite.Returning([]string{"id"}))
+	assert.Equal(t, "", SQLServer.Returning([]string{"id"}))
+}
+
+func TestSupportsOnConflict(t *testing.T) {
+	assert.True(t, PostgreSQL.SupportsOnConflict())
+	assert.True(t, SQLite.SupportsOnConflict())
+	assert.False(t, MySQL.SupportsOnConflict())
+	assert.False(t, SQLServer.SupportsOnConflict())
+}
+
+func TestSupportsConflictConstraint(t *testing.T) {
+	assert.True(t, PostgreSQL.SupportsConflictConstraint())
+	assert.False(t, SQLite.SupportsConflictConstraint())
+	assert.False(t, MySQL.SupportsConflictConstraint())
+	assert.False(t, SQLServer.SupportsConflictConstraint())
+}
+
+func TestOutput(t *testing.T) {
+	assert.Equal(t, " OUTPUT INSERTED.id, INSERTED.created_at", SQLServer.Output([]string{"id", "created_at"}))
+	assert.Equal(t, "", SQLServer.Output(nil))
+	assert.Equal(t, "", PostgreSQL.Output([]string{"id"}))
+}
+
+func TestLike(t *testing.T) {
+	contains := Like{Expr: "title", Value: "?", Prefix: true, Suffix: true}
+	startsWithFold := Like{Expr: "title", Value: "?", Suffix: true, Fold: true}
+	notEndsWith := Like{Expr: "title", Value: "?", Prefix: true, Not: true}
+
+	assert.Equal(t, "title LIKE CONCAT('%', ?, '%')", PostgreSQL.Like(contains))
+	assert.Equal(t, "LOWER(title) LIKE LOWER(CONCAT(?, '%'))", PostgreSQL.Like(startsWithFold))
+	assert.Equal(t, "title COLLATE utf8mb4_bin NOT LIKE CONCAT('%', ?)", MySQL.Like(notEndsWith))
+	assert.Equal(t, "LOWER(title) LIKE LOWER(CONCAT(?, '%'))", MySQL.Like(startsWithFold))
+
+	assert.Equal(t, "title GLOB '*' || ? || '*'", SQLite.Like(contains))
+	assert.Equal(t, "title LIKE ? || '%'", SQLite.Like(startsWithFold))
+	assert.Equal(t, "title NOT GLOB '*' || ?", SQLite.Like(notEndsWith))
+
+	assert.Equal(t, "title COLLATE Latin1_General_CS_AS LIKE CONCAT('%', ?, '%')", SQLServer.Like(contains))
+	assert.Equal(t, "LOWER(title) LIKE LOWER(CONCAT(?, '%'))", SQLServer.Like(startsWithFold))
+}
+
+func TestSavepoint(t *testing.T) {
+	assert.Equal(t, "SAVEPOINT sp", PostgreSQL.Savepoint("sp"))
+	assert.Equal(t, "ROLLBACK TO SAVEPOINT sp", SQLite.RollbackToSavepoint("sp"))
+	assert.Equal(t, "RELEASE SAVEPOINT sp", MySQL.ReleaseSavepoint("sp"))
+	assert.Equal(t, "SAVE TRANSACTION sp", SQLServer.Savepoint("sp"))
+	assert.Equal(t, "ROLLBACK TRANSACTION sp", SQLServer.RollbackToSavepoint("sp"))
+	assert.Equal(t, "", SQLServer.ReleaseSavepoint("sp"))
+}
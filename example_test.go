@@ -253,6 +253,48 @@ func ExampleRunInTx() {
 	}
 }
 
+// ExampleRunInSavepoint undoes part of a transaction: an error inside the
+// savepoint rolls back only the work done there. A RunInTx nested in another
+// one takes the same path on its own.
+func ExampleRunInSavepoint() {
+	adapter := exampleAdapter()
+	users := exampleRepo()
+
+	err := gerpo.RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+		if err := users.Insert(ctx, &User{ID: uuid.New(), Name: "Alice"}); err != nil {
+			return err
+		}
+		// A failed insert of Bob leaves Alice in place.
+		_ = gerpo.RunInSavepoint(ctx, func(ctx context.Context) error {
+			return users.Insert(ctx, &User{ID: uuid.New(), Name: "Bob"})
+		})
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ExampleOnCommit defers a side effect until the transaction commits: a
+// rolled-back insert publishes nothing.
+func ExampleOnCommit() {
+	adapter := exampleAdapter()
+	users := exampleRepo()
+
+	err := gerpo.RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+		user := &User{ID: uuid.New(), Name: "Alice"}
+		if err := users.Insert(ctx, user); err != nil {
+			return err
+		}
+		return gerpo.OnCommit(ctx, func() {
+			fmt.Println("user created", user.ID)
+		})
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
 // ExampleWithTracer wires gerpo into an OpenTelemetry-style tracer without
 // pulling the OTel package into the gerpo dependency set. The tracer receives
 // SpanInfo with the operation name (e.g. "gerpo.GetFirst") and the bound table.
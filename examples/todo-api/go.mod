@@ -5,6 +5,7 @@ go 1.24.0
 replace github.com/insei/gerpo => ../..
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/google/uuid v1.6.0
 	github.com/insei/gerpo v0.0.0-00010101000000-000000000000
 	github.com/jackc/pgx/v5 v5.7.4
@@ -13,7 +14,11 @@ require (
 
 require (
 	github.com/insei/fmap/v3 v3.1.2 // indirect
+	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
+	github.com/jackc/pgconn v1.14.3 // indirect
+	github.com/jackc/pgio v1.0.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgproto3/v2 v2.3.3 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
@@ -9,13 +9,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/insei/gerpo"
 )
 
 // Handler exposes the Service over a small JSON REST surface.
 //
 // Routes:
 //   POST   /tasks          — create
-//   GET    /tasks          — list (?page, ?size, ?done=true|false)
+//   GET    /tasks          — list (?page | ?cursor, ?size, ?done=true|false)
 //   GET    /tasks/{id}     — fetch one
 //   PATCH  /tasks/{id}     — partial update
 //   DELETE /tasks/{id}     — delete
@@ -105,6 +106,7 @@ func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
 		}
 		p.Size = v
 	}
+	p.Cursor = q.Get("cursor")
 	if s := q.Get("done"); s != "" {
 		v, err := strconv.ParseBool(s)
 		if err != nil {
@@ -114,11 +116,19 @@ func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
 		p.Done = &v
 	}
 
-	tasks, err := h.svc.List(r.Context(), p)
+	tasks, next, err := h.svc.List(r.Context(), p)
+	if errors.Is(err, gerpo.ErrInvalidCursor) {
+		writeErr(w, http.StatusBadRequest, "invalid cursor")
+		return
+	}
 	if err != nil {
 		writeErr(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// Keyset pagination: the client passes the header back as ?cursor=.
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
 	out := make([]taskDTO, 0, len(tasks))
 	for _, t := range tasks {
 		out = append(out, toDTO(t))
@@ -23,26 +23,39 @@ func NewService(repo gerpo.Repository[Task], adapter executor.Adapter) *Service
 }
 
 // ListParams holds optional list filters. Zero values mean "no filter".
+// Page beyond the first switches the listing to page numbers; otherwise it
+// is keyset-paginated and Cursor continues from a previous page.
 type ListParams struct {
-	Page uint64
-	Size uint64
-	Done *bool // nil = any status
+	Page   uint64
+	Size   uint64
+	Cursor string
+	Done   *bool // nil = any status
 }
 
-func (s *Service) List(ctx context.Context, p ListParams) ([]*Task, error) {
-	return s.repo.GetList(ctx, func(m *Task, h query.GetListHelper[Task]) {
+// List returns one page of tasks and, for keyset listings, the cursor of the
+// next page ("" on the last one).
+func (s *Service) List(ctx context.Context, p ListParams) ([]*Task, string, error) {
+	var next string
+	tasks, err := s.repo.GetList(ctx, func(m *Task, h query.GetListHelper[Task]) {
 		if p.Done != nil {
 			h.Where().Field(&m.Done).EQ(*p.Done)
 		}
-		h.OrderBy().Field(&m.CreatedAt).DESC()
+		// ID breaks CreatedAt ties so keyset pages never skip a row.
+		h.OrderBy().Field(&m.CreatedAt).DESC().Field(&m.ID).ASC()
 		if p.Size == 0 {
 			p.Size = 20
 		}
-		if p.Page == 0 {
-			p.Page = 1
+		h.Size(p.Size)
+		// Page cannot be combined with a cursor. The first page is the same
+		// either way, so it is served as a keyset page and hands out a cursor.
+		if p.Cursor == "" && p.Page > 1 {
+			h.Page(p.Page)
+			return
 		}
-		h.Page(p.Page).Size(p.Size)
+		h.After(p.Cursor)
+		h.NextCursor(&next)
 	})
+	return tasks, next, err
 }
 
 func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Task, error) {
@@ -0,0 +1,69 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+)
+
+func TestService_List(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	adapter := databasesql.NewAdapter(db)
+	repo, err := NewRepository(adapter)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when building repository", err)
+	}
+	svc := NewService(repo, adapter)
+
+	columns := []string{"id", "created_at", "updated_at", "title", "description", "done"}
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := uuid.New()
+
+	// The first page is a keyset page: no cursor condition, a full page hands
+	// out the cursor of the next one.
+	mockDB.ExpectQuery(`SELECT tasks.id, tasks.created_at, tasks.updated_at, tasks.title, tasks.description, tasks.done FROM tasks ORDER BY tasks.created_at DESC, tasks.id ASC LIMIT 2$`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), createdAt.Add(time.Hour), nil, "a", "", false).
+			AddRow(last, createdAt, nil, "b", "", false))
+	tasks, next, err := svc.List(context.Background(), ListParams{Size: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(tasks) != 2 || next == "" {
+		t.Fatalf("expected a full first page with a next cursor, got %d tasks and cursor %q", len(tasks), next)
+	}
+
+	// The cursor continues after the last row of the first page.
+	mockDB.ExpectQuery(`SELECT .* FROM tasks WHERE \(\(tasks.created_at < \?\) OR \(tasks.created_at = \? AND tasks.id > \?\)\) ORDER BY tasks.created_at DESC, tasks.id ASC LIMIT 2$`).
+		WithArgs(createdAt, createdAt, last).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), createdAt.Add(-time.Hour), nil, "c", "", true))
+	tasks, next, err = svc.List(context.Background(), ListParams{Size: 2, Cursor: next})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(tasks) != 1 || next != "" {
+		t.Fatalf("expected a short last page without a cursor, got %d tasks and cursor %q", len(tasks), next)
+	}
+
+	// A later page number falls back to LIMIT / OFFSET.
+	mockDB.ExpectQuery(`SELECT .* FROM tasks ORDER BY tasks.created_at DESC, tasks.id ASC LIMIT 2 OFFSET 2$`).
+		WillReturnRows(sqlmock.NewRows(columns))
+	if _, next, err = svc.List(context.Background(), ListParams{Page: 2, Size: 2}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if next != "" {
+		t.Fatalf("expected no cursor for a numbered page, got %q", next)
+	}
+
+	if err = mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
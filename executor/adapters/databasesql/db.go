@@ -3,7 +3,9 @@ package databasesql
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor/adapters/internal"
 	"github.com/insei/gerpo/executor/adapters/placeholder"
 	extypes "github.com/insei/gerpo/executor/types"
@@ -24,14 +26,26 @@ func (b *dbDriver) Query(ctx context.Context, sql string, args ...any) (extypes.
 	return b.db.QueryContext(ctx, sql, args...)
 }
 
-func (b *dbDriver) BeginTx(ctx context.Context) (internal.TxDriver, error) {
-	tx, err := b.db.BeginTx(ctx, nil)
+func (b *dbDriver) BeginTx(ctx context.Context, opts extypes.TxOptions) (internal.TxDriver, error) {
+	// database/sql has no deferrable mode.
+	if opts.Deferrable {
+		return nil, fmt.Errorf("databasesql: deferrable transactions are not supported")
+	}
+	tx, err := b.db.BeginTx(ctx, &sql.TxOptions{Isolation: isolationLevels[opts.Isolation], ReadOnly: opts.ReadOnly})
 	if err != nil {
 		return nil, err
 	}
 	return &txDriver{tx: tx}, nil
 }
 
+var isolationLevels = map[extypes.IsolationLevel]sql.IsolationLevel{
+	extypes.LevelDefault:         sql.LevelDefault,
+	extypes.LevelReadUncommitted: sql.LevelReadUncommitted,
+	extypes.LevelReadCommitted:   sql.LevelReadCommitted,
+	extypes.LevelRepeatableRead:  sql.LevelRepeatableRead,
+	extypes.LevelSerializable:    sql.LevelSerializable,
+}
+
 // txDriver implements internal.TxDriver on top of *sql.Tx.
 type txDriver struct {
 	tx *sql.Tx
@@ -51,15 +65,36 @@ func (t *txDriver) Rollback() error { return t.tx.Rollback() }
 // adapterConfig collects the optional knobs for NewAdapter.
 type adapterConfig struct {
 	placeholder placeholder.PlaceholderFormat
+	dialect     dialect.Dialect
 }
 
 // NewAdapter wraps a database/sql DB with the gerpo DB adapter contract.
 // The placeholder format defaults to `?` (MySQL); use WithPlaceholder to
-// switch to `$1, $2, …` for PostgreSQL.
+// switch to `$1, $2, …` for PostgreSQL. The SQL dialect defaults to
+// PostgreSQL; use WithDialect, NewMySQLAdapter or NewSQLServerAdapter for
+// the others.
 func NewAdapter(db *sql.DB, opts ...Option) extypes.Adapter {
-	cfg := adapterConfig{placeholder: placeholder.Question}
+	cfg := adapterConfig{placeholder: placeholder.Question, dialect: dialect.PostgreSQL}
 	for _, opt := range opts {
 		opt.apply(&cfg)
 	}
-	return internal.New(&dbDriver{db: db}, cfg.placeholder)
+	return internal.New(&dbDriver{db: db}, cfg.placeholder, cfg.dialect)
+}
+
+// NewMySQLAdapter is the MySQL 8.0+ preset of NewAdapter: `?` placeholders
+// and the MySQL dialect. Open the *sql.DB with clientFoundRows=true
+// (go-sql-driver/mysql) — MySQL otherwise reports changed rather than matched
+// rows, and an UPDATE that rewrites identical values would surface as
+// ErrNotFound.
+func NewMySQLAdapter(db *sql.DB, opts ...Option) extypes.Adapter {
+	preset := []Option{WithPlaceholder(placeholder.Question), WithDialect(dialect.MySQL)}
+	return NewAdapter(db, append(preset, opts...)...)
+}
+
+// NewSQLServerAdapter is the SQL Server 2012+ preset of NewAdapter: `@p1`
+// placeholders and the SQL Server dialect, for the "sqlserver" driver of
+// github.com/microsoft/go-mssqldb.
+func NewSQLServerAdapter(db *sql.DB, opts ...Option) extypes.Adapter {
+	preset := []Option{WithPlaceholder(placeholder.AtP), WithDialect(dialect.SQLServer)}
+	return NewAdapter(db, append(preset, opts...)...)
 }
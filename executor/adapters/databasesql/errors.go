@@ -0,0 +1,131 @@
+package databasesql
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+
+	pgconnv4 "github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/insei/gerpo/executor/adapters/internal"
+	extypes "github.com/insei/gerpo/executor/types"
+)
+
+// ConstraintViolation implements internal.ViolationDriver for the errors of
+// github.com/jackc/pgx/v5/stdlib and github.com/jackc/pgx/v4/stdlib
+// (*pgconn.PgError of pgx v5 and of github.com/jackc/pgconn), github.com/lib/pq
+// (*pq.Error, read through its Get method), modernc.org/sqlite
+// (*sqlite.Error), github.com/go-sql-driver/mysql (*mysql.MySQLError, read
+// through its fields) and github.com/microsoft/go-mssqldb (mssql.Error, read
+// through its SQLErrorNumber and SQLErrorMessage methods). Other drivers'
+// errors pass through as they are.
+func (b *dbDriver) ConstraintViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return internal.PGViolation(err, internal.PGError{
+			Code:       pgErr.Code,
+			Constraint: pgErr.ConstraintName,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			Detail:     pgErr.Detail,
+		})
+	}
+	var pgErrV4 *pgconnv4.PgError
+	if errors.As(err, &pgErrV4) {
+		return internal.PGViolation(err, internal.PGError{
+			Code:       pgErrV4.Code,
+			Constraint: pgErrV4.ConstraintName,
+			Table:      pgErrV4.TableName,
+			Column:     pgErrV4.ColumnName,
+			Detail:     pgErrV4.Detail,
+		})
+	}
+	var pqErr interface{ Get(k byte) string }
+	if errors.As(err, &pqErr) {
+		return internal.PGViolation(err, internal.PGError{
+			Code:       pqErr.Get('C'),
+			Constraint: pqErr.Get('n'),
+			Table:      pqErr.Get('t'),
+			Column:     pqErr.Get('c'),
+			Detail:     pqErr.Get('D'),
+		})
+	}
+	var mssqlErr interface {
+		SQLErrorNumber() int32
+		SQLErrorMessage() string
+	}
+	if errors.As(err, &mssqlErr) {
+		return internal.SQLServerViolation(err, int(mssqlErr.SQLErrorNumber()), mssqlErr.SQLErrorMessage())
+	}
+	if number, message, ok := mysqlError(err); ok {
+		return internal.MySQLViolation(err, number, message)
+	}
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		return sqliteViolation(err, sqliteErr.Code())
+	}
+	return nil
+}
+
+// mysqlError reads the Number and Message fields of the *MySQLError of
+// go-sql-driver/mysql in the chain of err; the type has no methods to read
+// them through, and importing the driver would tie every user of this
+// adapter to it.
+func mysqlError(err error) (number int, message string, ok bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if v.Elem().Type().Name() != "MySQLError" {
+			continue
+		}
+		n, m := v.Elem().FieldByName("Number"), v.Elem().FieldByName("Message")
+		if n.Kind() == reflect.Uint16 && m.Kind() == reflect.String {
+			return int(n.Uint()), m.String(), true
+		}
+	}
+	return 0, "", false
+}
+
+// sqliteConstraintKinds maps the extended result codes of SQLite constraint
+// violations to their kinds.
+var sqliteConstraintKinds = map[int]extypes.ConstraintKind{
+	275:  extypes.ConstraintCheck,      // SQLITE_CONSTRAINT_CHECK
+	787:  extypes.ConstraintForeignKey, // SQLITE_CONSTRAINT_FOREIGNKEY
+	1299: extypes.ConstraintNotNull,    // SQLITE_CONSTRAINT_NOTNULL
+	1555: extypes.ConstraintUnique,     // SQLITE_CONSTRAINT_PRIMARYKEY
+	2067: extypes.ConstraintUnique,     // SQLITE_CONSTRAINT_UNIQUE
+}
+
+// sqliteFailed matches what SQLite says about the failed constraint —
+// "t.a, t.b" for keys and NOT NULL, the name or the expression for CHECK —
+// without the " (2067)" modernc appends.
+var sqliteFailed = regexp.MustCompile(`(?:UNIQUE|NOT NULL|CHECK) constraint failed: (.+?)(?: \(\d+\))?$`)
+
+// sqliteViolation returns err as a violation type when code is a constraint
+// violation, nil otherwise. SQLite names no constraint but CHECK ones and
+// nothing at all for foreign keys.
+func sqliteViolation(err error, code int) error {
+	kind, ok := sqliteConstraintKinds[code]
+	if !ok {
+		return nil
+	}
+	v := extypes.ConstraintViolation{Err: err}
+	if m := sqliteFailed.FindStringSubmatch(err.Error()); m != nil {
+		if kind == extypes.ConstraintCheck {
+			v.Constraint = m[1]
+		} else {
+			var columns []string
+			for _, qualified := range strings.Split(m[1], ", ") {
+				table, column, _ := strings.Cut(qualified, ".")
+				v.Table = table
+				columns = append(columns, column)
+			}
+			v.Column = strings.Join(columns, ", ")
+		}
+	}
+	return extypes.NewConstraintViolation(kind, v)
+}
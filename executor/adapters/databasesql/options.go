@@ -1,6 +1,9 @@
 package databasesql
 
-import "github.com/insei/gerpo/executor/adapters/placeholder"
+import (
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/executor/adapters/placeholder"
+)
 
 // Option tunes how NewAdapter wires the underlying *sql.DB.
 type Option interface {
@@ -18,3 +21,12 @@ func WithPlaceholder(format placeholder.PlaceholderFormat) Option {
 		cfg.placeholder = format
 	})
 }
+
+// WithDialect sets the SQL dialect of the database behind the *sql.DB. The
+// default is dialect.PostgreSQL; NewMySQLAdapter and NewSQLServerAdapter set
+// dialect.MySQL and dialect.SQLServer.
+func WithDialect(d dialect.Dialect) Option {
+	return optionFn(func(cfg *adapterConfig) {
+		cfg.dialect = d
+	})
+}
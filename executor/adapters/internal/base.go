@@ -10,7 +10,9 @@ package internal
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor/adapters/placeholder"
 	extypes "github.com/insei/gerpo/executor/types"
 )
@@ -22,7 +24,7 @@ import (
 type Driver interface {
 	Exec(ctx context.Context, sql string, args ...any) (extypes.Result, error)
 	Query(ctx context.Context, sql string, args ...any) (extypes.Rows, error)
-	BeginTx(ctx context.Context) (TxDriver, error)
+	BeginTx(ctx context.Context, opts extypes.TxOptions) (TxDriver, error)
 }
 
 // TxDriver mirrors Driver minus BeginTx, plus Commit / Rollback. The wrapping
@@ -35,19 +37,62 @@ type TxDriver interface {
 	Rollback() error
 }
 
+// CopyDriver is the optional COPY FROM capability of a Driver or TxDriver.
+// When the driver implements it, the wrapping Adapter / Tx expose
+// executor.types.Copier; otherwise they do not, so the capability check in
+// the executor stays a plain type assertion. COPY carries no placeholders,
+// nothing is rewritten on the way down.
+type CopyDriver interface {
+	CopyFrom(ctx context.Context, table string, columns []string, src extypes.CopySource) (int64, error)
+}
+
+// ViolationDriver is the optional capability of a Driver to recognise
+// constraint violations among its errors. When the driver implements it, the
+// wrapping Adapter and its transactions hand them back as the violation types
+// of executor.types — from statements, rows, COPY and Commit alike.
+type ViolationDriver interface {
+	// ConstraintViolation returns err as a violation type, nil when err is
+	// not a constraint violation.
+	ConstraintViolation(err error) error
+}
+
 // Adapter is the executor.types.Adapter implementation shared by every
 // bundled adapter. It rewrites placeholders before each driver call and wraps
 // transactions in a state machine that makes RollbackUnlessCommitted safe to
-// use as a defer.
+// use as a defer and runs the OnCommit / OnRollback callbacks registered on
+// them. It reports the dialect it was built with.
 type Adapter struct {
 	driver      Driver
 	placeholder placeholder.PlaceholderFormat
+	dialect     dialect.Dialect
+	violations  ViolationDriver
 }
 
 // New constructs an Adapter that runs every SQL statement through the given
 // placeholder format before handing it over to the driver.
-func New(driver Driver, p placeholder.PlaceholderFormat) extypes.Adapter {
-	return &Adapter{driver: driver, placeholder: p}
+func New(driver Driver, p placeholder.PlaceholderFormat, d dialect.Dialect) extypes.Adapter {
+	a := &Adapter{driver: driver, placeholder: p, dialect: d}
+	a.violations, _ = driver.(ViolationDriver)
+	if c, ok := driver.(CopyDriver); ok {
+		return &copyAdapter{Adapter: a, copier: c}
+	}
+	return a
+}
+
+// copyAdapter is the Adapter of a driver that supports COPY FROM.
+type copyAdapter struct {
+	*Adapter
+	copier CopyDriver
+}
+
+func (a *copyAdapter) CopyFrom(ctx context.Context, table string, columns []string, src extypes.CopySource) (int64, error) {
+	n, err := a.copier.CopyFrom(ctx, table, columns, src)
+	return n, violation(a.violations, err)
+}
+
+// Dialect returns the SQL dialect of the database behind the driver.
+func (a *Adapter) Dialect() dialect.Dialect {
+	return a.dialect
 }
 
 func (a *Adapter) ExecContext(ctx context.Context, sql string, args ...any) (extypes.Result, error) {
@@ -55,7 +100,8 @@ func (a *Adapter) ExecContext(ctx context.Context, sql string, args ...any) (ext
 	if err != nil {
 		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
 	}
-	return a.driver.Exec(ctx, rewritten, args...)
+	res, err := a.driver.Exec(ctx, rewritten, args...)
+	return res, violation(a.violations, err)
 }
 
 func (a *Adapter) QueryContext(ctx context.Context, sql string, args ...any) (extypes.Rows, error) {
@@ -63,26 +109,53 @@ func (a *Adapter) QueryContext(ctx context.Context, sql string, args ...any) (ex
 	if err != nil {
 		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
 	}
-	return a.driver.Query(ctx, rewritten, args...)
+	rows, err := a.driver.Query(ctx, rewritten, args...)
+	return violationRows(a.violations, rows, err)
 }
 
 func (a *Adapter) BeginTx(ctx context.Context) (extypes.Tx, error) {
-	inner, err := a.driver.BeginTx(ctx)
+	return a.BeginTxWithOptions(ctx, extypes.TxOptions{})
+}
+
+// BeginTxWithOptions opens a transaction with opts; it makes every bundled
+// adapter an executor.types.TxOptionsBeginner.
+func (a *Adapter) BeginTxWithOptions(ctx context.Context, opts extypes.TxOptions) (extypes.Tx, error) {
+	inner, err := a.driver.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &transaction{
+	tx := &transaction{
 		inner:                         inner,
 		placeholder:                   a.placeholder,
+		violations:                    a.violations,
 		rollbackUnlessCommittedNeeded: true,
-	}, nil
+	}
+	if c, ok := inner.(CopyDriver); ok {
+		return &copyTransaction{transaction: tx, copier: c}, nil
+	}
+	return tx, nil
 }
 
+// Transaction outcomes, reported by transaction.ended.
+const (
+	txActive = iota
+	txCommitted
+	txRolledBack
+)
+
 type transaction struct {
 	inner                         TxDriver
 	placeholder                   placeholder.PlaceholderFormat
+	violations                    ViolationDriver
 	committed                     bool
 	rollbackUnlessCommittedNeeded bool
+
+	// mu guards the callbacks: hooks may register them from several
+	// goroutines sharing the tx ctx.
+	mu         sync.Mutex
+	ended      int
+	onCommit   []func()
+	onRollback []func()
 }
 
 func (t *transaction) ExecContext(ctx context.Context, sql string, args ...any) (extypes.Result, error) {
@@ -90,7 +163,8 @@ func (t *transaction) ExecContext(ctx context.Context, sql string, args ...any)
 	if err != nil {
 		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
 	}
-	return t.inner.Exec(ctx, rewritten, args...)
+	res, err := t.inner.Exec(ctx, rewritten, args...)
+	return res, violation(t.violations, err)
 }
 
 func (t *transaction) QueryContext(ctx context.Context, sql string, args ...any) (extypes.Rows, error) {
@@ -98,20 +172,80 @@ func (t *transaction) QueryContext(ctx context.Context, sql string, args ...any)
 	if err != nil {
 		return nil, fmt.Errorf("failed to replace placeholders: %w", err)
 	}
-	return t.inner.Query(ctx, rewritten, args...)
+	rows, err := t.inner.Query(ctx, rewritten, args...)
+	return violationRows(t.violations, rows, err)
 }
 
 func (t *transaction) Commit() error {
 	if err := t.inner.Commit(); err != nil {
-		return err
+		// A DEFERRABLE constraint is checked at commit.
+		return violation(t.violations, err)
 	}
 	t.committed = true
+	t.end(txCommitted)
 	return nil
 }
 
+// Rollback runs the OnRollback callbacks even when the driver reports an
+// error: the transaction did not commit either way.
 func (t *transaction) Rollback() error {
 	t.rollbackUnlessCommittedNeeded = false
-	return t.inner.Rollback()
+	err := t.inner.Rollback()
+	t.end(txRolledBack)
+	return err
+}
+
+// OnCommit registers fn to run after a successful Commit. Registered on a
+// transaction that has committed already, fn runs right away; on one that
+// was rolled back, never.
+func (t *transaction) OnCommit(fn func()) {
+	t.register(txCommitted, fn)
+}
+
+// OnRollback registers fn to run after Rollback. Registered on a transaction
+// that was rolled back already, fn runs right away; on one that has
+// committed, never.
+func (t *transaction) OnRollback(fn func()) {
+	t.register(txRolledBack, fn)
+}
+
+func (t *transaction) register(outcome int, fn func()) {
+	if fn == nil {
+		return
+	}
+	t.mu.Lock()
+	switch {
+	case t.ended == outcome:
+		t.mu.Unlock()
+		fn()
+		return
+	case t.ended != txActive:
+	case outcome == txCommitted:
+		t.onCommit = append(t.onCommit, fn)
+	default:
+		t.onRollback = append(t.onRollback, fn)
+	}
+	t.mu.Unlock()
+}
+
+// end records the outcome of the transaction and runs the callbacks waiting
+// for it; the others are dropped. Only the first outcome counts.
+func (t *transaction) end(outcome int) {
+	t.mu.Lock()
+	if t.ended != txActive {
+		t.mu.Unlock()
+		return
+	}
+	t.ended = outcome
+	callbacks := t.onCommit
+	if outcome == txRolledBack {
+		callbacks = t.onRollback
+	}
+	t.onCommit, t.onRollback = nil, nil
+	t.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
 }
 
 func (t *transaction) RollbackUnlessCommitted() error {
@@ -120,3 +254,53 @@ func (t *transaction) RollbackUnlessCommitted() error {
 	}
 	return nil
 }
+
+// copyTransaction is the transaction of a TxDriver that supports COPY FROM.
+type copyTransaction struct {
+	*transaction
+	copier CopyDriver
+}
+
+func (t *copyTransaction) CopyFrom(ctx context.Context, table string, columns []string, src extypes.CopySource) (int64, error) {
+	n, err := t.copier.CopyFrom(ctx, table, columns, src)
+	return n, violation(t.violations, err)
+}
+
+// violation hands err back as a violation type when v recognises it, as is
+// otherwise.
+func violation(v ViolationDriver, err error) error {
+	if err == nil || v == nil {
+		return err
+	}
+	if verr := v.ConstraintViolation(err); verr != nil {
+		return verr
+	}
+	return err
+}
+
+// violationRows wraps rows so the errors they report pass through v: PG
+// drivers report the violation of an INSERT ... RETURNING while the rows are
+// read, not from Query.
+func violationRows(v ViolationDriver, rows extypes.Rows, err error) (extypes.Rows, error) {
+	if err != nil || v == nil {
+		return rows, violation(v, err)
+	}
+	return &rowsViolations{Rows: rows, violations: v}, nil
+}
+
+type rowsViolations struct {
+	extypes.Rows
+	violations ViolationDriver
+}
+
+func (r *rowsViolations) Scan(dest ...any) error {
+	return violation(r.violations, r.Rows.Scan(dest...))
+}
+
+func (r *rowsViolations) Err() error {
+	return violation(r.violations, r.Rows.Err())
+}
+
+func (r *rowsViolations) Close() error {
+	return violation(r.violations, r.Rows.Close())
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor/adapters/placeholder"
 	extypes "github.com/insei/gerpo/executor/types"
 
@@ -17,6 +18,7 @@ type fakeDriver struct {
 	execCalls  []callRecord
 	queryCalls []callRecord
 	beginErr   error
+	beginOpts  extypes.TxOptions
 	tx         *fakeTx
 	execResult extypes.Result
 	queryRows  extypes.Rows
@@ -39,7 +41,8 @@ func (b *fakeDriver) Query(_ context.Context, sql string, args ...any) (extypes.
 	return b.queryRows, b.queryErr
 }
 
-func (b *fakeDriver) BeginTx(_ context.Context) (TxDriver, error) {
+func (b *fakeDriver) BeginTx(_ context.Context, opts extypes.TxOptions) (TxDriver, error) {
+	b.beginOpts = opts
 	if b.beginErr != nil {
 		return nil, b.beginErr
 	}
@@ -86,7 +89,7 @@ func (t *fakeTx) Rollback() error {
 // reaching the driver. Drives the real placeholder.Dollar transformation.
 func TestAdapter_RewritesQuestionToDollar(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Dollar)
+	a := New(b, placeholder.Dollar, dialect.PostgreSQL)
 
 	_, err := a.ExecContext(context.Background(), "INSERT INTO t(a, b) VALUES (?, ?)", "x", 1)
 	require.NoError(t, err)
@@ -103,7 +106,7 @@ func TestAdapter_RewritesQuestionToDollar(t *testing.T) {
 // TestAdapter_QuestionPlaceholder_NoOp — Question format leaves SQL alone.
 func TestAdapter_QuestionPlaceholder_NoOp(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	_, err := a.ExecContext(context.Background(), "INSERT INTO t(a) VALUES (?)", "x")
 	require.NoError(t, err)
@@ -114,7 +117,7 @@ func TestAdapter_QuestionPlaceholder_NoOp(t *testing.T) {
 // subsequent RollbackUnlessCommitted is a no-op.
 func TestTransaction_Commit_FlipsCommittedFlag(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	require.NoError(t, err)
@@ -131,7 +134,7 @@ func TestTransaction_Commit_FlipsCommittedFlag(t *testing.T) {
 // for the safety net.
 func TestTransaction_RollbackUnlessCommitted_WithoutCommit_RollsBack(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	require.NoError(t, err)
@@ -148,7 +151,7 @@ func TestTransaction_RollbackUnlessCommitted_WithoutCommit_RollsBack(t *testing.
 // blocks the deferred RollbackUnlessCommitted.
 func TestTransaction_ExplicitRollback_ClearsSafetyNet(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	require.NoError(t, err)
@@ -163,7 +166,7 @@ func TestTransaction_ExplicitRollback_ClearsSafetyNet(t *testing.T) {
 func TestTransaction_CommitError_DoesNotMarkCommitted(t *testing.T) {
 	commitFail := errors.New("commit failed")
 	b := &fakeDriver{tx: &fakeTx{commitErr: commitFail}}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	require.NoError(t, err)
@@ -177,7 +180,7 @@ func TestTransaction_CommitError_DoesNotMarkCommitted(t *testing.T) {
 // Exec/Query also pass through the placeholder rewriter.
 func TestTransaction_ExecAndQuery_RewritePlaceholders(t *testing.T) {
 	b := &fakeDriver{}
-	a := New(b, placeholder.Dollar)
+	a := New(b, placeholder.Dollar, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	require.NoError(t, err)
@@ -196,9 +199,153 @@ func TestTransaction_ExecAndQuery_RewritePlaceholders(t *testing.T) {
 func TestAdapter_BeginTxError_Propagates(t *testing.T) {
 	beginFail := errors.New("begin failed")
 	b := &fakeDriver{beginErr: beginFail}
-	a := New(b, placeholder.Question)
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
 
 	tx, err := a.BeginTx(context.Background())
 	assert.Nil(t, tx)
 	assert.ErrorIs(t, err, beginFail)
 }
+
+// TestAdapter_BeginTxWithOptions — the options reach the driver as they are,
+// and the transaction gets the usual state machine.
+func TestAdapter_BeginTxWithOptions(t *testing.T) {
+	b := &fakeDriver{}
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
+
+	beginner, ok := a.(extypes.TxOptionsBeginner)
+	require.True(t, ok, "the bundled adapter must accept transaction options")
+	opts := extypes.TxOptions{Isolation: extypes.LevelSerializable, ReadOnly: true, Deferrable: true}
+	tx, err := beginner.BeginTxWithOptions(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, opts, b.beginOpts)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, tx.RollbackUnlessCommitted())
+	assert.Equal(t, 0, b.tx.rollbacks)
+}
+
+// copyDriver is a fakeDriver that also implements CopyDriver; its BeginTx
+// hands out a copyTx so the transaction side of the capability is covered too.
+type copyDriver struct {
+	fakeDriver
+	copied [][]any
+}
+
+func (b *copyDriver) CopyFrom(_ context.Context, _ string, _ []string, src extypes.CopySource) (int64, error) {
+	for src.Next() {
+		vals, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		b.copied = append(b.copied, vals)
+	}
+	return int64(len(b.copied)), src.Err()
+}
+
+func (b *copyDriver) BeginTx(_ context.Context, _ extypes.TxOptions) (TxDriver, error) {
+	return &copyTx{}, nil
+}
+
+type copyTx struct {
+	fakeTx
+}
+
+func (t *copyTx) CopyFrom(_ context.Context, _ string, _ []string, _ extypes.CopySource) (int64, error) {
+	return 7, nil
+}
+
+type sliceSource struct {
+	rows [][]any
+	idx  int
+}
+
+func (s *sliceSource) Next() bool             { s.idx++; return s.idx <= len(s.rows) }
+func (s *sliceSource) Values() ([]any, error) { return s.rows[s.idx-1], nil }
+func (s *sliceSource) Err() error             { return nil }
+
+// TestAdapter_CopyCapability — Copier is exposed only when the driver
+// implements CopyDriver, on the adapter and on the transactions it opens.
+func TestAdapter_CopyCapability(t *testing.T) {
+	plain := New(&fakeDriver{}, placeholder.Dollar, dialect.PostgreSQL)
+	_, ok := plain.(extypes.Copier)
+	assert.False(t, ok, "driver without CopyFrom must not advertise Copier")
+	plainTx, err := plain.BeginTx(context.Background())
+	require.NoError(t, err)
+	_, ok = plainTx.(extypes.Copier)
+	assert.False(t, ok)
+
+	b := &copyDriver{}
+	a := New(b, placeholder.Dollar, dialect.PostgreSQL)
+	copier, ok := a.(extypes.Copier)
+	require.True(t, ok)
+	n, err := copier.CopyFrom(context.Background(), "t", []string{"a"}, &sliceSource{rows: [][]any{{1}, {2}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, [][]any{{1}, {2}}, b.copied)
+
+	tx, err := a.BeginTx(context.Background())
+	require.NoError(t, err)
+	txCopier, ok := tx.(extypes.Copier)
+	require.True(t, ok)
+	n, err = txCopier.CopyFrom(context.Background(), "t", []string{"a"}, &sliceSource{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	require.NoError(t, tx.RollbackUnlessCommitted())
+}
+
+// TestAdapter_Dialect — the dialect is reported with and without the COPY
+// capability.
+func TestAdapter_Dialect(t *testing.T) {
+	assert.Equal(t, dialect.MySQL, New(&fakeDriver{}, placeholder.Question, dialect.MySQL).Dialect())
+	assert.Equal(t, dialect.PostgreSQL, New(&copyDriver{}, placeholder.Dollar, dialect.PostgreSQL).Dialect())
+}
+
+// TestTransaction_Callbacks — OnCommit / OnRollback callbacks run once, after
+// the outcome they wait for, in registration order.
+func TestTransaction_Callbacks(t *testing.T) {
+	begin := func(t *testing.T, b *fakeDriver) (extypes.Tx, extypes.TxCallbacks, *[]string) {
+		t.Helper()
+		tx, err := New(b, placeholder.Question, dialect.PostgreSQL).BeginTx(context.Background())
+		require.NoError(t, err)
+		callbacks, ok := tx.(extypes.TxCallbacks)
+		require.True(t, ok, "transactions of the bundled adapters must have TxCallbacks")
+		calls := &[]string{}
+		callbacks.OnCommit(func() { *calls = append(*calls, "commit 1") })
+		callbacks.OnRollback(func() { *calls = append(*calls, "rollback 1") })
+		callbacks.OnCommit(func() { *calls = append(*calls, "commit 2") })
+		callbacks.OnRollback(func() { *calls = append(*calls, "rollback 2") })
+		return tx, callbacks, calls
+	}
+
+	t.Run("Commit runs the OnCommit callbacks", func(t *testing.T) {
+		tx, callbacks, calls := begin(t, &fakeDriver{})
+		require.NoError(t, tx.Commit())
+		require.NoError(t, tx.RollbackUnlessCommitted())
+		assert.Equal(t, []string{"commit 1", "commit 2"}, *calls)
+
+		callbacks.OnCommit(func() { *calls = append(*calls, "late commit") })
+		callbacks.OnRollback(func() { *calls = append(*calls, "late rollback") })
+		assert.Equal(t, []string{"commit 1", "commit 2", "late commit"}, *calls,
+			"a callback registered after the outcome runs right away or never")
+	})
+
+	t.Run("Rollback runs the OnRollback callbacks", func(t *testing.T) {
+		tx, _, calls := begin(t, &fakeDriver{})
+		require.NoError(t, tx.RollbackUnlessCommitted())
+		require.NoError(t, tx.Rollback())
+		assert.Equal(t, []string{"rollback 1", "rollback 2"}, *calls)
+	})
+
+	t.Run("A failed Commit leaves the callbacks to the rollback", func(t *testing.T) {
+		tx, _, calls := begin(t, &fakeDriver{tx: &fakeTx{commitErr: errors.New("commit failed")}})
+		require.Error(t, tx.Commit())
+		assert.Empty(t, *calls)
+		require.NoError(t, tx.RollbackUnlessCommitted())
+		assert.Equal(t, []string{"rollback 1", "rollback 2"}, *calls)
+	})
+
+	t.Run("A failed Rollback still runs the OnRollback callbacks", func(t *testing.T) {
+		tx, _, calls := begin(t, &fakeDriver{tx: &fakeTx{rollbackErr: errors.New("conn lost")}})
+		require.Error(t, tx.Rollback())
+		assert.Equal(t, []string{"rollback 1", "rollback 2"}, *calls)
+	})
+}
@@ -0,0 +1,193 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+
+	extypes "github.com/insei/gerpo/executor/types"
+)
+
+// pgConstraintKinds maps the SQLSTATE codes of integrity constraint
+// violations to their kinds.
+var pgConstraintKinds = map[string]extypes.ConstraintKind{
+	"23505": extypes.ConstraintUnique,
+	"23503": extypes.ConstraintForeignKey,
+	"23514": extypes.ConstraintCheck,
+	"23502": extypes.ConstraintNotNull,
+}
+
+// PGError holds the fields of a PostgreSQL error message the violation types
+// are built from; each PG driver copies them out of its own error type.
+type PGError struct {
+	Code       string
+	Constraint string
+	Table      string
+	Column     string
+	Detail     string
+}
+
+// PGViolation returns err as a violation type when e is an integrity
+// constraint violation, nil otherwise. PostgreSQL names the column of NOT NULL
+// violations only; for a key the columns come from the detail message,
+// "Key (a, b)=(...) already exists." — with lc_messages in another language
+// than English, Column stays empty for keys.
+func PGViolation(err error, e PGError) error {
+	kind, ok := pgConstraintKinds[e.Code]
+	if !ok {
+		return nil
+	}
+	column := e.Column
+	// A delete reported by a foreign key names the referenced columns, not
+	// those of Table.
+	if column == "" && !strings.Contains(e.Detail, "still referenced") {
+		column = keyColumns(e.Detail)
+	}
+	return extypes.NewConstraintViolation(kind, extypes.ConstraintViolation{
+		Constraint: e.Constraint,
+		Table:      e.Table,
+		Column:     column,
+		Err:        err,
+	})
+}
+
+// keyColumns extracts "a, b" from a detail message starting with
+// "Key (a, b)=", "" when it does not.
+func keyColumns(detail string) string {
+	rest, ok := strings.CutPrefix(detail, "Key (")
+	if !ok {
+		return ""
+	}
+	columns, _, ok := strings.Cut(rest, ")=")
+	if !ok {
+		return ""
+	}
+	// Identifiers that need quoting are quoted there.
+	return strings.ReplaceAll(columns, `"`, "")
+}
+
+// mysqlConstraintKinds maps the error numbers of MySQL constraint violations
+// to their kinds.
+var mysqlConstraintKinds = map[int]extypes.ConstraintKind{
+	1062: extypes.ConstraintUnique,     // ER_DUP_ENTRY
+	1451: extypes.ConstraintForeignKey, // ER_ROW_IS_REFERENCED_2
+	1452: extypes.ConstraintForeignKey, // ER_NO_REFERENCED_ROW_2
+	3819: extypes.ConstraintCheck,      // ER_CHECK_CONSTRAINT_VIOLATED
+	1048: extypes.ConstraintNotNull,    // ER_BAD_NULL_ERROR
+}
+
+var (
+	// mysqlDupKey matches "Duplicate entry '...' for key 'users.users_email_key'";
+	// MySQL before 8.0.19 does not qualify the key with its table.
+	mysqlDupKey = regexp.MustCompile(`for key '(?:([^'.]+)\.)?([^']+)'$`)
+	// mysqlForeignKey matches the "(`db`.`posts`, CONSTRAINT `fk` FOREIGN KEY
+	// (`author_id`) REFERENCES ..." part of both foreign key errors.
+	mysqlForeignKey = regexp.MustCompile("`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(([^)]+)\\)")
+	// mysqlQuoted matches the first name quoted in a message: the constraint
+	// of "Check constraint 'c' is violated.", the column of "Column 'c'
+	// cannot be null".
+	mysqlQuoted = regexp.MustCompile(`'([^']+)'`)
+)
+
+// MySQLViolation returns err as a violation type when number is the error
+// number of a constraint violation, nil otherwise; the names come from
+// message. MySQL names no column for unique keys and no table for CHECK and
+// NOT NULL. A delete reported by a foreign key names the referencing table
+// and, as with PostgreSQL, no column.
+func MySQLViolation(err error, number int, message string) error {
+	kind, ok := mysqlConstraintKinds[number]
+	if !ok {
+		return nil
+	}
+	v := extypes.ConstraintViolation{Err: err}
+	switch number {
+	case 1062:
+		if m := mysqlDupKey.FindStringSubmatch(message); m != nil {
+			v.Table, v.Constraint = m[1], m[2]
+		}
+	case 1451, 1452:
+		if m := mysqlForeignKey.FindStringSubmatch(message); m != nil {
+			v.Table, v.Constraint = m[1], m[2]
+			if number == 1452 {
+				v.Column = strings.ReplaceAll(m[3], "`", "")
+			}
+		}
+	case 3819:
+		if m := mysqlQuoted.FindStringSubmatch(message); m != nil {
+			v.Constraint = m[1]
+		}
+	case 1048:
+		if m := mysqlQuoted.FindStringSubmatch(message); m != nil {
+			v.Column = m[1]
+		}
+	}
+	return extypes.NewConstraintViolation(kind, v)
+}
+
+var (
+	// sqlServerKey matches "Violation of UNIQUE KEY constraint 'c'. Cannot
+	// insert duplicate key in object 'dbo.users'." (2627, PRIMARY KEY too).
+	sqlServerKey = regexp.MustCompile(`constraint '([^']+)'\. Cannot insert duplicate key in object '([^']+)'`)
+	// sqlServerIndex matches "Cannot insert duplicate key row in object
+	// 'dbo.users' with unique index 'ix'." (2601).
+	sqlServerIndex = regexp.MustCompile(`in object '([^']+)' with unique index '([^']+)'`)
+	// sqlServerConflict matches "The INSERT statement conflicted with the
+	// CHECK constraint "c". The conflict occurred in database "db", table
+	// "dbo.posts", column 'title'." (547); the column is not always there.
+	sqlServerConflict = regexp.MustCompile(`conflicted with the (FOREIGN KEY|REFERENCE|CHECK) constraint "([^"]+)"\. The conflict occurred in database "[^"]+", table "([^"]+)"(?:, column '([^']+)')?`)
+	// sqlServerNull matches "Cannot insert the value NULL into column 'c',
+	// table 'db.dbo.posts'" (515).
+	sqlServerNull = regexp.MustCompile(`into column '([^']+)', table '([^']+)'`)
+)
+
+// SQLServerViolation returns err as a violation type when number is the
+// error number of a constraint violation, nil otherwise; the names come from
+// message. A FOREIGN KEY conflict of an insert or update names the
+// referenced table and column, which are left out; a REFERENCE conflict of a
+// delete keeps the referencing table only.
+func SQLServerViolation(err error, number int, message string) error {
+	v := extypes.ConstraintViolation{Err: err}
+	var kind extypes.ConstraintKind
+	switch number {
+	case 2627:
+		kind = extypes.ConstraintUnique
+		if m := sqlServerKey.FindStringSubmatch(message); m != nil {
+			v.Constraint, v.Table = m[1], unqualified(m[2])
+		}
+	case 2601:
+		kind = extypes.ConstraintUnique
+		if m := sqlServerIndex.FindStringSubmatch(message); m != nil {
+			v.Table, v.Constraint = unqualified(m[1]), m[2]
+		}
+	case 547:
+		m := sqlServerConflict.FindStringSubmatch(message)
+		if m == nil {
+			// 547 reports other conflicts too; only the constraint ones
+			// are violations.
+			return nil
+		}
+		v.Constraint = m[2]
+		switch m[1] {
+		case "CHECK":
+			kind = extypes.ConstraintCheck
+			v.Table, v.Column = unqualified(m[3]), m[4]
+		case "REFERENCE":
+			kind = extypes.ConstraintForeignKey
+			v.Table = unqualified(m[3])
+		default:
+			kind = extypes.ConstraintForeignKey
+		}
+	case 515:
+		kind = extypes.ConstraintNotNull
+		if m := sqlServerNull.FindStringSubmatch(message); m != nil {
+			v.Column, v.Table = m[1], unqualified(m[2])
+		}
+	default:
+		return nil
+	}
+	return extypes.NewConstraintViolation(kind, v)
+}
+
+// unqualified drops the database and schema in front of a table name.
+func unqualified(table string) string {
+	return table[strings.LastIndex(table, ".")+1:]
+}
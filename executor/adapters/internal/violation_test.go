@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/executor/adapters/placeholder"
+	extypes "github.com/insei/gerpo/executor/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPGViolation — SQLSTATE codes map to the violation types; the columns of
+// a key come from the detail message.
+func TestPGViolation(t *testing.T) {
+	driverErr := errors.New("driver")
+
+	err := PGViolation(driverErr, PGError{
+		Code: "23505", Constraint: "users_email_key", Table: "users",
+		Detail: `Key (tenant_id, "order")=(1, 2) already exists.`,
+	})
+	var uv *extypes.ErrUniqueViolation
+	require.ErrorAs(t, err, &uv)
+	assert.Equal(t, extypes.ConstraintViolation{
+		Constraint: "users_email_key", Table: "users", Column: "tenant_id, order", Err: driverErr,
+	}, uv.ConstraintViolation)
+	assert.ErrorIs(t, err, driverErr)
+	assert.Equal(t, "driver", err.Error())
+
+	err = PGViolation(driverErr, PGError{Code: "23502", Table: "users", Column: "name", Detail: "Failing row contains (1, null)."})
+	var nnv *extypes.ErrNotNullViolation
+	require.ErrorAs(t, err, &nnv)
+	assert.Equal(t, "name", nnv.Column)
+
+	err = PGViolation(driverErr, PGError{Code: "23503", Table: "posts", Detail: `Key (id)=(1) is still referenced from table "posts".`})
+	var fkv *extypes.ErrForeignKeyViolation
+	require.ErrorAs(t, err, &fkv)
+	assert.Empty(t, fkv.Column, "a delete names the columns of the referenced table")
+
+	err = PGViolation(driverErr, PGError{Code: "23514", Constraint: "age_positive"})
+	var cv *extypes.ErrCheckViolation
+	require.ErrorAs(t, err, &cv)
+	assert.Equal(t, "age_positive", cv.Violation().Constraint)
+
+	assert.Nil(t, PGViolation(driverErr, PGError{Code: "40001"}))
+}
+
+// TestMySQLViolation — error numbers map to the violation types; the names
+// come from the message.
+func TestMySQLViolation(t *testing.T) {
+	driverErr := errors.New("driver")
+	for _, tt := range []struct {
+		name    string
+		number  int
+		message string
+		want    extypes.ConstraintViolation
+		kind    any
+	}{
+		{"unique", 1062, "Duplicate entry 'a@b.c' for key 'users.users_email_key'",
+			extypes.ConstraintViolation{Constraint: "users_email_key", Table: "users"}, &extypes.ErrUniqueViolation{}},
+		{"unique before 8.0.19", 1062, "Duplicate entry 'a@b.c' for key 'users_email_key'",
+			extypes.ConstraintViolation{Constraint: "users_email_key"}, &extypes.ErrUniqueViolation{}},
+		{"foreign key", 1452, "Cannot add or update a child row: a foreign key constraint fails (`blog`.`posts`, CONSTRAINT `posts_author_fk` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`))",
+			extypes.ConstraintViolation{Constraint: "posts_author_fk", Table: "posts", Column: "author_id"}, &extypes.ErrForeignKeyViolation{}},
+		{"referenced row", 1451, "Cannot delete or update a parent row: a foreign key constraint fails (`blog`.`posts`, CONSTRAINT `posts_author_fk` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`))",
+			extypes.ConstraintViolation{Constraint: "posts_author_fk", Table: "posts"}, &extypes.ErrForeignKeyViolation{}},
+		{"check", 3819, "Check constraint 'age_positive' is violated.",
+			extypes.ConstraintViolation{Constraint: "age_positive"}, &extypes.ErrCheckViolation{}},
+		{"not null", 1048, "Column 'name' cannot be null",
+			extypes.ConstraintViolation{Column: "name"}, &extypes.ErrNotNullViolation{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MySQLViolation(driverErr, tt.number, tt.message)
+			require.IsType(t, tt.kind, err)
+			tt.want.Err = driverErr
+			assert.Equal(t, &tt.want, err.(interface {
+				Violation() *extypes.ConstraintViolation
+			}).Violation())
+		})
+	}
+	assert.Nil(t, MySQLViolation(driverErr, 1213, "Deadlock found when trying to get lock"))
+}
+
+// TestSQLServerViolation — error numbers map to the violation types; the
+// names come from the message.
+func TestSQLServerViolation(t *testing.T) {
+	driverErr := errors.New("driver")
+	for _, tt := range []struct {
+		name    string
+		number  int
+		message string
+		want    extypes.ConstraintViolation
+		kind    any
+	}{
+		{"unique constraint", 2627, "Violation of UNIQUE KEY constraint 'UQ_users_email'. Cannot insert duplicate key in object 'dbo.users'. The duplicate key value is (a@b.c).",
+			extypes.ConstraintViolation{Constraint: "UQ_users_email", Table: "users"}, &extypes.ErrUniqueViolation{}},
+		{"unique index", 2601, "Cannot insert duplicate key row in object 'dbo.users' with unique index 'ix_users_email'. The duplicate key value is (a@b.c).",
+			extypes.ConstraintViolation{Constraint: "ix_users_email", Table: "users"}, &extypes.ErrUniqueViolation{}},
+		{"foreign key", 547, `The INSERT statement conflicted with the FOREIGN KEY constraint "FK_posts_users". The conflict occurred in database "blog", table "dbo.users", column 'id'.`,
+			extypes.ConstraintViolation{Constraint: "FK_posts_users"}, &extypes.ErrForeignKeyViolation{}},
+		{"reference", 547, `The DELETE statement conflicted with the REFERENCE constraint "FK_posts_users". The conflict occurred in database "blog", table "dbo.posts", column 'author_id'.`,
+			extypes.ConstraintViolation{Constraint: "FK_posts_users", Table: "posts"}, &extypes.ErrForeignKeyViolation{}},
+		{"check", 547, `The INSERT statement conflicted with the CHECK constraint "CK_users_age". The conflict occurred in database "blog", table "dbo.users", column 'age'.`,
+			extypes.ConstraintViolation{Constraint: "CK_users_age", Table: "users", Column: "age"}, &extypes.ErrCheckViolation{}},
+		{"not null", 515, "Cannot insert the value NULL into column 'name', table 'blog.dbo.users'; column does not allow nulls. INSERT fails.",
+			extypes.ConstraintViolation{Table: "users", Column: "name"}, &extypes.ErrNotNullViolation{}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SQLServerViolation(driverErr, tt.number, tt.message)
+			require.IsType(t, tt.kind, err)
+			tt.want.Err = driverErr
+			assert.Equal(t, &tt.want, err.(interface {
+				Violation() *extypes.ConstraintViolation
+			}).Violation())
+		})
+	}
+	assert.Nil(t, SQLServerViolation(driverErr, 1205, "Transaction was deadlocked"))
+}
+
+// violationDriver is a fakeDriver whose errors are all unique violations.
+type violationDriver struct {
+	fakeDriver
+}
+
+func (b *violationDriver) ConstraintViolation(err error) error {
+	return extypes.NewConstraintViolation(extypes.ConstraintUnique, extypes.ConstraintViolation{Err: err})
+}
+
+// errRows reports err once iterated, the way pgx reports the error of an
+// INSERT ... RETURNING.
+type errRows struct{ err error }
+
+func (r *errRows) Next() bool        { return false }
+func (r *errRows) Scan(...any) error { return r.err }
+func (r *errRows) Err() error        { return r.err }
+func (r *errRows) Close() error      { return nil }
+
+// TestAdapter_ConstraintViolations — a ViolationDriver translates the errors
+// of statements, rows and Commit, in and out of transactions.
+func TestAdapter_ConstraintViolations(t *testing.T) {
+	driverErr := errors.New("duplicate")
+	b := &violationDriver{fakeDriver{
+		execErr:   driverErr,
+		queryRows: &errRows{err: driverErr},
+		tx:        &fakeTx{commitErr: driverErr},
+	}}
+	a := New(b, placeholder.Question, dialect.PostgreSQL)
+	var uv *extypes.ErrUniqueViolation
+
+	_, err := a.ExecContext(context.Background(), "INSERT")
+	assert.ErrorAs(t, err, &uv)
+
+	rows, err := a.QueryContext(context.Background(), "INSERT ... RETURNING")
+	require.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.ErrorAs(t, rows.Err(), &uv)
+	assert.ErrorAs(t, rows.Scan(), &uv)
+	assert.NoError(t, rows.Close())
+
+	tx, err := a.BeginTx(context.Background())
+	require.NoError(t, err)
+	assert.ErrorAs(t, tx.Commit(), &uv, "a deferred constraint fails at commit")
+
+	plain := New(&fakeDriver{execErr: driverErr}, placeholder.Question, dialect.PostgreSQL)
+	_, err = plain.ExecContext(context.Background(), "INSERT")
+	assert.Same(t, driverErr, err, "without a ViolationDriver errors pass through")
+}
@@ -0,0 +1,24 @@
+package pgx4
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/insei/gerpo/executor/adapters/internal"
+)
+
+// ConstraintViolation implements internal.ViolationDriver for *pgconn.PgError.
+func (b *poolDriver) ConstraintViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	return internal.PGViolation(err, internal.PGError{
+		Code:       pgErr.Code,
+		Constraint: pgErr.ConstraintName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Detail:     pgErr.Detail,
+	})
+}
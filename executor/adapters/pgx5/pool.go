@@ -2,10 +2,12 @@ package pgx5
 
 import (
 	"context"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor/adapters/internal"
 	"github.com/insei/gerpo/executor/adapters/placeholder"
 	extypes "github.com/insei/gerpo/executor/types"
@@ -32,14 +34,38 @@ func (b *poolDriver) Query(ctx context.Context, sql string, args ...any) (extype
 	return &rowsWrap{rows: rows}, nil
 }
 
-func (b *poolDriver) BeginTx(ctx context.Context) (internal.TxDriver, error) {
-	tx, err := b.pool.BeginTx(ctx, pgx.TxOptions{})
+func (b *poolDriver) CopyFrom(ctx context.Context, table string, columns []string, src extypes.CopySource) (int64, error) {
+	return b.pool.CopyFrom(ctx, copyTable(table), columns, src)
+}
+
+func (b *poolDriver) BeginTx(ctx context.Context, opts extypes.TxOptions) (internal.TxDriver, error) {
+	tx, err := b.pool.BeginTx(ctx, txOptions(opts))
 	if err != nil {
 		return nil, err
 	}
 	return &txDriver{tx: tx}, nil
 }
 
+var isoLevels = map[extypes.IsolationLevel]pgx.TxIsoLevel{
+	extypes.LevelReadUncommitted: pgx.ReadUncommitted,
+	extypes.LevelReadCommitted:   pgx.ReadCommitted,
+	extypes.LevelRepeatableRead:  pgx.RepeatableRead,
+	extypes.LevelSerializable:    pgx.Serializable,
+}
+
+// txOptions maps opts to pgx.TxOptions; the zero value of either leaves the
+// server defaults.
+func txOptions(opts extypes.TxOptions) pgx.TxOptions {
+	o := pgx.TxOptions{IsoLevel: isoLevels[opts.Isolation]}
+	if opts.ReadOnly {
+		o.AccessMode = pgx.ReadOnly
+	}
+	if opts.Deferrable {
+		o.DeferrableMode = pgx.Deferrable
+	}
+	return o
+}
+
 // txDriver implements internal.TxDriver on top of pgx.Tx. Commit/Rollback do
 // not propagate caller context — pgx.Tx insists on its own background context
 // for those operations.
@@ -63,11 +89,21 @@ func (t *txDriver) Query(ctx context.Context, sql string, args ...any) (extypes.
 	return &rowsWrap{rows: rows}, nil
 }
 
+func (t *txDriver) CopyFrom(ctx context.Context, table string, columns []string, src extypes.CopySource) (int64, error) {
+	return t.tx.CopyFrom(ctx, copyTable(table), columns, src)
+}
+
 func (t *txDriver) Commit() error   { return t.tx.Commit(context.Background()) }
 func (t *txDriver) Rollback() error { return t.tx.Rollback(context.Background()) }
 
+// copyTable turns a possibly schema-qualified table name into the
+// identifier pgx quotes for COPY.
+func copyTable(table string) pgx.Identifier {
+	return pgx.Identifier(strings.Split(table, "."))
+}
+
 // NewPoolAdapter wraps a pgx v5 pool with the gerpo DB adapter contract.
 // SQL placeholders are rewritten from `?` to PostgreSQL's `$1, $2, …` form.
 func NewPoolAdapter(pool *pgxpool.Pool) extypes.Adapter {
-	return internal.New(&poolDriver{pool: pool}, placeholder.Dollar)
+	return internal.New(&poolDriver{pool: pool}, placeholder.Dollar, dialect.PostgreSQL)
 }
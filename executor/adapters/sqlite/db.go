@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/executor/adapters/placeholder"
+	extypes "github.com/insei/gerpo/executor/types"
+)
+
+// NewAdapter wraps a database/sql DB opened with an SQLite driver
+// (modernc.org/sqlite, github.com/mattn/go-sqlite3) with the gerpo DB adapter
+// contract: SQLite's own `?` placeholders and the SQLite dialect. SQLite 3.35+
+// is required for RETURNING.
+//
+// A DB shared by several goroutines should be opened in WAL mode with a busy
+// timeout, and with transactions that take the write lock up front
+// (`_txlock=immediate`) — a deferred transaction that starts writing late
+// fails with SQLITE_BUSY rather than waiting for the lock.
+func NewAdapter(db *sql.DB) extypes.Adapter {
+	return databasesql.NewAdapter(db,
+		databasesql.WithPlaceholder(placeholder.Question),
+		databasesql.WithDialect(dialect.SQLite),
+	)
+}
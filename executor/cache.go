@@ -6,8 +6,18 @@ import (
 	"github.com/insei/gerpo/executor/cache"
 )
 
-func get[TCached any](ctx context.Context, b cache.Storage, stmt string, stmtArgs ...any) (*TCached, bool) {
+// scope returns ctx scoping the read about to query b, see cache.WithScope.
+func scope(ctx context.Context, b cache.Storage) context.Context {
 	if b == nil {
+		return ctx
+	}
+	return cache.WithScope(ctx)
+}
+
+// get looks the read up in b. A read forced to the primary skips it, the
+// entry may come from a lagging replica, but still fills it.
+func get[TCached any](ctx context.Context, b cache.Storage, stmt string, stmtArgs ...any) (*TCached, bool) {
+	if b == nil || cache.ControlFromContext(ctx).NoCache || forcedPrimary(ctx) {
 		return nil, false
 	}
 	cached, err := b.Get(ctx, stmt, stmtArgs...)
@@ -21,11 +31,70 @@ func get[TCached any](ctx context.Context, b cache.Storage, stmt string, stmtArg
 	return nil, false
 }
 
-func set(ctx context.Context, b cache.Storage, cache any, statement string, statementArgs ...any) {
-	if b == nil {
+func set(ctx context.Context, b cache.Storage, value any, statement string, statementArgs ...any) {
+	if b == nil || cache.ControlFromContext(ctx).NoCache {
 		return
 	}
-	b.Set(ctx, cache, statement, statementArgs...)
+	b.Set(ctx, value, statement, statementArgs...)
+}
+
+// release hands the claim of a read that ends without a set back to b, see
+// cache.Releaser.
+func release(ctx context.Context, b cache.Storage, statement string, statementArgs ...any) {
+	if r, ok := b.(cache.Releaser); ok {
+		r.Release(ctx, statement, statementArgs...)
+	}
+}
+
+// copyModel returns model, or a copy of it under WithCopyOnRead.
+func (e *executor[TModel]) copyModel(model *TModel) *TModel {
+	if e.clone == nil {
+		return model
+	}
+	return e.clone(model)
+}
+
+// sharedModel returns model read by a flight of WithSingleFlight to one of
+// its callers: a copy of its own, so that the AfterSelect hooks of a caller
+// and what it does with the model never reach the others.
+func (e *executor[TModel]) sharedModel(model *TModel) *TModel {
+	if e.flights == nil || model == nil {
+		return model
+	}
+	return e.flightClone(model)
+}
+
+// sharedModels is sharedModel for a list of models.
+func (e *executor[TModel]) sharedModels(models []*TModel) []*TModel {
+	if e.flights == nil || models == nil {
+		return models
+	}
+	cp := make([]*TModel, len(models))
+	for i, model := range models {
+		cp[i] = e.flightClone(model)
+	}
+	return cp
+}
+
+// copyModels returns models, or a new slice of copies of them under
+// WithCopyOnRead.
+func (e *executor[TModel]) copyModels(models []*TModel) []*TModel {
+	if e.clone == nil || models == nil {
+		return models
+	}
+	cp := make([]*TModel, len(models))
+	for i, model := range models {
+		cp[i] = e.clone(model)
+	}
+	return cp
+}
+
+// BindCache implements CacheBinder: a storage supporting per-table
+// invalidation is replaced by its Bind, others are kept as they are.
+func (e *executor[TModel]) BindCache(table string, dependencies []string) {
+	if b, ok := e.cacheSource.(cache.Binder); ok {
+		e.cacheSource = b.Bind(table, dependencies)
+	}
 }
 
 func clean(ctx context.Context, b cache.Storage) {
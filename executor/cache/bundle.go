@@ -30,6 +30,28 @@ func (m *storagesBundle) Set(ctx context.Context, cache any, statement string, s
 	}
 }
 
+// Release implements Releaser for the storages of the bundle that support it.
+func (m *storagesBundle) Release(ctx context.Context, statement string, statementArgs ...any) {
+	for _, storage := range m.storages {
+		if r, ok := storage.(Releaser); ok {
+			r.Release(ctx, statement, statementArgs...)
+		}
+	}
+}
+
+// Bind implements Binder: it binds the storages of the bundle that support it
+// and keeps the others as they are.
+func (m *storagesBundle) Bind(table string, dependencies []string) Storage {
+	bound := &storagesBundle{storages: make([]Storage, len(m.storages))}
+	for i, storage := range m.storages {
+		if b, ok := storage.(Binder); ok {
+			storage = b.Bind(table, dependencies)
+		}
+		bound.storages[i] = storage
+	}
+	return bound
+}
+
 func NewModelBundle(opts ...Option) Storage {
 	b := &storagesBundle{}
 	for _, opt := range opts {
@@ -215,3 +215,29 @@ func TestNewModelBundle(t *testing.T) {
 		})
 	}
 }
+
+// binderSource is a Storage remembering the Bind it was made by.
+type binderSource struct {
+	mockSource
+	table string
+}
+
+func (m *binderSource) Bind(table string, _ []string) Storage {
+	return &binderSource{table: table}
+}
+
+func TestModelBundle_Bind(t *testing.T) {
+	plain := &mockSource{}
+	bundle := NewModelBundle(WithStorage(&binderSource{}), WithStorage(plain))
+
+	bound := bundle.(Binder).Bind("posts", []string{"users"}).(*storagesBundle)
+	if len(bound.storages) != 2 {
+		t.Fatalf("expected 2 storages, got %d", len(bound.storages))
+	}
+	if b, ok := bound.storages[0].(*binderSource); !ok || b.table != "posts" {
+		t.Errorf("expected the binder storage bound to posts, got %#v", bound.storages[0])
+	}
+	if bound.storages[1] != plain {
+		t.Errorf("expected the plain storage kept, got %#v", bound.storages[1])
+	}
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Control is the cache control of one read, set through its query helper
+// (NoCache, CacheTTL, CacheKey) and carried by its context down to the
+// storage. The zero Control leaves the storage as configured.
+type Control struct {
+	// NoCache makes the read skip the storage: it neither reads nor fills it.
+	NoCache bool
+	// TTL overrides the lifetime of the entry the read fills, for the
+	// storages whose entries expire.
+	TTL time.Duration
+	// Tags are added to the tags of the entry the read fills, for the
+	// storages invalidating entries per tag.
+	Tags []string
+}
+
+type controlKey struct{}
+
+// WithControl returns a derived context carrying c.
+func WithControl(ctx context.Context, c Control) context.Context {
+	return context.WithValue(ctx, controlKey{}, c)
+}
+
+// ControlFromContext returns the Control carried by ctx, the zero Control
+// when there is none.
+func ControlFromContext(ctx context.Context) Control {
+	c, _ := ctx.Value(controlKey{}).(Control)
+	return c
+}
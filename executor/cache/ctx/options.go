@@ -33,3 +33,13 @@ func WithKey(key string) Option {
 		}
 	})
 }
+
+// WithUndeclaredDependencies marks the reads of the repository as depending on
+// tables it cannot declare — raw SQL reading other tables without
+// virtual.DependsOn, for instance. A write to any table through the context
+// then drops its entries, as before the per-table invalidation.
+func WithUndeclaredDependencies() Option {
+	return cacheOption(func(s *Cache) {
+		s.undeclared = true
+	})
+}
@@ -2,11 +2,9 @@ package ctx
 
 import (
 	"context"
-	"fmt"
-	"strconv"
-	"strings"
 
 	"github.com/google/uuid"
+	"github.com/insei/gerpo/executor/cache"
 	"github.com/insei/gerpo/executor/cache/types"
 	"github.com/insei/gerpo/logger"
 )
@@ -14,6 +12,14 @@ import (
 type Cache struct {
 	key string
 	log logger.Logger
+	// table is the table of the repository the Cache is bound to, empty
+	// before Bind: its writes then wipe the whole context.
+	table string
+	// tables are the tables its reads depend on, nil for every table.
+	tables []string
+	// undeclared marks reads depending on tables the repository cannot
+	// declare; see WithUndeclaredDependencies.
+	undeclared bool
 }
 
 func New(opts ...Option) *Cache {
@@ -46,76 +52,45 @@ func (s *Cache) Get(ctx context.Context, statement string, statementArgs ...any)
 	if err != nil {
 		return nil, err
 	}
-	return storage.Get(s.key, buildKey(statement, statementArgs))
+	return storage.Get(s.key, cache.Key(statement, statementArgs...))
 }
 
-func (s *Cache) Set(ctx context.Context, cache any, statement string, statementArgs ...any) {
+func (s *Cache) Set(ctx context.Context, value any, statement string, statementArgs ...any) {
 	storage, err := s.getStorage(ctx)
 	if err != nil {
 		return
 	}
-	storage.Set(s.key, buildKey(statement, statementArgs), cache)
+	storage.Set(s.key, cache.Key(statement, statementArgs...), value, s.tables...)
 }
 
-// buildKey assembles a cache key from a SQL statement and its arguments without going
-// through fmt.Sprintf. It mirrors the original "%s%v" encoding closely enough for
-// equality comparison while avoiding the allocations fmt incurs for each argument.
-func buildKey(statement string, args []any) string {
-	var sb strings.Builder
-	sb.Grow(len(statement) + 2 + len(args)*8)
-	sb.WriteString(statement)
-	sb.WriteByte('[')
-	for i, a := range args {
-		if i > 0 {
-			sb.WriteByte(' ')
-		}
-		writeArg(&sb, a)
+// Bind implements cache.Binder: it returns the Cache of a repository reading
+// table and dependencies. Its entries live in a section of their own and are
+// dropped by a write through the context to any of these tables; its writes
+// drop the entries reading table only.
+func (s *Cache) Bind(table string, dependencies []string) cache.Storage {
+	bound := *s
+	bound.key = s.key + "/" + table
+	bound.table = table
+	if !s.undeclared {
+		bound.tables = append([]string{table}, dependencies...)
 	}
-	sb.WriteByte(']')
-	return sb.String()
+	return &bound
 }
 
-func writeArg(sb *strings.Builder, a any) {
-	switch v := a.(type) {
-	case nil:
-		sb.WriteString("<nil>")
-	case string:
-		sb.WriteString(v)
-	case int:
-		sb.WriteString(strconv.Itoa(v))
-	case int64:
-		sb.WriteString(strconv.FormatInt(v, 10))
-	case int32:
-		sb.WriteString(strconv.FormatInt(int64(v), 10))
-	case uint:
-		sb.WriteString(strconv.FormatUint(uint64(v), 10))
-	case uint64:
-		sb.WriteString(strconv.FormatUint(v, 10))
-	case uint32:
-		sb.WriteString(strconv.FormatUint(uint64(v), 10))
-	case bool:
-		if v {
-			sb.WriteString("true")
-		} else {
-			sb.WriteString("false")
-		}
-	case []byte:
-		sb.Write(v)
-	case uuid.UUID:
-		sb.WriteString(v.String())
-	default:
-		fmt.Fprint(sb, a)
-	}
-}
-
-// Clean invalidates every cached read inside the current context, not just the
-// entries belonging to this Cache instance. Any write through any repository
-// bound to the same context wipes the whole per-context storage — the only safe
-// default when repositories can share results through virtual columns or JOINs.
+// Clean invalidates the cached reads inside the current context that depend on
+// the table of the repository, not just the entries belonging to this Cache
+// instance: those of other repositories joining it or reading it through
+// virtual columns go too, and so do the entries of repositories that declare
+// no tables. Before Bind the table is unknown and Clean wipes the whole
+// per-context storage.
 func (s *Cache) Clean(ctx context.Context) {
 	storage, err := s.getStorage(ctx)
 	if err != nil {
 		return
 	}
-	storage.Clean()
+	if s.table == "" {
+		storage.Clean()
+		return
+	}
+	storage.CleanTable(s.table)
 }
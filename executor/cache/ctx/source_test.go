@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/insei/gerpo/executor/cache"
 	"github.com/insei/gerpo/executor/cache/types"
 	"github.com/insei/gerpo/logger"
 	"github.com/stretchr/testify/assert"
@@ -245,3 +246,48 @@ func TestGetSet_NamespaceIsolation(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "posts-row", got, "posts cache must see its own value")
 }
+
+// TestClean_Bound — bound to their repositories, caches drop on a write only
+// the reads depending on the written table.
+func TestClean_Bound(t *testing.T) {
+	shared := &Cache{log: logger.NoopLogger, key: "shared"}
+	users := shared.Bind("users", nil)
+	posts := shared.Bind("posts", []string{"users"})
+	tags := shared.Bind("tags", nil)
+	raw := (&Cache{log: logger.NoopLogger, key: "raw", undeclared: true}).Bind("raw", nil)
+	ctx := WrapContext(context.Background())
+	fill := func() {
+		for _, c := range []cache.Storage{users, posts, tags, raw} {
+			c.Set(ctx, "row", "SELECT 1")
+		}
+	}
+	cached := func(caches ...cache.Storage) (n int) {
+		for _, c := range caches {
+			if _, err := c.Get(ctx, "SELECT 1"); err == nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	fill()
+	assert.Equal(t, 4, cached(users, posts, tags, raw), "bound caches of one instance keep sections of their own")
+
+	users.Clean(ctx)
+	assert.Equal(t, 0, cached(users, posts, raw))
+	assert.Equal(t, 1, cached(tags), "reads not depending on users survive")
+
+	fill()
+	posts.Clean(ctx)
+	assert.Equal(t, 0, cached(posts, raw))
+	assert.Equal(t, 2, cached(users, tags))
+
+	fill()
+	raw.Clean(ctx)
+	assert.Equal(t, 0, cached(raw))
+	assert.Equal(t, 3, cached(users, posts, tags))
+
+	fill()
+	shared.Clean(ctx)
+	assert.Equal(t, 0, cached(users, posts, tags, raw), "an unbound cache wipes the whole context")
+}
@@ -3,6 +3,7 @@ package ctx
 import (
 	"context"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/insei/gerpo/executor/cache/types"
@@ -20,6 +21,9 @@ type cacheStorage struct {
 	mtx      *sync.Mutex
 	c        map[string]map[string]any
 	disabled []string
+	// tables holds the tables the entries of a model key read; the entries of
+	// a model key missing from it read every table.
+	tables map[string][]string
 }
 
 func (s *cacheStorage) Get(modelKey string, key string) (any, error) {
@@ -39,7 +43,10 @@ func (s *cacheStorage) Get(modelKey string, key string) (any, error) {
 	return cached, nil
 }
 
-func (s *cacheStorage) Set(modelKey string, key string, value any) {
+// Set stores value under key in the cache section of modelKey. tables, when
+// given, are the tables the entries of the section read; the section is
+// dropped by CleanTable of any of them only.
+func (s *cacheStorage) Set(modelKey string, key string, value any, tables ...string) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	modelCache, ok := s.c[modelKey]
@@ -48,22 +55,49 @@ func (s *cacheStorage) Set(modelKey string, key string, value any) {
 		s.c[modelKey] = modelCache
 	}
 	modelCache[key] = value
+	if len(tables) > 0 {
+		if s.tables == nil {
+			s.tables = make(map[string][]string)
+		}
+		s.tables[modelKey] = tables
+	}
 }
 
-// Clean wipes every model's cache section inside this context. Per-repo
-// isolation is only used for Get/Set (avoiding accidental key collisions
-// between repositories that happen to encode the same SQL); invalidation
-// fans out to the whole request context because cross-repo dependencies —
-// virtual columns, JOINs — make per-repo clean unsafe. See docs/features/cache.md
-// for the rationale.
+// Clean wipes every model's cache section inside this context — the
+// invalidation of a write whose table is unknown. Per-repo isolation is only
+// used for Get/Set (avoiding accidental key collisions between repositories
+// that happen to encode the same SQL). See docs/features/cache.md for the
+// rationale.
 func (s *cacheStorage) Clean() {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	for k := range s.c {
 		delete(s.c, k)
 	}
+	clear(s.tables)
+}
+
+// CleanTable wipes the cache sections reading table: those whose tables
+// include it and those of unknown tables. Cross-repo dependencies — virtual
+// columns, JOINs — are part of the tables of a section, so a write through
+// one repository reaches the sections of the others reading its table.
+func (s *cacheStorage) CleanTable(table string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for modelKey := range s.c {
+		tables, ok := s.tables[modelKey]
+		if ok && !slices.ContainsFunc(tables, func(t string) bool { return strings.EqualFold(t, table) }) {
+			continue
+		}
+		delete(s.c, modelKey)
+		delete(s.tables, modelKey)
+	}
 }
 
 func WrapContext(ctx context.Context) context.Context {
-	return context.WithValue(ctx, ctxCacheKey, &cacheStorage{mtx: &sync.Mutex{}, c: make(map[string]map[string]any)})
+	return context.WithValue(ctx, ctxCacheKey, &cacheStorage{
+		mtx:    &sync.Mutex{},
+		c:      make(map[string]map[string]any),
+		tables: make(map[string][]string),
+	})
 }
@@ -209,3 +209,20 @@ func TestNewCtxCache(t *testing.T) {
 	assert.NotNil(t, cache.mtx, "Expected non-nil mutex in cacheStorage")
 	assert.NotNil(t, cache.c, "Expected empty map in cacheStorage")
 }
+
+func TestCacheStorageCleanTable(t *testing.T) {
+	cs := &cacheStorage{
+		mtx: &sync.Mutex{},
+		c:   make(map[string]map[string]any),
+	}
+	cs.Set("users", "select users", "row-1", "users")
+	cs.Set("posts", "select posts", "row-a", "posts", "Users")
+	cs.Set("tags", "select tags", "row-t", "tags")
+	cs.Set("raw", "select raw", "row-r")
+
+	cs.CleanTable("users")
+	assert.Equal(t, map[string]map[string]any{
+		"tags": {"select tags": "row-t"},
+	}, cs.c, "a write to users drops its section, those depending on it and those of unknown tables")
+	assert.Equal(t, map[string][]string{"tags": {"tags"}}, cs.tables)
+}
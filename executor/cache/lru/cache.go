@@ -0,0 +1,109 @@
+package lru
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/insei/gerpo/executor/cache/types"
+	extypes "github.com/insei/gerpo/executor/types"
+)
+
+// Cache is the cache.Storage of one repository, made by Store.Cache.
+//
+// Reads inside a transaction bypass it: they may see rows the transaction
+// has not committed, which must not reach other requests, and a cached
+// value would hide the transaction's own writes from it.
+type Cache struct {
+	store *Store
+	// key prefixes the keys of the entries so that equal statements of two
+	// repositories do not share one.
+	key    string
+	table  string
+	tables []string
+	tags   []string
+	ttl    time.Duration
+}
+
+func (c *Cache) Get(ctx context.Context, statement string, statementArgs ...any) (any, error) {
+	if _, ok := executor.TxFromContext(ctx); ok {
+		return nil, types.ErrNotFound
+	}
+	value, epoch, ok := c.store.get(c.entryKey(ctx, statement, statementArgs))
+	if scope := cache.ScopeFromContext(ctx); scope != nil {
+		scope.Store(c, epoch)
+	}
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *Cache) Set(ctx context.Context, value any, statement string, statementArgs ...any) {
+	if _, ok := executor.TxFromContext(ctx); ok {
+		return
+	}
+	ttl, tags := c.ttl, c.tags
+	if control := cache.ControlFromContext(ctx); control.TTL > 0 || len(control.Tags) > 0 {
+		if control.TTL > 0 {
+			ttl = control.TTL
+		}
+		tags = append(slices.Clone(tags), control.Tags...)
+	}
+	// The rows were read after the miss recorded in the scope of the read; a
+	// write since then makes them stale. Without a scope they are stored.
+	epoch := anyEpoch
+	if scope := cache.ScopeFromContext(ctx); scope != nil {
+		if e, ok := scope.Load(c); ok {
+			epoch = e.(uint64)
+		}
+	}
+	c.store.set(c.entryKey(ctx, statement, statementArgs), value, ttl, c.tables, tags, epoch)
+}
+
+// entryKey is the key of the entry of a statement. The tags of the read take
+// part in it: an entry stored without them would not be dropped by
+// InvalidateTags, so a tagged read must never be served one.
+func (c *Cache) entryKey(ctx context.Context, statement string, statementArgs []any) string {
+	key := c.key + cache.Key(statement, statementArgs...)
+	for _, tag := range cache.ControlFromContext(ctx).Tags {
+		key += "\x00tag:" + tag
+	}
+	return key
+}
+
+// Bind implements cache.Binder: it returns a copy of c reading the table of
+// the repository, whose entries also read the dependencies the repository
+// derives from its JOINs and virtual columns, next to those declared with
+// WithDependencies.
+func (c *Cache) Bind(table string, dependencies []string) cache.Storage {
+	bound := *c
+	if t := normalizeTable(table); t != "" {
+		bound.table = t
+	}
+	bound.tables = addTables(nil, bound.table)
+	for _, t := range c.tables {
+		if t != c.table {
+			bound.tables = addTables(bound.tables, t)
+		}
+	}
+	bound.tables = addTables(bound.tables, dependencies...)
+	return &bound
+}
+
+// Clean invalidates the entries that read the table of c, those of other
+// repositories depending on it included. Inside a transaction it does so
+// again once the transaction commits, dropping what other requests cached
+// from the rows as they were before it.
+func (c *Cache) Clean(ctx context.Context) {
+	c.store.InvalidateTables(c.table)
+	tx, ok := executor.TxFromContext(ctx)
+	if !ok {
+		return
+	}
+	if callbacks, ok := tx.(extypes.TxCallbacks); ok {
+		callbacks.OnCommit(func() { c.store.InvalidateTables(c.table) })
+	}
+}
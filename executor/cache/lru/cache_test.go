@@ -0,0 +1,153 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/insei/gerpo/executor/cache/types"
+	extypes "github.com/insei/gerpo/executor/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// callbacksTx is a Tx collecting its OnCommit functions.
+type callbacksTx struct {
+	extypes.Tx
+	onCommit []func()
+}
+
+func (t *callbacksTx) OnCommit(fn func())   { t.onCommit = append(t.onCommit, fn) }
+func (t *callbacksTx) OnRollback(fn func()) {}
+
+func TestCache_Tx(t *testing.T) {
+	s, _ := newTestStore()
+	users := s.Cache("users")
+	tx := &callbacksTx{}
+	txCtx := executor.WithTx(context.Background(), tx)
+
+	users.Set(context.Background(), "committed", "SELECT")
+	_, err := users.Get(txCtx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "reads inside a tx bypass the cache")
+	users.Set(txCtx, "uncommitted", "SELECT 2")
+	assert.Equal(t, 1, s.Len(), "reads inside a tx are not cached")
+
+	users.Clean(txCtx)
+	assert.Zero(t, s.Len())
+	assert.Len(t, tx.onCommit, 1)
+
+	// Another request caches the rows as they were before the commit.
+	users.Set(context.Background(), "stale", "SELECT")
+	for _, fn := range tx.onCommit {
+		fn()
+	}
+	_, err = users.Get(context.Background(), "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestCache_Bind(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore()
+	users := s.Cache("users")
+	posts := s.Cache("posts", WithDependencies("tags")).Bind("posts", []string{"Users", "tags"})
+	comments := s.Cache("comments")
+
+	posts.Set(ctx, "p", "SELECT")
+	assert.Equal(t, []string{"posts", "tags", "users"}, posts.(*Cache).tables)
+
+	users.Clean(ctx)
+	_, err := posts.Get(ctx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "dependencies derived by the repository invalidate too")
+
+	posts.Set(ctx, "p", "SELECT")
+	comments.Clean(ctx)
+	_, err = posts.Get(ctx, "SELECT")
+	assert.NoError(t, err)
+}
+
+func TestCache_Control(t *testing.T) {
+	ctx := context.Background()
+	s, clk := newTestStore(WithTTL(time.Hour))
+	users := s.Cache("users", WithTags("users"))
+
+	user1 := cache.WithControl(ctx, cache.Control{TTL: time.Minute, Tags: []string{"user:1"}})
+	user2 := cache.WithControl(ctx, cache.Control{Tags: []string{"user:2"}})
+	users.Set(user1, "u1", "SELECT 1")
+	users.Set(user2, "u2", "SELECT 2")
+	users.Set(ctx, "all", "SELECT")
+	assert.Equal(t, []string{"users"}, users.tags, "tags of a read stay with its entry")
+
+	s.InvalidateTags("user:2")
+	_, err := users.Get(user2, "SELECT 2")
+	assert.ErrorIs(t, err, types.ErrNotFound, "InvalidateTags drops the reads tagged through CacheKey")
+	_, err = users.Get(user1, "SELECT 1")
+	assert.NoError(t, err)
+
+	clk.t = clk.t.Add(time.Minute)
+	_, err = users.Get(user1, "SELECT 1")
+	assert.ErrorIs(t, err, types.ErrNotFound, "CacheTTL overrides the TTL of the cache")
+	_, err = users.Get(ctx, "SELECT")
+	assert.NoError(t, err)
+
+	s.InvalidateTags("users")
+	assert.Zero(t, s.Len())
+}
+
+func TestCache_InvalidatedFill(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore()
+	users := s.Cache("").Bind("users", nil)
+	posts := s.Cache("posts")
+
+	read := cache.WithScope(ctx)
+	_, err := users.Get(read, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	posts.Clean(ctx)
+	users.Set(read, "fresh", "SELECT")
+	_, err = users.Get(ctx, "SELECT")
+	assert.NoError(t, err, "a write to another table keeps the fill")
+
+	read = cache.WithScope(ctx)
+	_, _ = users.Get(read, "SELECT 2")
+	// A write lands between the miss and the fill of the read; Clean uses the
+	// table Bind gave the cache.
+	users.Clean(ctx)
+	users.Set(read, "stale", "SELECT 2")
+	_, err = users.Get(ctx, "SELECT 2")
+	assert.ErrorIs(t, err, types.ErrNotFound, "rows read before the write are not cached after it")
+
+	read = cache.WithScope(ctx)
+	tagged := cache.WithScope(cache.WithControl(ctx, cache.Control{Tags: []string{"user:1"}}))
+	_, _ = users.Get(read, "SELECT 3")
+	_, _ = users.Get(tagged, "SELECT 4")
+	s.InvalidateTags("users")
+	users.Set(read, "untagged", "SELECT 3")
+	users.Set(tagged, "tagged", "SELECT 4")
+	_, err = users.Get(ctx, "SELECT 3")
+	assert.NoError(t, err, "InvalidateTags drops the fills of tagged entries only")
+	_, err = users.Get(tagged, "SELECT 4")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestCache_TaggedRead(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore()
+	users := s.Cache("users")
+	tagged := cache.WithControl(ctx, cache.Control{Tags: []string{"user:1"}})
+
+	users.Set(ctx, "untagged", "SELECT")
+	_, err := users.Get(tagged, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "a tagged read is not served the entry of an untagged one")
+	users.Set(tagged, "tagged", "SELECT")
+	got, err := users.Get(tagged, "SELECT")
+	assert.NoError(t, err)
+	assert.Equal(t, "tagged", got)
+
+	s.InvalidateTags("user:1")
+	_, err = users.Get(tagged, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "InvalidateTags drops the entry of the tagged read")
+	got, err = users.Get(ctx, "SELECT")
+	assert.NoError(t, err)
+	assert.Equal(t, "untagged", got)
+}
@@ -0,0 +1,77 @@
+package lru
+
+import "time"
+
+type storeOption func(s *Store)
+
+// apply implements the Option interface for storeOption.
+// It calls the underlying function with the given *Store.
+func (f storeOption) apply(s *Store) {
+	f(s)
+}
+
+type Option interface {
+	apply(s *Store)
+}
+
+// WithMaxEntries bounds the number of entries of the Store; past it, the
+// least recently used entry is dropped. 10 000 by default.
+func WithMaxEntries(n int) Option {
+	return storeOption(func(s *Store) {
+		if n > 0 {
+			s.maxEntries = n
+		}
+	})
+}
+
+// WithTTL sets how long an entry lives for the caches that do not set their
+// own with WithCacheTTL. A minute by default.
+func WithTTL(ttl time.Duration) Option {
+	return storeOption(func(s *Store) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	})
+}
+
+type cacheOption func(c *Cache)
+
+// apply implements the CacheOption interface for cacheOption.
+// It calls the underlying function with the given *Cache.
+func (f cacheOption) apply(c *Cache) {
+	f(c)
+}
+
+type CacheOption interface {
+	apply(c *Cache)
+}
+
+// WithCacheTTL sets how long the entries of the Cache live, overriding the
+// TTL of the Store.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return cacheOption(func(c *Cache) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	})
+}
+
+// WithDependencies declares the other tables the reads of the repository
+// touch — through JOINs or virtual columns — so that a write to any of them
+// through another repository invalidates its entries too.
+func WithDependencies(tables ...string) CacheOption {
+	return cacheOption(func(c *Cache) {
+		c.tables = addTables(c.tables, tables...)
+	})
+}
+
+// WithTags tags the entries of the Cache; Store.InvalidateTags drops them.
+func WithTags(tags ...string) CacheOption {
+	return cacheOption(func(c *Cache) {
+		for _, tag := range tags {
+			if tag != "" {
+				c.tags = append(c.tags, tag)
+			}
+		}
+	})
+}
@@ -0,0 +1,235 @@
+// Package lru is an in-process cache.Storage shared by every request: a
+// Store bounded in size, whose entries expire after a TTL and are invalidated
+// per table — a write through a repository drops the cached reads of its
+// table and of the repositories that declared a dependency on it — or per tag.
+package lru
+
+import (
+	"container/list"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxEntries = 10_000
+	defaultTTL        = time.Minute
+)
+
+// Store holds the entries of every Cache made from it. One Store is meant to
+// be shared by all the repositories of a database, so that a write through
+// one of them reaches the entries of the others.
+type Store struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	// order lists the entries, the most recently used first.
+	order   *list.List
+	entries map[string]*list.Element
+	byTable map[string]map[string]struct{}
+	byTag   map[string]map[string]struct{}
+
+	// epoch counts the invalidations. invalidated holds the epoch of the last
+	// one of each table, tagged and purged those of the last InvalidateTags
+	// and Purge: a fill whose miss predates them is dropped.
+	epoch       uint64
+	invalidated map[string]uint64
+	tagged      uint64
+	purged      uint64
+}
+
+type entry struct {
+	key     string
+	value   any
+	expires time.Time
+	tables  []string
+	tags    []string
+}
+
+// NewStore returns an empty Store holding up to 10 000 entries for a minute
+// each unless opts say otherwise.
+func NewStore(opts ...Option) *Store {
+	s := &Store{
+		maxEntries: defaultMaxEntries,
+		ttl:        defaultTTL,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		byTable:    make(map[string]map[string]struct{}),
+		byTag:      make(map[string]map[string]struct{}),
+
+		invalidated: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	return s
+}
+
+// Cache returns the cache.Storage of a repository reading table. Its entries
+// are invalidated by a write to table or to any table of WithDependencies,
+// and by InvalidateTags with any tag of WithTags. The repository binds the
+// cache to its own table, which replaces table: it may be left empty.
+func (s *Store) Cache(table string, opts ...CacheOption) *Cache {
+	c := &Cache{
+		store: s,
+		key:   uuid.New().String(),
+		table: normalizeTable(table),
+		ttl:   s.ttl,
+	}
+	c.tables = addTables(nil, c.table)
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	return c
+}
+
+// InvalidateTables drops the entries that read any of tables.
+func (s *Store) InvalidateTables(tables ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	for _, table := range tables {
+		table = normalizeTable(table)
+		s.invalidated[table] = s.epoch
+		for key := range s.byTable[table] {
+			s.remove(s.entries[key])
+		}
+	}
+}
+
+// InvalidateTags drops the entries of the caches tagged with any of tags, and
+// those of the reads tagged with them (query.Cacheable's CacheKey).
+func (s *Store) InvalidateTags(tags ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	s.tagged = s.epoch
+	for _, tag := range tags {
+		for key := range s.byTag[tag] {
+			s.remove(s.entries[key])
+		}
+	}
+}
+
+// Purge drops every entry.
+func (s *Store) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	s.purged = s.epoch
+	s.order.Init()
+	clear(s.entries)
+	clear(s.byTable)
+	clear(s.byTag)
+}
+
+// Len returns the number of entries, expired ones not yet dropped included.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// get returns the value of key together with the epoch it was looked up
+// at, which set compares to the invalidations that came after.
+func (s *Store) get(key string) (any, uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, s.epoch, false
+	}
+	e := el.Value.(*entry)
+	if !s.now().Before(e.expires) {
+		s.remove(el)
+		return nil, s.epoch, false
+	}
+	s.order.MoveToFront(el)
+	return e.value, s.epoch, true
+}
+
+// anyEpoch makes set store its entry whatever was invalidated before.
+const anyEpoch = ^uint64(0)
+
+// set stores value under key unless one of tables, or any tag when the entry
+// has tags, was invalidated after epoch: the value was read before that
+// write and would outlive it.
+func (s *Store) set(key string, value any, ttl time.Duration, tables, tags []string, epoch uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.purged > epoch || len(tags) > 0 && s.tagged > epoch {
+		return
+	}
+	for _, table := range tables {
+		if s.invalidated[table] > epoch {
+			return
+		}
+	}
+	if el, ok := s.entries[key]; ok {
+		s.remove(el)
+	}
+	e := &entry{key: key, value: value, expires: s.now().Add(ttl), tables: tables, tags: tags}
+	s.entries[key] = s.order.PushFront(e)
+	for _, table := range tables {
+		index(s.byTable, table, key)
+	}
+	for _, tag := range tags {
+		index(s.byTag, tag, key)
+	}
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+}
+
+// remove drops the entry of el from the list and the indexes. s.mu is held.
+func (s *Store) remove(el *list.Element) {
+	if el == nil {
+		return
+	}
+	e := s.order.Remove(el).(*entry)
+	delete(s.entries, e.key)
+	for _, table := range e.tables {
+		unindex(s.byTable, table, e.key)
+	}
+	for _, tag := range e.tags {
+		unindex(s.byTag, tag, e.key)
+	}
+}
+
+// addTables appends the names of tables missing from list to it.
+func addTables(list []string, tables ...string) []string {
+	for _, t := range tables {
+		if t = normalizeTable(t); t != "" && !slices.Contains(list, t) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+func index(m map[string]map[string]struct{}, name, key string) {
+	keys, ok := m[name]
+	if !ok {
+		keys = make(map[string]struct{})
+		m[name] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+func unindex(m map[string]map[string]struct{}, name, key string) {
+	delete(m[name], key)
+	if len(m[name]) == 0 {
+		delete(m, name)
+	}
+}
+
+// normalizeTable folds the case of a table name; names are otherwise
+// compared as written, a schema-qualified name included.
+func normalizeTable(table string) string {
+	return strings.ToLower(table)
+}
@@ -0,0 +1,124 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/insei/gerpo/executor/cache/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a settable Store.now.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestStore(opts ...Option) (*Store, *clock) {
+	s := NewStore(opts...)
+	c := &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.now = c.now
+	return s, c
+}
+
+func TestStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	s, clk := newTestStore(WithTTL(time.Minute))
+	users := s.Cache("users")
+	tags := s.Cache("tags", WithCacheTTL(time.Hour))
+
+	users.Set(ctx, "u", "SELECT users")
+	tags.Set(ctx, "t", "SELECT tags")
+
+	clk.t = clk.t.Add(59 * time.Second)
+	got, err := users.Get(ctx, "SELECT users")
+	assert.NoError(t, err)
+	assert.Equal(t, "u", got)
+
+	clk.t = clk.t.Add(time.Second)
+	_, err = users.Get(ctx, "SELECT users")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	got, err = tags.Get(ctx, "SELECT tags")
+	assert.NoError(t, err)
+	assert.Equal(t, "t", got)
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestStore_MaxEntries(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(WithMaxEntries(2))
+	c := s.Cache("users")
+
+	c.Set(ctx, 1, "SELECT", 1)
+	c.Set(ctx, 2, "SELECT", 2)
+	_, err := c.Get(ctx, "SELECT", 1) // 1 is now the most recently used
+	assert.NoError(t, err)
+	c.Set(ctx, 3, "SELECT", 3)
+
+	assert.Equal(t, 2, s.Len())
+	_, err = c.Get(ctx, "SELECT", 2)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	for _, arg := range []int{1, 3} {
+		got, err := c.Get(ctx, "SELECT", arg)
+		assert.NoError(t, err)
+		assert.Equal(t, arg, got)
+	}
+}
+
+func TestStore_KeysPerCache(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore()
+	a, b := s.Cache("users"), s.Cache("users")
+
+	a.Set(ctx, "a", "SELECT")
+	_, err := b.Get(ctx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestStore_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore()
+	users := s.Cache("users")
+	posts := s.Cache("posts", WithDependencies("Users"), WithTags("feed"))
+	tags := s.Cache("tags", WithTags("catalog"))
+	fill := func() {
+		s.Purge()
+		users.Set(ctx, "u", "SELECT users")
+		posts.Set(ctx, "p", "SELECT posts")
+		tags.Set(ctx, "t", "SELECT tags")
+	}
+	cached := func() (got []string) {
+		for _, c := range []struct {
+			name  string
+			cache *Cache
+		}{{"users", users}, {"posts", posts}, {"tags", tags}} {
+			if _, err := c.cache.Get(ctx, "SELECT "+c.name); err == nil {
+				got = append(got, c.name)
+			}
+		}
+		return got
+	}
+
+	fill()
+	users.Clean(ctx)
+	assert.Equal(t, []string{"tags"}, cached(), "a write to users drops the posts depending on it")
+
+	fill()
+	posts.Clean(ctx)
+	assert.Equal(t, []string{"users", "tags"}, cached())
+
+	fill()
+	s.InvalidateTables("TAGS")
+	assert.Equal(t, []string{"users", "posts"}, cached())
+
+	fill()
+	s.InvalidateTags("feed", "unknown")
+	assert.Equal(t, []string{"users", "tags"}, cached())
+
+	fill()
+	s.Purge()
+	assert.Empty(t, cached())
+	assert.Zero(t, s.Len())
+	assert.Empty(t, s.byTable)
+	assert.Empty(t, s.byTag)
+}
@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/insei/gerpo/executor/cache/types"
+	extypes "github.com/insei/gerpo/executor/types"
+	"github.com/insei/gerpo/logger"
+)
+
+type cacheConfig struct {
+	table  string
+	tables []string
+	ttl    time.Duration
+}
+
+// Cache is the cache.Storage of a repository of TModel, made by NewCache.
+// Entries of equal statements are shared by the replicas: their keys hash the
+// model type, the table and the statement, not an instance identifier.
+//
+// Reads inside a transaction bypass it, as they do with lru.Cache: they may
+// see rows the transaction has not committed.
+type Cache[TModel any] struct {
+	store *Store
+	// model names TModel in the keys, so that two repositories of one table
+	// scanning different models do not decode each other's entries.
+	model string
+	cacheConfig
+}
+
+// NewCache returns the cache.Storage of a repository of TModel reading table.
+// Its entries are invalidated by a write to table, to the tables the
+// repository derives from its JOINs and virtual columns (cache.Binder) and to
+// those of WithDependencies. The repository binds the cache to its own table,
+// which replaces table: it may be left empty.
+func NewCache[TModel any](store *Store, table string, opts ...CacheOption) *Cache[TModel] {
+	c := &Cache[TModel]{
+		store: store,
+		model: reflect.TypeFor[TModel]().String(),
+		cacheConfig: cacheConfig{
+			ttl: store.ttl,
+		},
+	}
+	c.table = normalizeTable(table)
+	c.tables = addTables(nil, c.table)
+	for _, opt := range opts {
+		opt.apply(&c.cacheConfig)
+	}
+	return c
+}
+
+func (c *Cache[TModel]) Get(ctx context.Context, statement string, statementArgs ...any) (any, error) {
+	if _, ok := executor.TxFromContext(ctx); ok {
+		return nil, types.ErrNotFound
+	}
+	key, err := c.key(ctx, statement, statementArgs)
+	if err != nil {
+		c.warn(ctx, "get", err)
+		return nil, types.ErrNotFound
+	}
+	scope := cache.ScopeFromContext(ctx)
+	if scope != nil {
+		scope.Store(c, miss{key: key})
+	}
+	data, err := c.store.fetch(ctx, key)
+	if err == nil && data == nil {
+		var locked bool
+		data, locked, err = c.store.fill(ctx, key)
+		if locked && scope != nil {
+			scope.Store(c, miss{key: key, locked: true})
+		}
+	}
+	if err != nil {
+		c.warn(ctx, "get", err)
+		return nil, types.ErrNotFound
+	}
+	if data == nil {
+		return nil, types.ErrNotFound
+	}
+	value, err := decode[TModel](c.store.codec, data)
+	if err != nil {
+		c.warn(ctx, "decode", err)
+		return nil, types.ErrNotFound
+	}
+	return value, nil
+}
+
+func (c *Cache[TModel]) Set(ctx context.Context, value any, statement string, statementArgs ...any) {
+	if _, ok := executor.TxFromContext(ctx); ok {
+		return
+	}
+	data, err := encode[TModel](c.store.codec, value)
+	if err != nil {
+		c.warn(ctx, "encode", err)
+		return
+	}
+	ttl := c.ttl
+	if control := cache.ControlFromContext(ctx); control.TTL > 0 {
+		ttl = control.TTL
+	}
+	key, err := c.fillKey(ctx, statement, statementArgs)
+	if err == nil {
+		err = c.store.store(ctx, key, data, ttl)
+	}
+	if err != nil {
+		c.warn(ctx, "set", err)
+	}
+}
+
+// miss is what Get records in the cache.Scope of a read: the key it looked
+// up, and whether the read holds the fill lock of that key.
+type miss struct {
+	key    string
+	locked bool
+}
+
+// Release implements cache.Releaser: it drops the fill lock the Get of the
+// read took, so that the readers polling for the entry stop waiting and query
+// the database themselves. Without a scope there is no lock to tell apart.
+func (c *Cache[TModel]) Release(ctx context.Context, _ string, _ ...any) {
+	scope := cache.ScopeFromContext(ctx)
+	if scope == nil {
+		return
+	}
+	m, ok := scope.Load(c)
+	if !ok || !m.(miss).locked {
+		return
+	}
+	if err := c.store.unlock(ctx, m.(miss).key); err != nil {
+		c.warn(ctx, "release", err)
+	}
+}
+
+// fillKey returns the key Set stores the rows of a read under: the one its Get
+// missed, recorded in the cache.Scope of the read. Rows read before a write
+// thus land under the generations the write has since moved past, never
+// under the new ones. Without a scope the key is computed afresh.
+func (c *Cache[TModel]) fillKey(ctx context.Context, statement string, statementArgs []any) (string, error) {
+	if scope := cache.ScopeFromContext(ctx); scope != nil {
+		if m, ok := scope.Load(c); ok {
+			return m.(miss).key, nil
+		}
+	}
+	return c.key(ctx, statement, statementArgs)
+}
+
+// Bind implements cache.Binder: it returns a copy of c reading the table of
+// the repository, whose entries also read the dependencies the repository
+// derives from its JOINs and virtual columns.
+func (c *Cache[TModel]) Bind(table string, dependencies []string) cache.Storage {
+	bound := *c
+	if t := normalizeTable(table); t != "" {
+		bound.table = t
+	}
+	bound.tables = addTables(nil, bound.table)
+	for _, t := range c.tables {
+		if t != c.table {
+			bound.tables = addTables(bound.tables, t)
+		}
+	}
+	bound.tables = addTables(bound.tables, dependencies...)
+	return &bound
+}
+
+// Clean invalidates the entries reading the table of c on every replica.
+// Inside a transaction it does so again once the transaction commits.
+func (c *Cache[TModel]) Clean(ctx context.Context) {
+	if err := c.store.InvalidateTables(ctx, c.table); err != nil {
+		c.warn(ctx, "invalidate", err)
+	}
+	tx, ok := executor.TxFromContext(ctx)
+	if !ok {
+		return
+	}
+	if callbacks, ok := tx.(extypes.TxCallbacks); ok {
+		ctx := context.WithoutCancel(ctx)
+		callbacks.OnCommit(func() {
+			if err := c.store.InvalidateTables(ctx, c.table); err != nil {
+				c.warn(ctx, "invalidate", err)
+			}
+		})
+	}
+}
+
+// key returns the key of the entry of statement under the current
+// generations of the tables of c and of the tags of the read; a tagged read
+// shares its entries with the reads of the same tags only.
+func (c *Cache[TModel]) key(ctx context.Context, statement string, statementArgs []any) (string, error) {
+	names := c.tables
+	if tags := cache.ControlFromContext(ctx).Tags; len(tags) > 0 {
+		names = slices.Clone(names)
+		for _, tag := range tags {
+			names = append(names, tagName(tag))
+		}
+	}
+	gens, err := c.store.generations(ctx, names)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{c.model, c.table, cache.Key(statement, statementArgs...)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for i, name := range names {
+		h.Write([]byte(name + "=" + strconv.FormatInt(gens[i], 10)))
+		h.Write([]byte{0})
+	}
+	return c.store.prefix + "entry:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Cache[TModel]) warn(ctx context.Context, op string, err error) {
+	c.store.log.Ctx(ctx).Warn(op+" failed",
+		logger.String("engine", "redis"),
+		logger.String("table", c.table),
+		logger.String("details", err.Error()))
+}
+
+// normalizeTable folds the case of a table name; names are otherwise
+// compared as written, a schema-qualified name included.
+func normalizeTable(table string) string {
+	return strings.ToLower(table)
+}
+
+// addTables appends the names of tables missing from list to it.
+func addTables(list []string, tables ...string) []string {
+	for _, t := range tables {
+		if t = normalizeTable(t); t != "" && !slices.Contains(list, t) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+// tagName is the name of the generation of tag, kept apart from the tables.
+func tagName(tag string) string {
+	return "#" + tag
+}
@@ -0,0 +1,305 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/insei/gerpo/executor/cache/types"
+	extypes "github.com/insei/gerpo/executor/types"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+// newTestStore returns a Store on srv, subscribed to its channel.
+func newTestStore(t *testing.T, srv *miniredis.Miniredis, opts ...Option) *Store {
+	t.Helper()
+	// No retries: TestCache_ServerDown would wait for their backoff.
+	client := goredis.NewClient(&goredis.Options{Addr: srv.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	s := NewStore(client, opts...)
+	t.Cleanup(func() { _ = s.Close() })
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.subscribed
+	}, time.Second, time.Millisecond)
+	return s
+}
+
+func TestCache_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache[user](newTestStore(t, miniredis.RunT(t)), "users")
+
+	one := user{ID: 1, Name: "Ann"}
+	list := []*user{{ID: 1, Name: "Ann"}, {ID: 2, Name: "Bob"}}
+	page := executor.Page[user]{Models: list, Total: 7}
+	for _, tt := range []struct {
+		name  string
+		value any
+	}{
+		{"model", one},
+		{"list", list},
+		{"page", page},
+		{"count", uint64(42)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c.Set(ctx, tt.value, "SELECT "+tt.name, 1)
+			got, err := c.Get(ctx, "SELECT "+tt.name, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, got)
+		})
+	}
+
+	got, err := c.Get(ctx, "SELECT list", 1)
+	require.NoError(t, err)
+	assert.NotSame(t, list[0], got.([]*user)[0], "entries decode into fresh values")
+
+	_, err = c.Get(ctx, "SELECT list", 2)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestCache_SharedByReplicas(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	a, b := newTestStore(t, srv, WithFillLock(0)), newTestStore(t, srv, WithFillLock(0))
+
+	NewCache[user](a, "users").Set(ctx, uint64(1), "SELECT count(*) FROM users")
+	got, err := NewCache[user](b, "users").Get(ctx, "SELECT count(*) FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), got)
+
+	type other struct{ ID int }
+	_, err = NewCache[other](b, "users").Get(ctx, "SELECT count(*) FROM users")
+	assert.ErrorIs(t, err, types.ErrNotFound, "keys tell the models apart")
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	a, b := newTestStore(t, srv, WithFillLock(0)), newTestStore(t, srv, WithFillLock(0))
+
+	usersA := NewCache[user](a, "users")
+	postsA := NewCache[user](a, "posts").Bind("posts", []string{"Users"})
+	tagsA := NewCache[user](a, "tags", WithDependencies("labels"))
+	fill := func() {
+		for _, c := range []interface {
+			Set(context.Context, any, string, ...any)
+		}{usersA, postsA, tagsA} {
+			c.Set(ctx, uint64(1), "SELECT")
+		}
+	}
+	cached := func() (n int) {
+		for _, c := range []interface {
+			Get(context.Context, string, ...any) (any, error)
+		}{usersA, postsA, tagsA} {
+			if _, err := c.Get(ctx, "SELECT"); err == nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	fill()
+	assert.Equal(t, 3, cached())
+
+	// A write on replica b reaches the generations replica a holds.
+	NewCache[user](b, "").Bind("users", nil).Clean(ctx)
+	assert.Eventually(t, func() bool { return cached() == 1 }, time.Second, time.Millisecond,
+		"users and the posts depending on them are dropped, tags kept")
+
+	require.NoError(t, b.InvalidateTables(ctx, "LABELS"))
+	assert.Eventually(t, func() bool { return cached() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	s := newTestStore(t, srv, WithTTL(time.Hour), WithFillLock(0))
+	users := NewCache[user](s, "users")
+	tags := NewCache[user](s, "tags", WithCacheTTL(time.Minute))
+
+	users.Set(ctx, uint64(1), "SELECT")
+	tags.Set(ctx, uint64(1), "SELECT")
+	srv.FastForward(time.Minute)
+
+	_, err := users.Get(ctx, "SELECT")
+	assert.NoError(t, err)
+	_, err = tags.Get(ctx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestCache_Control(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	a, b := newTestStore(t, srv, WithTTL(time.Hour), WithFillLock(0)), newTestStore(t, srv, WithFillLock(0))
+	users := NewCache[user](a, "users")
+	tagged := cache.WithControl(ctx, cache.Control{TTL: time.Minute, Tags: []string{"user:1"}})
+
+	users.Set(tagged, uint64(1), "SELECT")
+	users.Set(ctx, uint64(2), "SELECT")
+	got, err := users.Get(tagged, "SELECT")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), got, "a tagged read shares its entries with the reads of the same tags")
+
+	require.NoError(t, b.InvalidateTags(ctx, "user:1"))
+	assert.Eventually(t, func() bool {
+		_, err := users.Get(tagged, "SELECT")
+		return err != nil
+	}, time.Second, time.Millisecond, "InvalidateTags reaches the other replicas")
+	_, err = users.Get(ctx, "SELECT")
+	assert.NoError(t, err, "untagged entries are kept")
+
+	users.Set(tagged, uint64(3), "SELECT")
+	srv.FastForward(time.Minute)
+	_, err = users.Get(tagged, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "CacheTTL overrides the TTL of the store")
+	_, err = users.Get(ctx, "SELECT")
+	assert.NoError(t, err)
+}
+
+func TestCache_FillLock(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	a, b := newTestStore(t, srv, WithFillLock(time.Second)), newTestStore(t, srv, WithFillLock(time.Second))
+	filler, waiter := NewCache[user](a, "users"), NewCache[user](b, "users")
+
+	_, err := filler.Get(ctx, "SELECT")
+	require.ErrorIs(t, err, types.ErrNotFound, "the first reader missing the entry fills it")
+
+	var wg sync.WaitGroup
+	got := make([]any, 3)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], _ = waiter.Get(ctx, "SELECT")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	filler.Set(ctx, uint64(5), "SELECT")
+	wg.Wait()
+	assert.Equal(t, []any{uint64(5), uint64(5), uint64(5)}, got, "the others wait for the filler")
+	assert.False(t, srv.Exists(a.lockKey(mustKey(t, filler, "SELECT"))), "Set releases the lock")
+
+	// A filler that never sets makes the others wait for the lock only.
+	_, err = filler.Get(ctx, "SELECT 2")
+	require.ErrorIs(t, err, types.ErrNotFound)
+	shortCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = waiter.Get(shortCtx, "SELECT 2")
+	assert.ErrorIs(t, err, types.ErrNotFound, "a cancelled wait is a miss")
+}
+
+func TestCache_Release(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	a, b := newTestStore(t, srv, WithFillLock(5*time.Second)), newTestStore(t, srv, WithFillLock(5*time.Second))
+	filler, waiter := NewCache[user](a, "users"), NewCache[user](b, "users")
+	lock := a.lockKey(mustKey(t, filler, "SELECT"))
+
+	read := cache.WithScope(ctx)
+	_, err := filler.Get(read, "SELECT")
+	require.ErrorIs(t, err, types.ErrNotFound)
+	waiting := cache.WithScope(ctx)
+	done := make(chan error)
+	go func() {
+		_, err := waiter.Get(waiting, "SELECT")
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	waiter.Release(waiting, "SELECT")
+	assert.True(t, srv.Exists(lock), "a reader not holding the lock leaves it")
+
+	filler.Release(read, "SELECT")
+	assert.False(t, srv.Exists(lock))
+	select {
+	case err = <-done:
+		assert.ErrorIs(t, err, types.ErrNotFound, "the waiter misses once the lock is dropped")
+	case <-time.After(time.Second):
+		t.Fatal("the waiter kept waiting for a released lock")
+	}
+}
+
+func TestCache_InvalidatedFill(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t, miniredis.RunT(t))
+	users := NewCache[user](s, "users")
+
+	read := cache.WithScope(ctx)
+	_, err := users.Get(read, "SELECT")
+	require.ErrorIs(t, err, types.ErrNotFound)
+	lock := s.lockKey(mustKey(t, users, "SELECT"))
+
+	// A write lands between the miss and the fill of the read.
+	require.NoError(t, s.InvalidateTables(ctx, "users"))
+	users.Set(read, uint64(1), "SELECT")
+
+	_, err = users.Get(ctx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "rows read before the write are not served after it")
+	_, err = s.client.Get(ctx, lock).Result()
+	assert.ErrorIs(t, err, goredis.Nil, "Set releases the lock the miss took")
+}
+
+// callbacksTx is a Tx collecting its OnCommit functions.
+type callbacksTx struct {
+	extypes.Tx
+	onCommit []func()
+}
+
+func (t *callbacksTx) OnCommit(fn func())   { t.onCommit = append(t.onCommit, fn) }
+func (t *callbacksTx) OnRollback(fn func()) {}
+
+func TestCache_Tx(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	users := NewCache[user](newTestStore(t, srv, WithFillLock(0)), "users")
+	tx := &callbacksTx{}
+	txCtx := executor.WithTx(ctx, tx)
+
+	users.Set(ctx, uint64(1), "SELECT")
+	_, err := users.Get(txCtx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "reads inside a tx bypass the cache")
+	users.Set(txCtx, uint64(2), "SELECT 2")
+	_, err = users.Get(ctx, "SELECT 2")
+	assert.ErrorIs(t, err, types.ErrNotFound, "reads inside a tx are not cached")
+
+	users.Clean(txCtx)
+	require.Len(t, tx.onCommit, 1)
+	users.Set(ctx, uint64(3), "SELECT")
+	tx.onCommit[0]()
+	assert.Eventually(t, func() bool {
+		_, err := users.Get(ctx, "SELECT")
+		return err != nil
+	}, time.Second, time.Millisecond, "the commit drops what was cached before it")
+}
+
+func TestCache_ServerDown(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	users := NewCache[user](newTestStore(t, srv), "users")
+	srv.Close()
+
+	users.Set(ctx, uint64(1), "SELECT")
+	_, err := users.Get(ctx, "SELECT")
+	assert.ErrorIs(t, err, types.ErrNotFound, "server errors are misses")
+	users.Clean(ctx)
+}
+
+func mustKey(t *testing.T, c *Cache[user], statement string) string {
+	t.Helper()
+	key, err := c.key(context.Background(), statement, nil)
+	require.NoError(t, err)
+	return key
+}
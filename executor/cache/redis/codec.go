@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/insei/gerpo/executor"
+)
+
+// Codec serializes the results the executor caches: TModel, []*TModel,
+// executor.Page[TModel] and uint64. Unmarshal receives a pointer to one of
+// them.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON is the default Codec. It round-trips the exported fields of a model
+// only; models with unexported state need a Codec of their own.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// The first byte of an entry tells the type of the result that follows.
+const (
+	kindModel byte = 'm'
+	kindList  byte = 'l'
+	kindPage  byte = 'p'
+	kindCount byte = 'c'
+)
+
+func encode[TModel any](codec Codec, value any) ([]byte, error) {
+	var kind byte
+	switch value.(type) {
+	case TModel:
+		kind = kindModel
+	case []*TModel:
+		kind = kindList
+	case executor.Page[TModel]:
+		kind = kindPage
+	case uint64:
+		kind = kindCount
+	default:
+		return nil, fmt.Errorf("unsupported cached type %T", value)
+	}
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{kind}, data...), nil
+}
+
+func decode[TModel any](codec Codec, data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty entry")
+	}
+	switch kind, data := data[0], data[1:]; kind {
+	case kindModel:
+		return unmarshal[TModel](codec, data)
+	case kindList:
+		return unmarshal[[]*TModel](codec, data)
+	case kindPage:
+		return unmarshal[executor.Page[TModel]](codec, data)
+	case kindCount:
+		return unmarshal[uint64](codec, data)
+	default:
+		return nil, fmt.Errorf("unknown entry kind %q", kind)
+	}
+}
+
+func unmarshal[T any](codec Codec, data []byte) (any, error) {
+	var v T
+	if err := codec.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
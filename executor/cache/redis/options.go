@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/insei/gerpo/logger"
+)
+
+type storeOption func(s *Store)
+
+// apply implements the Option interface for storeOption.
+// It calls the underlying function with the given *Store.
+func (f storeOption) apply(s *Store) {
+	f(s)
+}
+
+type Option interface {
+	apply(s *Store)
+}
+
+// WithPrefix sets the prefix of every key the Store writes, "gerpo:" by
+// default. Applications sharing a server keep apart with distinct prefixes.
+func WithPrefix(prefix string) Option {
+	return storeOption(func(s *Store) {
+		if prefix != "" {
+			s.prefix = prefix
+		}
+	})
+}
+
+// WithChannel sets the pub/sub channel invalidations are announced on, the
+// prefix followed by "invalidate" by default.
+func WithChannel(channel string) Option {
+	return storeOption(func(s *Store) {
+		if channel != "" {
+			s.channel = channel
+		}
+	})
+}
+
+// WithTTL sets how long an entry lives for the caches that do not set their
+// own with WithCacheTTL. A minute by default.
+func WithTTL(ttl time.Duration) Option {
+	return storeOption(func(s *Store) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	})
+}
+
+// WithFillLock sets how long the readers missing an entry wait for the one
+// reader filling it — the first to miss, on any replica — before they query
+// the database themselves. They stop waiting sooner when that reader finds
+// no row or fails. 250ms by default; zero or less lets every reader
+// missing the entry query the database.
+func WithFillLock(wait time.Duration) Option {
+	return storeOption(func(s *Store) {
+		s.fillLock = wait
+	})
+}
+
+// WithCodec sets the Codec of the entries, JSON by default.
+func WithCodec(codec Codec) Option {
+	return storeOption(func(s *Store) {
+		if codec != nil {
+			s.codec = codec
+		}
+	})
+}
+
+func WithLogger(log logger.Logger) Option {
+	return storeOption(func(s *Store) {
+		if log != nil {
+			s.log = log
+		}
+	})
+}
+
+type cacheOption func(c *cacheConfig)
+
+// apply implements the CacheOption interface for cacheOption.
+// It calls the underlying function with the given *cacheConfig.
+func (f cacheOption) apply(c *cacheConfig) {
+	f(c)
+}
+
+type CacheOption interface {
+	apply(c *cacheConfig)
+}
+
+// WithCacheTTL sets how long the entries of the Cache live, overriding the
+// TTL of the Store.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return cacheOption(func(c *cacheConfig) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	})
+}
+
+// WithDependencies declares tables the reads of the repository touch that it
+// cannot derive itself — raw SQL without virtual.DependsOn — so that a write
+// to any of them invalidates its entries too.
+func WithDependencies(tables ...string) CacheOption {
+	return cacheOption(func(c *cacheConfig) {
+		c.tables = addTables(c.tables, tables...)
+	})
+}
@@ -0,0 +1,308 @@
+// Package redis is a cache.Storage kept in a Redis-protocol server and shared
+// by every replica of an application. Entries are serialized with a Codec,
+// invalidated per table through generation counters announced over pub/sub,
+// and filled by one caller at a time (see WithFillLock).
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/insei/gerpo/logger"
+)
+
+const (
+	defaultPrefix   = "gerpo:"
+	defaultTTL      = time.Minute
+	defaultFillLock = 250 * time.Millisecond
+	pollInterval    = 10 * time.Millisecond
+	// resubscribeDelay spaces the attempts to read the invalidation channel
+	// while the server is unreachable.
+	resubscribeDelay = 100 * time.Millisecond
+)
+
+// Store is the connection of the caches of one application to the server. An
+// entry key carries the generations of the tables the entry reads; a write
+// increments the generation of its table, so the entries made before it are
+// never read again and expire with their TTL. Each Store keeps the
+// generations it has read and forgets one when a Store of any replica
+// announces its increment on the invalidation channel.
+type Store struct {
+	client   goredis.UniversalClient
+	prefix   string
+	channel  string
+	ttl      time.Duration
+	fillLock time.Duration
+	codec    Codec
+	log      logger.Logger
+
+	mu sync.Mutex
+	// gens holds the generations read while the Store is subscribed to the
+	// channel; epoch counts the times they were forgotten.
+	gens       map[string]int64
+	epoch      uint64
+	subscribed bool
+
+	group  singleflight.Group
+	pubsub *goredis.PubSub
+	closed chan struct{}
+	done   chan struct{}
+}
+
+// NewStore returns a Store talking to client and subscribes it to the
+// invalidation channel until Close.
+func NewStore(client goredis.UniversalClient, opts ...Option) *Store {
+	s := &Store{
+		client:   client,
+		prefix:   defaultPrefix,
+		ttl:      defaultTTL,
+		fillLock: defaultFillLock,
+		codec:    JSON,
+		log:      logger.NoopLogger,
+		gens:     make(map[string]int64),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	if s.channel == "" {
+		s.channel = s.prefix + "invalidate"
+	}
+	s.pubsub = client.Subscribe(context.Background(), s.channel)
+	go s.subscribe()
+	return s
+}
+
+// Close unsubscribes the Store from the invalidation channel. It does not
+// close the client.
+func (s *Store) Close() error {
+	close(s.closed)
+	err := s.pubsub.Close()
+	<-s.done
+	return err
+}
+
+// InvalidateTables increments the generations of tables, dropping the entries
+// that read any of them on every replica.
+func (s *Store) InvalidateTables(ctx context.Context, tables ...string) error {
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = normalizeTable(table)
+	}
+	return s.invalidate(ctx, names)
+}
+
+// InvalidateTags increments the generations of tags, dropping the entries of
+// the reads tagged with any of them (query.Cacheable's CacheKey) on every
+// replica.
+func (s *Store) InvalidateTags(ctx context.Context, tags ...string) error {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tagName(tag)
+	}
+	return s.invalidate(ctx, names)
+}
+
+// invalidate increments the generations of names, the normalized tables and
+// the tag names, and announces them on the channel.
+func (s *Store) invalidate(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	_, err := s.client.TxPipelined(ctx, func(p goredis.Pipeliner) error {
+		for _, name := range names {
+			p.Incr(ctx, s.genKey(name))
+			p.Publish(ctx, s.channel, name)
+		}
+		return nil
+	})
+	s.forget(names...)
+	return err
+}
+
+func (s *Store) genKey(name string) string {
+	return s.prefix + "gen:" + name
+}
+
+func (s *Store) lockKey(key string) string {
+	return s.prefix + "lock:" + key
+}
+
+// generations returns the generations of names, reading from the server
+// those the Store does not hold.
+func (s *Store) generations(ctx context.Context, names []string) ([]int64, error) {
+	gens := make([]int64, len(names))
+	var missing []int
+	s.mu.Lock()
+	for i, name := range names {
+		gen, ok := s.gens[name]
+		if !ok {
+			missing = append(missing, i)
+		}
+		gens[i] = gen
+	}
+	epoch := s.epoch
+	s.mu.Unlock()
+	if len(missing) == 0 {
+		return gens, nil
+	}
+
+	keys := make([]string, len(missing))
+	for j, i := range missing {
+		keys[j] = s.genKey(names[i])
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missing {
+		if v, ok := values[j].(string); ok {
+			if gens[i], err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A generation forgotten while it was read may be older than the one
+	// announced; keep it for this read only.
+	if s.subscribed && s.epoch == epoch {
+		for _, i := range missing {
+			s.gens[names[i]] = gens[i]
+		}
+	}
+	return gens, nil
+}
+
+// forget drops the generations of names, every generation when none given.
+func (s *Store) forget(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	if len(names) == 0 {
+		clear(s.gens)
+		return
+	}
+	for _, name := range names {
+		delete(s.gens, name)
+	}
+}
+
+func (s *Store) setSubscribed(subscribed bool) {
+	s.mu.Lock()
+	s.subscribed = subscribed
+	s.mu.Unlock()
+	s.forget()
+}
+
+// subscribe follows the invalidation channel until Close. While it is not
+// subscribed — before the first confirmation, after a lost connection — the
+// generations are read from the server on every Get.
+func (s *Store) subscribe() {
+	defer close(s.done)
+	ctx := context.Background()
+	for {
+		msg, err := s.pubsub.Receive(ctx)
+		if err != nil {
+			s.setSubscribed(false)
+			select {
+			case <-s.closed:
+				return
+			default:
+			}
+			s.log.Ctx(ctx).Warn("invalidation channel lost",
+				logger.String("engine", "redis"),
+				logger.String("details", err.Error()))
+			select {
+			case <-s.closed:
+				return
+			case <-time.After(resubscribeDelay):
+			}
+			continue
+		}
+		switch m := msg.(type) {
+		case *goredis.Subscription:
+			s.setSubscribed(m.Kind == "subscribe")
+		case *goredis.Message:
+			s.forget(m.Payload)
+		}
+	}
+}
+
+// fetch reads key, one request per key at a time for all the callers of the
+// Store; a missing key is a nil slice.
+func (s *Store) fetch(ctx context.Context, key string) ([]byte, error) {
+	v, err, _ := s.group.Do(key, func() (any, error) {
+		data, err := s.client.Get(context.WithoutCancel(ctx), key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return []byte(nil), nil
+		}
+		return data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// fill reads key when it is missing: the caller taking the fill lock gets a
+// nil slice and locked, and is expected to Set the entry or unlock it; the
+// others poll for it until the lock expires, or is dropped without an entry —
+// the filler found nothing to cache, and each of them reads on its own.
+func (s *Store) fill(ctx context.Context, key string) (data []byte, locked bool, err error) {
+	if s.fillLock <= 0 {
+		return nil, false, nil
+	}
+	locked, err = s.client.SetNX(ctx, s.lockKey(key), 1, s.fillLock).Result()
+	if err != nil || locked {
+		return nil, locked, err
+	}
+	timer := time.NewTimer(s.fillLock)
+	defer timer.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-timer.C:
+			return nil, false, nil
+		case <-ticker.C:
+			data, err := s.fetch(ctx, key)
+			if err != nil || data != nil {
+				return data, false, err
+			}
+			held, err := s.client.Exists(ctx, s.lockKey(key)).Result()
+			if err != nil {
+				return nil, false, err
+			}
+			if held == 0 {
+				// The entry may have been stored since the fetch above.
+				data, err = s.fetch(ctx, key)
+				return data, false, err
+			}
+		}
+	}
+}
+
+// unlock drops the fill lock of key, for a read that will not Set it.
+func (s *Store) unlock(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.lockKey(key)).Err()
+}
+
+func (s *Store) store(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	_, err := s.client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		p.Set(ctx, key, data, ttl)
+		p.Del(ctx, s.lockKey(key))
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Scope carries the state of one read of the executor from the Get of a
+// storage to its Set — the generations or the invalidation epoch a miss was
+// read at — so that the Set can tell its rows predate a write that ran in
+// between. Storages key their state by their own identity.
+type Scope struct {
+	mu     sync.Mutex
+	values map[any]any
+}
+
+type scopeKey struct{}
+
+// WithScope returns a derived context scoping one read. The executor calls it
+// before Get; the Set following the miss receives the same context.
+func WithScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &Scope{})
+}
+
+// ScopeFromContext returns the Scope of the read ctx belongs to, nil outside
+// of one — a storage used on its own, for instance.
+func ScopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// Store records value for key.
+func (s *Scope) Store(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[any]any)
+	}
+	s.values[key] = value
+}
+
+// Load returns the value recorded for key.
+func (s *Scope) Load(key any) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok
+}
@@ -16,3 +16,21 @@ type Storage interface {
 	Get(ctx context.Context, statement string, statementArgs ...any) (any, error)
 	Set(ctx context.Context, cache any, statement string, statementArgs ...any)
 }
+
+// Binder is an optional capability of a Storage: invalidation per table. The
+// repository calls Bind once when it is built, with its table and the other
+// tables its reads depend on — the tables of the persistent JOINs, of the
+// columns bound to other tables and of virtual.DependsOn. The returned Storage
+// is the one the repository uses: its Clean drops the entries depending on
+// table only, the entries it caches depend on table and dependencies.
+type Binder interface {
+	Bind(table string, dependencies []string) Storage
+}
+
+// Releaser is an optional capability of a Storage claiming the entry of a
+// missed Get until its Set — a fill lock the other readers wait on. A read
+// ending without a Set (no row, a query or scan error) calls Release with the
+// same ctx and statement, so that the others stop waiting for it.
+type Releaser interface {
+	Release(ctx context.Context, statement string, statementArgs ...any)
+}
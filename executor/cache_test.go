@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/insei/gerpo/executor/cache"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -128,3 +129,106 @@ func TestClean(t *testing.T) {
 		})
 	}
 }
+
+// bindingCacheSource is a cache.Binder returning bound.
+type bindingCacheSource struct {
+	MockCacheSource
+	bound        cache.Storage
+	table        string
+	dependencies []string
+}
+
+func (m *bindingCacheSource) Bind(table string, dependencies []string) cache.Storage {
+	m.table, m.dependencies = table, dependencies
+	return m.bound
+}
+
+func TestBindCache(t *testing.T) {
+	bound := new(MockCacheSource)
+	source := &bindingCacheSource{bound: bound}
+	e := New[struct{}](nil, WithCacheStorage(source)).(*executor[struct{}])
+	e.BindCache("posts", []string{"users"})
+	assert.Same(t, bound, e.cacheSource)
+	assert.Equal(t, "posts", source.table)
+	assert.Equal(t, []string{"users"}, source.dependencies)
+
+	plain := new(MockCacheSource)
+	e = New[struct{}](nil, WithCacheStorage(plain)).(*executor[struct{}])
+	e.BindCache("posts", nil)
+	assert.Same(t, plain, e.cacheSource, "storages without Bind are kept")
+
+	e = New[struct{}](nil).(*executor[struct{}])
+	e.BindCache("posts", nil)
+	assert.Nil(t, e.cacheSource)
+}
+
+func TestGetSet_NoCache(t *testing.T) {
+	ctx := cache.WithControl(context.Background(), cache.Control{NoCache: true})
+	b := new(MockCacheSource)
+	_, ok := get[string](ctx, b, "SELECT")
+	assert.False(t, ok)
+	set(ctx, b, "value", "SELECT")
+	b.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	b.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetSet_ForcePrimary(t *testing.T) {
+	ctx := ForcePrimary(context.Background())
+	b := &memoryCacheSource{value: "lagged"}
+	_, ok := get[string](ctx, b, "SELECT")
+	assert.False(t, ok, "a read forced to the primary skips the lookup")
+	set(ctx, b, "fresh", "SELECT")
+	assert.Equal(t, "fresh", b.value, "and fills the cache")
+}
+
+// memoryCacheSource keeps the last value set, whatever the statement.
+type memoryCacheSource struct {
+	value any
+}
+
+func (m *memoryCacheSource) Clean(context.Context) { m.value = nil }
+
+func (m *memoryCacheSource) Get(context.Context, string, ...any) (any, error) {
+	if m.value == nil {
+		return nil, cache.ErrNotFound
+	}
+	return m.value, nil
+}
+
+func (m *memoryCacheSource) Set(_ context.Context, value any, _ string, _ ...any) { m.value = value }
+
+func TestGetMultiple_CopyOnRead(t *testing.T) {
+	ctx := context.Background()
+	stmt := new(mockStmt)
+	stmt.On("SQL").Return("SELECT", []any{}, nil)
+
+	for _, tt := range []struct {
+		name   string
+		opts   []Option
+		shared bool
+	}{
+		{"shared by default", nil, true},
+		{"WithCopyOnRead", []Option{WithCopyOnRead()}, false},
+		{"WithCloneFunc", []Option{WithCloneFunc(func(m *testModel) *testModel {
+			cp := *m
+			return &cp
+		})}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &memoryCacheSource{value: []*testModel{{ID: 1, Name: "Ann"}}}
+			e := New[testModel](nil, append(tt.opts, WithCacheStorage(source))...)
+
+			first, err := e.GetMultiple(ctx, stmt)
+			assert.NoError(t, err)
+			first[0].Name = "changed"
+			second, err := e.GetMultiple(ctx, stmt)
+			assert.NoError(t, err)
+			if tt.shared {
+				assert.Same(t, first[0], second[0])
+				return
+			}
+			assert.NotSame(t, first[0], second[0])
+			assert.Equal(t, "Ann", second[0].Name, "a mutated result leaves the cache untouched")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+
+	"github.com/insei/fmap/v3"
+)
+
+// cloneFunc returns the function copying the models that go in and out of
+// the cache: the one of WithCloneFunc, a deep copy under WithCopyOnRead, nil
+// without either.
+func cloneFunc[TModel any](o options) func(*TModel) *TModel {
+	if o.clone != nil {
+		clone, ok := o.clone.(func(*TModel) *TModel)
+		if !ok {
+			panic(fmt.Sprintf("gerpo: WithCloneFunc got a %T for a repository of %s", o.clone, reflect.TypeFor[TModel]()))
+		}
+		return clone
+	}
+	if o.copyOnRead {
+		return deepCopyFunc[TModel]()
+	}
+	return nil
+}
+
+// deepCopyFunc returns a function copying a model together with what its
+// exported pointer, slice, map and array fields reference — those of nested
+// structs included, as fmap lists them. Unexported fields are copied as they
+// are, sharing what they point to.
+func deepCopyFunc[TModel any]() func(*TModel) *TModel {
+	var fields []fmap.Field
+	if storage, err := fmap.Get[TModel](); err == nil {
+		for _, path := range storage.GetAllPaths() {
+			field := storage.MustFind(path)
+			switch field.GetType().Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Array:
+				if exportedPath(path) {
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+	return func(model *TModel) *TModel {
+		if model == nil {
+			return nil
+		}
+		cp := new(TModel)
+		*cp = *model
+		for _, field := range fields {
+			field.Set(cp, deepCopy(reflect.ValueOf(field.Get(model))).Interface())
+		}
+		return cp
+	}
+}
+
+func exportedPath(path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		if !token.IsExported(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// deepCopy copies v and, through its exported fields, what it references.
+// Cyclic values are not supported.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+		copyElems(cp, v)
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		reflect.Copy(cp, v)
+		copyElems(cp, v)
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			cp.SetMapIndex(it.Key(), deepCopy(it.Value()))
+		}
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := range cp.NumField() {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return cp
+	default:
+		return v
+	}
+}
+
+// copyElems deep-copies the elements of src into dst when they may reference
+// other values; dst already holds their shallow copies.
+func copyElems(dst, src reflect.Value) {
+	switch src.Type().Elem().Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Array, reflect.Struct:
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+	}
+}
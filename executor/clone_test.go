@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cloneAddress struct {
+	City  string
+	Lines []string
+}
+
+type cloneModel struct {
+	ID        int
+	Name      *string
+	Tags      []string
+	Attrs     map[string][]int
+	Address   cloneAddress
+	Previous  []*cloneAddress
+	CreatedAt time.Time
+	Scores    [2][]int
+	Nil       []string
+	hidden    []string
+}
+
+func TestDeepCopyFunc(t *testing.T) {
+	name := "Ann"
+	model := &cloneModel{
+		ID:        1,
+		Name:      &name,
+		Tags:      []string{"a"},
+		Attrs:     map[string][]int{"x": {1}},
+		Address:   cloneAddress{City: "Oslo", Lines: []string{"Main st."}},
+		Previous:  []*cloneAddress{{City: "Rome"}},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+		Scores:    [2][]int{{1}, {2}},
+		hidden:    []string{"h"},
+	}
+	cp := deepCopyFunc[cloneModel]()(model)
+	assert.Equal(t, model, cp)
+
+	*cp.Name = "Bob"
+	cp.Tags[0] = "b"
+	cp.Attrs["x"][0] = 2
+	cp.Address.Lines[0] = "Side st."
+	cp.Previous[0].City = "Paris"
+	cp.Scores[1][0] = 3
+	assert.Equal(t, "Ann", *model.Name)
+	assert.Equal(t, []string{"a"}, model.Tags)
+	assert.Equal(t, map[string][]int{"x": {1}}, model.Attrs)
+	assert.Equal(t, []string{"Main st."}, model.Address.Lines)
+	assert.Equal(t, "Rome", model.Previous[0].City)
+	assert.Equal(t, [2][]int{{1}, {2}}, model.Scores)
+	assert.Nil(t, cp.Nil)
+	assert.Same(t, &model.hidden[0], &cp.hidden[0], "unexported fields are shared")
+	assert.Equal(t, time.Local, cp.CreatedAt.Location())
+
+	assert.Nil(t, deepCopyFunc[cloneModel]()(nil))
+}
+
+func TestCloneFunc(t *testing.T) {
+	assert.Nil(t, cloneFunc[testModel](options{}))
+	assert.NotNil(t, cloneFunc[testModel](options{copyOnRead: true}))
+
+	clone := func(m *testModel) *testModel { return &testModel{ID: m.ID + 1} }
+	got := cloneFunc[testModel](options{copyOnRead: true, clone: clone})
+	assert.Equal(t, 2, got(&testModel{ID: 1}).ID, "WithCloneFunc wins over the deep copy")
+
+	assert.PanicsWithValue(t, "gerpo: WithCloneFunc got a func(*executor.cloneModel) *executor.cloneModel for a repository of executor.testModel", func() {
+		New[testModel](nil, WithCloneFunc(func(m *cloneModel) *cloneModel { return m }))
+	})
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	extypes "github.com/insei/gerpo/executor/types"
+	"github.com/insei/gerpo/types"
+)
+
+// ConflictTargetStmt is an optional capability of batch INSERT statements
+// with an ON CONFLICT clause on columns. Rows skipped by DO NOTHING (or by the
+// DO UPDATE ... WHERE condition) are absent from RETURNING, so the executor
+// pairs returned rows with models by the values of these columns instead of
+// by position. The columns must be part of the statement's RETURNING set.
+type ConflictTargetStmt interface {
+	ConflictStmt
+	ConflictTarget() []types.Column
+}
+
+func conflictTargetOfBatch(stmt BatchStmt) []types.Column {
+	cs, ok := any(stmt).(ConflictTargetStmt)
+	if !ok || !cs.HasConflictClause() {
+		return nil
+	}
+	return cs.ConflictTarget()
+}
+
+// scanReturningByKey writes RETURNING rows back into the chunk models they
+// belong to. Each row is scanned into a scratch model first and then copied
+// into the first not-yet-matched chunk model with the same conflict key, so
+// skipped rows and any reordering by the database leave the write-back
+// correct. Models whose row was skipped are left untouched.
+//
+// A key the database hands back in another form than it was sent — a citext
+// or case-insensitive collation folding it, a timestamp rounded to the
+// precision of the column — matches no model. When every model got a row,
+// such rows are paired by position with the models left unmatched; otherwise
+// which of them was skipped cannot be told and the scan fails.
+func scanReturningByKey[TModel any](rows extypes.Rows, returning, key []types.Column, chunk []*TModel) (int64, error) {
+	pending := make(map[string][]int, len(chunk))
+	for i, m := range chunk {
+		k := conflictKey(key, m)
+		pending[k] = append(pending[k], i)
+	}
+	var n int64
+	var unmatched []*TModel
+	for rows.Next() {
+		scratch := new(TModel)
+		if err := rows.Scan(scanPointers(returning, scratch)...); err != nil {
+			return n, err
+		}
+		k := conflictKey(key, scratch)
+		idxs := pending[k]
+		if len(idxs) == 0 {
+			unmatched = append(unmatched, scratch)
+			continue
+		}
+		pending[k] = idxs[1:]
+		copyColumns(returning, chunk[idxs[0]], scratch)
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	if len(unmatched) == 0 {
+		return n, nil
+	}
+	if int(n)+len(unmatched) != len(chunk) {
+		return n, fmt.Errorf("RETURNING yielded a row that matches no sent model by its key")
+	}
+	var left []int
+	for _, idxs := range pending {
+		left = append(left, idxs...)
+	}
+	slices.Sort(left)
+	for i, scratch := range unmatched {
+		copyColumns(returning, chunk[left[i]], scratch)
+		n++
+	}
+	return n, nil
+}
+
+// conflictKey renders the conflict target values of model as a map key.
+// Values are normalised the way they reach the database so a model and the
+// row scanned back for it produce the same key.
+func conflictKey(key []types.Column, model any) string {
+	sb := strings.Builder{}
+	for i, c := range key {
+		if i > 0 {
+			sb.WriteByte(0x1f)
+		}
+		fmt.Fprintf(&sb, "%#v", normalizeKeyValue(reflect.ValueOf(c.GetPtr(model)).Elem()))
+	}
+	return sb.String()
+}
+
+func normalizeKeyValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	val := v.Interface()
+	if valuer, ok := val.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err == nil {
+			val = dv
+		}
+	}
+	switch tv := val.(type) {
+	case time.Time:
+		return tv.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(tv)
+	}
+	return val
+}
+
+func copyColumns(cols []types.Column, dst, src any) {
+	for _, c := range cols {
+		reflect.ValueOf(c.GetPtr(dst)).Elem().Set(reflect.ValueOf(c.GetPtr(src)).Elem())
+	}
+}
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/insei/gerpo/types"
+)
+
+// BulkCopy streams models into the table with PostgreSQL COPY FROM. It needs
+// an adapter (or ctx Tx) implementing Copier and reports ErrCopyNotSupported
+// otherwise. Values are read lazily, one model at a time, so the load never
+// materialises the whole batch as driver arguments. COPY returns the number
+// of rows written only: nothing is scanned back into the models.
+func (e *executor[TModel]) BulkCopy(ctx context.Context, stmt CopyStmt, models []*TModel) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+	copier, ok := e.getExecQuery(ctx).(Copier)
+	if !ok {
+		return 0, ErrCopyNotSupported
+	}
+	names, err := stmt.ColumnNames()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get columns from stmt: %w", err)
+	}
+	n, err := copier.CopyFrom(ctx, stmt.Table(), names, &modelsCopySource[TModel]{
+		columns: stmt.Columns(),
+		models:  models,
+		idx:     -1,
+	})
+	if err != nil {
+		return n, err
+	}
+	if n > 0 {
+		clean(ctx, e.cacheSource)
+	}
+	return n, nil
+}
+
+// modelsCopySource adapts a model slice to types.CopySource.
+type modelsCopySource[TModel any] struct {
+	columns types.ExecutionColumns
+	models  []*TModel
+	idx     int
+}
+
+func (s *modelsCopySource[TModel]) Next() bool {
+	s.idx++
+	return s.idx < len(s.models)
+}
+
+func (s *modelsCopySource[TModel]) Values() ([]any, error) {
+	return s.columns.GetModelValues(s.models[s.idx]), nil
+}
+
+func (s *modelsCopySource[TModel]) Err() error { return nil }
@@ -3,13 +3,25 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/sqlstmt"
 	"github.com/insei/gerpo/types"
 )
 
 type executor[TModel any] struct {
 	db Adapter
+	// nextReader picks the reader of the next read, round-robin.
+	nextReader atomic.Uint64
+	// clone copies the models going in and out of the cache, nil when the
+	// cache keeps the models handed to the callers; see WithCopyOnRead.
+	clone func(*TModel) *TModel
+	// flights coalesces identical concurrent reads, nil without
+	// WithSingleFlight. flightClone copies the models a flight read for each
+	// of its callers: clone, or a deep copy when clone is nil.
+	flights     *flightGroup
+	flightClone func(*TModel) *TModel
 
 	options
 }
@@ -22,6 +34,14 @@ func New[TModel any](db Adapter, opts ...Option) Executor[TModel] {
 	e := &executor[TModel]{
 		options: *o,
 		db:      db,
+		clone:   cloneFunc[TModel](*o),
+	}
+	if o.singleFlight {
+		e.flights = newFlightGroup()
+		e.flightClone = e.clone
+		if e.flightClone == nil {
+			e.flightClone = deepCopyFunc[TModel]()
+		}
 	}
 	return e
 }
@@ -30,7 +50,7 @@ func New[TModel any](db Adapter, opts ...Option) Executor[TModel] {
 // carries a Tx (installed via executor.WithTx / gerpo.WithTx), that Tx wins;
 // otherwise the repository-level adapter is used.
 func (e *executor[TModel]) getExecQuery(ctx context.Context) ExecQuery {
-	if tx, ok := txFromContext(ctx); ok {
+	if tx, ok := TxFromContext(ctx); ok {
 		return tx
 	}
 	return e.db
@@ -41,26 +61,35 @@ func (e *executor[TModel]) GetOne(ctx context.Context, stmt Stmt) (model *TModel
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql query from stmt: %w", err)
 	}
+	ctx = scope(ctx, e.cacheSource)
 	if cached, ok := get[TModel](ctx, e.cacheSource, sql, args...); ok {
-		return cached, nil
-	}
-	rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, args...)
-	if err != nil {
-		return nil, err
+		return e.copyModel(cached), nil
 	}
-	defer rows.Close() //nolint:errcheck
-	if rows.Next() {
-		model = new(TModel)
-		pointers := stmt.Columns().GetModelPointers(model)
-		if err = rows.Scan(pointers...); err != nil {
+	columns := e.columnsOf(stmt)
+	model, err = coalesce(ctx, e, "one", sql, args, func(ctx context.Context) (model *TModel, err error) {
+		rows, err := e.getReadQuery(ctx).QueryContext(ctx, sql, args...)
+		if err != nil {
 			return nil, err
 		}
-		set(ctx, e.cacheSource, *model, sql, args...)
-	}
-	if model == nil {
-		return nil, ErrNoRows
+		defer rows.Close() //nolint:errcheck
+		if rows.Next() {
+			model = new(TModel)
+			pointers := columns().GetModelPointers(model)
+			if err = rows.Scan(pointers...); err != nil {
+				return nil, err
+			}
+			set(ctx, e.cacheSource, *e.copyModel(model), sql, args...)
+		}
+		if model == nil {
+			return nil, ErrNoRows
+		}
+		return model, nil
+	})
+	if err != nil {
+		release(ctx, e.cacheSource, sql, args...)
+		return nil, err
 	}
-	return model, nil
+	return e.sharedModel(model), nil
 }
 
 func (e *executor[TModel]) GetMultiple(ctx context.Context, stmt Stmt) (models []*TModel, err error) {
@@ -68,23 +97,80 @@ func (e *executor[TModel]) GetMultiple(ctx context.Context, stmt Stmt) (models [
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql query from stmt: %w", err)
 	}
+	ctx = scope(ctx, e.cacheSource)
 	if cached, ok := get[[]*TModel](ctx, e.cacheSource, sql, args...); ok {
-		return *cached, nil
+		return e.copyModels(*cached), nil
 	}
-	rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, args...)
+	columns := e.columnsOf(stmt)
+	models, err = coalesce(ctx, e, "list", sql, args, func(ctx context.Context) (models []*TModel, err error) {
+		rows, err := e.getReadQuery(ctx).QueryContext(ctx, sql, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close() //nolint:errcheck
+		for rows.Next() {
+			model := new(TModel)
+			if err = rows.Scan(columns().GetModelPointers(model)...); err != nil {
+				return nil, err
+			}
+			models = append(models, model)
+		}
+		set(ctx, e.cacheSource, e.copyModels(models), sql, args...)
+		return models, nil
+	})
 	if err != nil {
+		release(ctx, e.cacheSource, sql, args...)
 		return nil, err
 	}
-	defer rows.Close() //nolint:errcheck
-	for rows.Next() {
-		model := new(TModel)
-		if err = rows.Scan(stmt.Columns().GetModelPointers(model)...); err != nil {
-			return nil, err
+	return e.sharedModels(models), nil
+}
+
+// Page is the cached shape of a GetPage result; storages that serialize
+// their entries encode and decode it next to TModel, []*TModel and uint64.
+type Page[TModel any] struct {
+	Models []*TModel
+	Total  uint64
+}
+
+// GetPage reads one page of models together with the total size of the
+// filtered set, both from a single statement: the window total arrives as the
+// trailing column of every row. A page with no rows reports a zero total —
+// callers that read past the end re-query the first row for the real one.
+func (e *executor[TModel]) GetPage(ctx context.Context, stmt PageStmt) (models []*TModel, total uint64, err error) {
+	stmt.WithTotal()
+	sql, args, err := stmt.SQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sql query from stmt: %w", err)
+	}
+	ctx = scope(ctx, e.cacheSource)
+	if cached, ok := get[Page[TModel]](ctx, e.cacheSource, sql, args...); ok {
+		return e.copyModels(cached.Models), cached.Total, nil
+	}
+	columns := e.columnsOf(stmt)
+	page, err := coalesce(ctx, e, "page", sql, args, func(ctx context.Context) (page Page[TModel], err error) {
+		rows, err := e.getReadQuery(ctx).QueryContext(ctx, sql, args...)
+		if err != nil {
+			return page, err
+		}
+		defer rows.Close() //nolint:errcheck
+		for rows.Next() {
+			model := new(TModel)
+			if err = rows.Scan(append(columns().GetModelPointers(model), &page.Total)...); err != nil {
+				return Page[TModel]{}, err
+			}
+			page.Models = append(page.Models, model)
+		}
+		if err = rows.Err(); err != nil {
+			return Page[TModel]{}, err
 		}
-		models = append(models, model)
+		set(ctx, e.cacheSource, Page[TModel]{Models: e.copyModels(page.Models), Total: page.Total}, sql, args...)
+		return page, nil
+	})
+	if err != nil {
+		release(ctx, e.cacheSource, sql, args...)
+		return nil, 0, err
 	}
-	set(ctx, e.cacheSource, models, sql, args...)
-	return models, nil
+	return e.sharedModels(page.Models), page.Total, nil
 }
 
 func (e *executor[TModel]) InsertOne(ctx context.Context, stmt Stmt, model *TModel) (err error) {
@@ -93,6 +179,9 @@ func (e *executor[TModel]) InsertOne(ctx context.Context, stmt Stmt, model *TMod
 		return fmt.Errorf("failed to get sql query from stmt: %w", err)
 	}
 	if returning := returningColumnsOf(stmt); len(returning) > 0 {
+		if !supportsReturning(ctx) {
+			return e.insertOneReadBack(ctx, stmt, sql, values, returning, model)
+		}
 		rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, values...)
 		if err != nil {
 			return err
@@ -105,7 +194,7 @@ func (e *executor[TModel]) InsertOne(ctx context.Context, stmt Stmt, model *TMod
 			if err := rows.Err(); err != nil {
 				return err
 			}
-			return ErrNoInsertedRows
+			return noInsertedRowsErr(stmt)
 		}
 		ptrs := scanPointers(returning, model)
 		if err = rows.Scan(ptrs...); err != nil {
@@ -123,17 +212,41 @@ func (e *executor[TModel]) InsertOne(ctx context.Context, stmt Stmt, model *TMod
 		return err
 	}
 	if insertedRows == 0 {
-		return ErrNoInsertedRows
+		return noInsertedRowsErr(stmt)
 	}
 	clean(ctx, e.cacheSource)
 	return nil
 }
 
+// noInsertedRowsErr picks the error for an INSERT that wrote nothing: with an
+// ON CONFLICT clause the row was skipped on purpose, otherwise it is the
+// generic ErrNoInsertedRows.
+func noInsertedRowsErr(stmt Stmt) error {
+	if cs, ok := stmt.(ConflictStmt); ok && cs.HasConflictClause() {
+		return ErrInsertConflict
+	}
+	return ErrNoInsertedRows
+}
+
+// chunkRows is the number of rows of valsPerRow bound values a batched
+// statement may carry under the parameter cap of the ctx dialect (see
+// dialect.Dialect.MaxParams). One percent of the cap stays free for the
+// values the rest of the statement binds (WHERE, ON CONFLICT, …).
+func chunkRows(ctx context.Context, valsPerRow int) int {
+	maxParams := dialect.FromContext(ctx).MaxParams()
+	budget := maxParams - maxParams/100
+	return max(budget/max(valsPerRow, 1), 1)
+}
+
 // InsertMany emits a multi-row INSERT ... VALUES (...) [RETURNING ...] and
-// transparently chunks the input to stay under the driver's placeholder limit
-// (PostgreSQL caps bound parameters at 65535 per query). When RETURNING is
+// transparently chunks the input to stay under the placeholder limit of the
+// dialect (see chunkRows) and its row limit per INSERT, if any. When RETURNING is
 // configured on the stmt, scanned rows are written back into models[i] by
-// position; every chunk consumes its slice range in order.
+// position; every chunk consumes its slice range in order. With an ON CONFLICT
+// clause rows are paired with models by the conflict target instead (see
+// ConflictTargetStmt), and the returned total counts only the rows actually
+// written. Dialects without RETURNING read the columns back by a client-set
+// primary key after every chunk (see ReadBackStmt).
 //
 // Failures leave the work already committed by prior chunks in place: the
 // caller is responsible for wrapping the call in gerpo.RunInTx when atomicity
@@ -153,15 +266,27 @@ func (e *executor[TModel]) InsertMany(ctx context.Context, stmt BatchStmt, model
 		return 0, fmt.Errorf("failed to get sql query from stmt: no columns to insert")
 	}
 
-	// PostgreSQL caps bound parameters at 65535. Stay well below that so other
-	// call-site contributions (RETURNING, ON CONFLICT, …) cannot trip it.
-	const placeholderBudget = 65000
-	chunkSize := placeholderBudget / activeCols
-	if chunkSize < 1 {
-		chunkSize = 1
+	chunkSize := chunkRows(ctx, activeCols)
+	if maxRows := dialect.FromContext(ctx).MaxInsertRows(); maxRows > 0 {
+		chunkSize = min(chunkSize, maxRows)
 	}
 
 	returning := returningColumnsOfBatch(stmt)
+	keyCols := conflictTargetOfBatch(stmt)
+	var readBackTable string
+	if len(returning) > 0 && !supportsReturning(ctx) {
+		// Without RETURNING the rows are selected back by a primary key the
+		// models already carry; generated keys of a multi-row INSERT cannot
+		// be told apart.
+		table, pk, err := readBackTarget(stmt)
+		if err != nil {
+			return 0, err
+		}
+		if len(withoutColumns(returning, pk)) != len(returning) {
+			return 0, fmt.Errorf("%w: InsertMany cannot read back generated primary keys", ErrReturningNotSupported)
+		}
+		readBackTable, keyCols = table, pk
+	}
 	chunkBuf := make([]any, 0, chunkSize)
 
 	var total int64
@@ -181,11 +306,28 @@ func (e *executor[TModel]) InsertMany(ctx context.Context, stmt BatchStmt, model
 			return total, fmt.Errorf("failed to get sql query from stmt: %w", err)
 		}
 
+		if readBackTable != "" {
+			n, err := e.execReadBack(ctx, sql, values, readBackTable, returning, keyCols, models[start:end])
+			total += n
+			if err != nil {
+				return total, err
+			}
+			continue
+		}
 		if len(returning) > 0 {
 			rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, values...)
 			if err != nil {
 				return total, err
 			}
+			if len(keyCols) > 0 {
+				n, err := scanReturningByKey(rows, returning, keyCols, models[start:end])
+				_ = rows.Close()
+				total += n
+				if err != nil {
+					return total, err
+				}
+				continue
+			}
 			idx := start
 			for rows.Next() {
 				if idx >= end {
@@ -242,6 +384,9 @@ func (e *executor[TModel]) Update(ctx context.Context, stmt Stmt, model *TModel)
 		return 0, fmt.Errorf("failed to get sql query from stmt: %w", err)
 	}
 	if returning := returningColumnsOf(stmt); len(returning) > 0 {
+		if !supportsReturning(ctx) {
+			return e.updateReadBack(ctx, stmt, sql, values, returning, model)
+		}
 		rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, values...)
 		if err != nil {
 			return 0, err
@@ -282,6 +427,56 @@ func (e *executor[TModel]) Update(ctx context.Context, stmt Stmt, model *TModel)
 	return updatedRows, nil
 }
 
+// UpdateWhere runs an UPDATE that is not bound to a model — its SET clause is
+// built from explicit assignments. With RETURNING configured every affected
+// row is scanned into a fresh model; only the returned columns are filled.
+// Without RETURNING the models slice is nil and only the count is reported.
+// Dialects without RETURNING reject it with ErrReturningNotSupported.
+func (e *executor[TModel]) UpdateWhere(ctx context.Context, stmt Stmt) (models []*TModel, updatedRows int64, err error) {
+	sql, values, err := stmt.SQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sql query from stmt: %w", err)
+	}
+	if returning := returningColumnsOf(stmt); len(returning) > 0 {
+		if !supportsReturning(ctx) {
+			// The WHERE clause is the only handle on the updated rows and it
+			// may no longer match them after the SET.
+			return nil, 0, fmt.Errorf("%w: UpdateWhere cannot tell the updated rows apart", ErrReturningNotSupported)
+		}
+		rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, values...)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer rows.Close() //nolint:errcheck
+		for rows.Next() {
+			model := new(TModel)
+			if err = rows.Scan(scanPointers(returning, model)...); err != nil {
+				return models, int64(len(models)), err
+			}
+			models = append(models, model)
+		}
+		if err := rows.Err(); err != nil {
+			return models, int64(len(models)), err
+		}
+		if len(models) > 0 {
+			clean(ctx, e.cacheSource)
+		}
+		return models, int64(len(models)), nil
+	}
+	result, err := e.getExecQuery(ctx).ExecContext(ctx, sql, values...)
+	if err != nil {
+		return nil, 0, err
+	}
+	updatedRows, err = result.RowsAffected()
+	if err != nil {
+		return nil, 0, err
+	}
+	if updatedRows > 0 {
+		clean(ctx, e.cacheSource)
+	}
+	return nil, updatedRows, nil
+}
+
 // returningColumnsOf extracts the RETURNING column list from stmt if it
 // supports the ReturningStmt capability; returns nil otherwise.
 func returningColumnsOf(stmt Stmt) []types.Column {
@@ -307,20 +502,28 @@ func (e *executor[TModel]) Count(ctx context.Context, stmt CountStmt) (count uin
 	if err != nil {
 		return 0, fmt.Errorf("failed to get sql query from stmt: %w", err)
 	}
+	ctx = scope(ctx, e.cacheSource)
 	if cached, ok := get[uint64](ctx, e.cacheSource, sql, args...); ok {
 		return *cached, nil
 	}
-	rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, args...)
-	if err != nil {
-		return 0, err
-	}
-	defer rows.Close() //nolint:errcheck
-	if rows.Next() {
-		if err = rows.Scan(&count); err != nil {
+	count, err = coalesce(ctx, e, "count", sql, args, func(ctx context.Context) (count uint64, err error) {
+		rows, err := e.getReadQuery(ctx).QueryContext(ctx, sql, args...)
+		if err != nil {
 			return 0, err
 		}
+		defer rows.Close() //nolint:errcheck
+		if rows.Next() {
+			if err = rows.Scan(&count); err != nil {
+				return 0, err
+			}
+		}
+		set(ctx, e.cacheSource, count, sql, args...)
+		return count, nil
+	})
+	if err != nil {
+		release(ctx, e.cacheSource, sql, args...)
+		return 0, err
 	}
-	set(ctx, e.cacheSource, count, sql, args...)
 	return count, nil
 }
 
@@ -3,10 +3,12 @@ package executor
 import (
 	"context"
 	dbsql "database/sql"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor/adapters/databasesql"
 	"github.com/insei/gerpo/executor/cache"
 	"github.com/insei/gerpo/sqlstmt"
@@ -42,6 +44,14 @@ func (s *returningStubStmt) Columns() types.ExecutionColumns { return nil }
 
 func (s *returningStubStmt) ReturningColumns() []types.Column { return s.returning }
 
+// conflictStubStmt is a returningStubStmt that also reports an ON CONFLICT
+// clause, so the executor can tell a skipped row apart from a lost INSERT.
+type conflictStubStmt struct {
+	returningStubStmt
+}
+
+func (s *conflictStubStmt) HasConflictClause() bool { return true }
+
 // returningStubCol is a tiny types.Column implementation that simply forwards a
 // preset pointer from GetPtr — enough for code paths that build a scan-pointer
 // slice before checking whether iteration produced any rows.
@@ -369,6 +379,129 @@ func TestGetMultiple(t *testing.T) {
 	}
 }
 
+// pageStubStmt is a mockStmt that also satisfies PageStmt.
+type pageStubStmt struct {
+	*mockStmt
+	withTotal bool
+}
+
+func (s *pageStubStmt) WithTotal() { s.withTotal = true }
+
+func TestGetPage(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	models := []testModel{{}, {}}
+	stmt := &pageStubStmt{mockStmt: new(mockStmt)}
+	stmt.On("SQL").Return("SELECT id, count(*) over() AS count FROM users LIMIT 2", []interface{}{}, nil)
+	for i := range models {
+		columns := new(mockColumns)
+		columns.On("GetModelPointers", mock.Anything).Return([]any{&models[i].ID})
+		stmt.On("Columns").Return(columns).Once()
+	}
+	mockDB.ExpectQuery(`SELECT id, count\(\*\) over\(\) AS count FROM users LIMIT 2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "count"}).AddRow(1, 7).AddRow(2, 7)).
+		RowsWillBeClosed()
+
+	e := &executor[testModel]{db: databasesql.NewAdapter(db)}
+	got, total, err := e.GetPage(context.Background(), stmt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stmt.withTotal {
+		t.Error("GetPage must switch the window total on")
+	}
+	if len(got) != 2 || total != 7 {
+		t.Errorf("expected 2 models and total 7, got %d and %d", len(got), total)
+	}
+	if models[0].ID != 1 || models[1].ID != 2 {
+		t.Errorf("unexpected scanned ids: %d, %d", models[0].ID, models[1].ID)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	stmt := new(mockStmt)
+	stmt.On("SQL").Return("SELECT id FROM users", []interface{}{}, nil)
+	stmt.On("Columns").Return(&scanIDColumns{})
+	mockDB.ExpectQuery("SELECT id FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3)).
+		RowsWillBeClosed()
+
+	// A cache that fails the test if touched: streaming bypasses it.
+	storage := new(MockCacheSource)
+	e := &executor[testModel]{db: databasesql.NewAdapter(db), options: options{cacheSource: storage}}
+
+	var ids []int
+	for m, err := range e.Iterate(context.Background(), stmt) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, m.ID)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("rows must be closed on early break: %s", err)
+	}
+	storage.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	storage.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIterate_CancelledContext(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	stmt := new(mockStmt)
+	stmt.On("SQL").Return("SELECT id FROM users", []interface{}{}, nil)
+	stmt.On("Columns").Return(&scanIDColumns{})
+	mockDB.ExpectQuery("SELECT id FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e := &executor[testModel]{db: databasesql.NewAdapter(db)}
+	var gotErr error
+	n := 0
+	for _, err := range e.Iterate(ctx, stmt) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		n++
+		cancel()
+	}
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", gotErr)
+	}
+	if n != 1 {
+		t.Errorf("expected one model before cancellation, got %d", n)
+	}
+}
+
+// scanIDColumns scans the single id column straight into the model.
+type scanIDColumns struct{ types.ExecutionColumns }
+
+func (c *scanIDColumns) GetModelPointers(model any) []any { return []any{&model.(*testModel).ID} }
+
 func TestInsertOne(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -500,6 +633,45 @@ func TestInsertOne(t *testing.T) {
 			},
 			expectedErr: fmt.Errorf("pq: duplicate key value violates unique constraint \"users_pkey\""),
 		},
+		{
+			name: "ON CONFLICT DO NOTHING skipped the row",
+			withModel: func() *testModel {
+				return &testModel{ID: 1, Age: 28, Name: "John Doe"}
+			},
+			withStmt: func() Stmt {
+				return &conflictStubStmt{returningStubStmt{
+					sql:  "INSERT INTO users (id, age, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING",
+					args: []any{1, 28, "John Doe"},
+				}}
+			},
+			setupDb: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO users \(id, age, name\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(id\) DO NOTHING`).
+					WithArgs(1, 28, "John Doe").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: ErrInsertConflict,
+		},
+		{
+			name: "ON CONFLICT DO NOTHING with RETURNING skipped the row",
+			withModel: func() *testModel {
+				return &testModel{ID: 1, Age: 28, Name: "John Doe"}
+			},
+			withStmt: func() Stmt {
+				m := &testModel{}
+				return &conflictStubStmt{returningStubStmt{
+					sql:       "INSERT INTO users (id, age, name) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING RETURNING id",
+					args:      []any{1, 28, "John Doe"},
+					returning: []types.Column{&returningStubCol{ptr: &m.ID}},
+				}}
+			},
+			setupDb: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`INSERT INTO users \(id, age, name\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(id\) DO NOTHING RETURNING id`).
+					WithArgs(1, 28, "John Doe").
+					WillReturnRows(sqlmock.NewRows([]string{"id"})).
+					RowsWillBeClosed()
+			},
+			expectedErr: ErrInsertConflict,
+		},
 		{
 			name: "Successful insertion",
 			withModel: func() *testModel {
@@ -713,6 +885,52 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+// idColumn is a types.Column that scans into testModel.ID of whatever model
+// it is given — what UpdateWhere needs to fill fresh models from RETURNING.
+type idColumn struct{ types.Column }
+
+func (idColumn) GetPtr(model any) any { return &model.(*testModel).ID }
+
+func TestUpdateWhere(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	e := &executor[testModel]{db: databasesql.NewAdapter(db)}
+
+	t.Run("RETURNING scans every row into a fresh model", func(t *testing.T) {
+		stmt := &returningStubStmt{sql: "UPDATE users SET age = age + ? RETURNING id", args: []any{1}, returning: []types.Column{idColumn{}}}
+		mockDB.ExpectQuery(`UPDATE users SET age = age \+ \? RETURNING id`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+
+		models, n, err := e.UpdateWhere(context.Background(), stmt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 2 || len(models) != 2 || models[0].ID != 3 || models[1].ID != 5 {
+			t.Errorf("unexpected result: n=%d models=%v", n, models)
+		}
+	})
+
+	t.Run("Without RETURNING only the count is reported", func(t *testing.T) {
+		stmt := &returningStubStmt{sql: "UPDATE users SET age = ?", args: []any{1}}
+		mockDB.ExpectExec(`UPDATE users SET age = \?`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 4))
+
+		models, n, err := e.UpdateWhere(context.Background(), stmt)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 4 || models != nil {
+			t.Errorf("unexpected result: n=%d models=%v", n, models)
+		}
+	})
+
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestCount(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -945,3 +1163,98 @@ func TestGetExecQuery_PrefersCtxTxOverAdapter(t *testing.T) {
 // stubTxExecQuery is an executor.Tx stub — zero behavior, used only to assert
 // identity in TestGetExecQuery_PrefersCtxTxOverAdapter.
 type stubTxExecQuery struct{ Tx }
+
+// copyStubStmt is a CopyStmt double: the column set is a mockColumns so the
+// values streamed into COPY come from GetModelValues like in production.
+type copyStubStmt struct {
+	columns *mockColumns
+}
+
+func (s *copyStubStmt) Table() string                   { return "t" }
+func (s *copyStubStmt) Columns() types.ExecutionColumns { return s.columns }
+func (s *copyStubStmt) ColumnNames() ([]string, error)  { return []string{"id", "name"}, nil }
+
+// copierAdapter is an Adapter that also implements Copier and records the
+// rows it is handed.
+type copierAdapter struct {
+	Adapter
+	table   string
+	columns []string
+	rows    [][]any
+}
+
+func (a *copierAdapter) CopyFrom(_ context.Context, table string, columns []string, src CopySource) (int64, error) {
+	a.table, a.columns = table, columns
+	for src.Next() {
+		vals, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		a.rows = append(a.rows, vals)
+	}
+	return int64(len(a.rows)), src.Err()
+}
+
+func TestBulkCopy(t *testing.T) {
+	models := []*testModel{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	cols := new(mockColumns)
+	cols.On("GetModelValues", models[0]).Return([]interface{}{1, "a"})
+	cols.On("GetModelValues", models[1]).Return([]interface{}{2, "b"})
+	stmt := &copyStubStmt{columns: cols}
+
+	t.Run("Adapter without Copier", func(t *testing.T) {
+		e := New[testModel](databasesql.NewAdapter(&dbsql.DB{}))
+		n, err := e.BulkCopy(context.Background(), stmt, models)
+		if !errors.Is(err, ErrCopyNotSupported) {
+			t.Fatalf("expected ErrCopyNotSupported, got %v", err)
+		}
+		if n != 0 {
+			t.Fatalf("expected 0 rows, got %d", n)
+		}
+	})
+
+	t.Run("Rows streamed through Copier", func(t *testing.T) {
+		db := &copierAdapter{}
+		e := New[testModel](db)
+		n, err := e.BulkCopy(context.Background(), stmt, models)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 2 || db.table != "t" || len(db.columns) != 2 {
+			t.Fatalf("unexpected copy: n=%d table=%q columns=%v", n, db.table, db.columns)
+		}
+		if fmt.Sprint(db.rows) != "[[1 a] [2 b]]" {
+			t.Fatalf("unexpected rows: %v", db.rows)
+		}
+	})
+
+	t.Run("Ctx Tx without Copier wins over the adapter", func(t *testing.T) {
+		e := New[testModel](&copierAdapter{})
+		ctx := WithTx(context.Background(), &stubTxExecQuery{})
+		if _, err := e.BulkCopy(ctx, stmt, models); !errors.Is(err, ErrCopyNotSupported) {
+			t.Fatalf("expected ErrCopyNotSupported, got %v", err)
+		}
+	})
+}
+
+func TestChunkRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		d          dialect.Dialect
+		valsPerRow int
+		want       int
+	}{
+		{"PostgreSQL", dialect.PostgreSQL, 10, 6488},
+		{"SQL Server", dialect.SQLServer, 10, 207},
+		{"Row wider than the budget", dialect.SQLServer, 5000, 1},
+		{"No values", dialect.SQLServer, 0, 2079},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := dialect.WithContext(context.Background(), tt.d)
+			if got := chunkRows(ctx, tt.valsPerRow); got != tt.want {
+				t.Fatalf("chunkRows() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
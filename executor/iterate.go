@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Iterate streams the rows of stmt one model at a time. The query runs when
+// the sequence is ranged over, not when Iterate is called; rows are closed
+// when the loop ends, including on an early break. The cache is bypassed in
+// both directions: a streamed result is never read from nor stored into it.
+//
+// Every yielded model is a fresh allocation. An error — from the query, a
+// Scan, the driver or a cancelled ctx — is yielded once as (nil, err) and
+// ends the sequence.
+func (e *executor[TModel]) Iterate(ctx context.Context, stmt Stmt) iter.Seq2[*TModel, error] {
+	return func(yield func(*TModel, error) bool) {
+		sql, args, err := stmt.SQL()
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to get sql query from stmt: %w", err))
+			return
+		}
+		rows, err := e.getReadQuery(ctx).QueryContext(ctx, sql, args...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close() //nolint:errcheck
+		for rows.Next() {
+			if err = ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			model := new(TModel)
+			if err = rows.Scan(stmt.Columns().GetModelPointers(model)...); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(model, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
@@ -5,7 +5,11 @@ import (
 )
 
 type options struct {
-	cacheSource cache.Storage
+	cacheSource  cache.Storage
+	readers      []Adapter
+	copyOnRead   bool
+	clone        any
+	singleFlight bool
 }
 
 type Option interface {
@@ -28,3 +32,59 @@ func WithCacheStorage(source cache.Storage) Option {
 		}
 	})
 }
+
+// WithSingleFlight coalesces the identical reads of the repository running at
+// the same time — GetFirst, GetList, GetPage, Count and the lookups by key
+// with the same SQL and args: one query goes to the database and every
+// caller gets its result. A caller whose ctx ends stops waiting with the ctx
+// error; the query is cancelled once no caller waits for it. Reads inside a
+// transaction are never coalesced, and reads forced to the primary
+// (ForcePrimary) only with each other. A read that panics makes every
+// caller waiting for it panic.
+//
+// Each caller gets models of its own, copied from those read with the clone
+// function of WithCloneFunc, or deeply as WithCopyOnRead does.
+func WithSingleFlight() Option {
+	return optionFn(func(o *options) {
+		o.singleFlight = true
+	})
+}
+
+// WithReaders routes the reads of the repository — GetFirst, GetList, GetPage,
+// Iterate, Count and the lookups by key — to readers, the adapters of
+// streaming replicas, picked round-robin; writes stay on the adapter the
+// executor was built with. A ctx carrying a transaction, or marked with
+// ForcePrimary, keeps its reads on that adapter too. The readers must talk to
+// the same kind of database as the writer: statements follow its dialect.
+func WithReaders(readers ...Adapter) Option {
+	return optionFn(func(o *options) {
+		for _, r := range readers {
+			if r != nil {
+				o.readers = append(o.readers, r)
+			}
+		}
+	})
+}
+
+// WithCopyOnRead makes the cache hand out copies of the models it keeps, so
+// that a caller mutating a result leaves the entry — and the results of the
+// later reads — untouched. The models are copied when the read fills the
+// cache too. A copy follows the exported pointer, slice, map and array fields
+// of the model; unexported fields are shared with the entry. Storages that
+// decode their entries on every read (executor/cache/redis) need no copy.
+func WithCopyOnRead() Option {
+	return optionFn(func(o *options) {
+		o.copyOnRead = true
+	})
+}
+
+// WithCloneFunc is WithCopyOnRead with clone copying the models, for models
+// keeping state in unexported fields or copied faster by hand. TModel must be
+// the model of the repository: New panics otherwise.
+func WithCloneFunc[TModel any](clone func(*TModel) *TModel) Option {
+	return optionFn(func(o *options) {
+		if clone != nil {
+			o.clone = clone
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/insei/gerpo/dialect"
+	extypes "github.com/insei/gerpo/executor/types"
+	"github.com/insei/gerpo/types"
+)
+
+// ReadBackStmt is an optional capability of write statements: the target
+// table and its column storage. On dialects without RETURNING (see
+// dialect.Dialect.SupportsReturning) the renderers drop the clause, and the
+// executor runs the statement with ExecContext and reads the RETURNING
+// columns back with a SELECT matched on the primary key.
+type ReadBackStmt interface {
+	Table() string
+	ColumnsStorage() types.ColumnsStorage
+}
+
+// supportsReturning reports whether the dialect bound to ctx renders
+// RETURNING clauses.
+func supportsReturning(ctx context.Context) bool {
+	return dialect.FromContext(ctx).SupportsReturning()
+}
+
+// readBackTarget resolves the table and the primary key columns the rows of
+// stmt are read back by.
+func readBackTarget(stmt any) (string, []types.Column, error) {
+	rs, ok := stmt.(ReadBackStmt)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: statement does not expose its table", ErrReturningNotSupported)
+	}
+	var pk []types.Column
+	for _, c := range rs.ColumnsStorage().AsSlice() {
+		if c.IsPrimaryKey() {
+			pk = append(pk, c)
+		}
+	}
+	if len(pk) == 0 {
+		return "", nil, fmt.Errorf("%w: no PrimaryKey() column to match the rows by", ErrReturningNotSupported)
+	}
+	return rs.Table(), pk, nil
+}
+
+// withoutColumns returns cols minus the ones in drop, keeping the order.
+func withoutColumns(cols, drop []types.Column) []types.Column {
+	return slices.DeleteFunc(slices.Clone(cols), func(c types.Column) bool {
+		return slices.Contains(drop, c)
+	})
+}
+
+// insertOneReadBack is InsertOne on a dialect without RETURNING. A single
+// integer primary key in the RETURNING set is taken from the driver's
+// LastInsertId (AUTO_INCREMENT); the remaining columns are selected back by
+// that key.
+func (e *executor[TModel]) insertOneReadBack(ctx context.Context, stmt Stmt, sql string, values []any, returning []types.Column, model *TModel) error {
+	table, pk, err := readBackTarget(stmt)
+	if err != nil {
+		return err
+	}
+	rest := withoutColumns(returning, pk)
+	generatedKey := len(rest) != len(returning)
+	if generatedKey {
+		if len(pk) != 1 {
+			return fmt.Errorf("%w: a composite primary key cannot be generated", ErrReturningNotSupported)
+		}
+		if !isIntegerColumn(pk[0]) {
+			return fmt.Errorf("%w: a generated primary key must be an integer", ErrReturningNotSupported)
+		}
+	}
+	result, err := e.getExecQuery(ctx).ExecContext(ctx, sql, values...)
+	if err != nil {
+		return err
+	}
+	insertedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if insertedRows == 0 {
+		return noInsertedRowsErr(stmt)
+	}
+	// The row is in: drop the cached reads before anything below can fail.
+	clean(ctx, e.cacheSource)
+	if generatedKey {
+		ir, ok := result.(extypes.InsertIDResult)
+		if !ok {
+			return fmt.Errorf("%w: the driver result has no LastInsertId", ErrReturningNotSupported)
+		}
+		id, err := ir.LastInsertId()
+		if err != nil {
+			return err
+		}
+		setInteger(reflect.ValueOf(pk[0].GetPtr(model)).Elem(), id)
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	n, err := e.readBack(ctx, table, rest, pk, []*TModel{model})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("read-back found no row for the inserted key")
+	}
+	return nil
+}
+
+// readBack selects the columns cols of the rows keyed like models and writes
+// them into the models they belong to, pairing rows and models by key the
+// way RETURNING rows of batched writes are paired (scanReturningByKey).
+func (e *executor[TModel]) readBack(ctx context.Context, table string, cols, key []types.Column, models []*TModel) (int64, error) {
+	selected := append(slices.Clone(key), withoutColumns(cols, key)...)
+	d := dialect.FromContext(ctx)
+	sb := strings.Builder{}
+	sb.WriteString("SELECT ")
+	for i, c := range selected {
+		name, ok := c.Name()
+		if !ok {
+			return 0, fmt.Errorf("%w: column %s has no name to select", ErrReturningNotSupported, c.GetField().GetStructPath())
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(dialect.Ident(d, name))
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(dialect.Ident(d, table))
+	sb.WriteString(" WHERE ")
+	args := make([]any, 0, len(models)*len(key))
+	if len(key) == 1 {
+		name, _ := key[0].Name()
+		sb.WriteString(dialect.Ident(d, name))
+		sb.WriteString(" IN (")
+		for i, m := range models {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('?')
+			args = append(args, reflect.ValueOf(key[0].GetPtr(m)).Elem().Interface())
+		}
+		sb.WriteByte(')')
+	} else {
+		for i, m := range models {
+			if i > 0 {
+				sb.WriteString(" OR ")
+			}
+			sb.WriteByte('(')
+			for j, c := range key {
+				if j > 0 {
+					sb.WriteString(" AND ")
+				}
+				name, _ := c.Name()
+				sb.WriteString(dialect.Ident(d, name))
+				sb.WriteString(" = ?")
+				args = append(args, reflect.ValueOf(c.GetPtr(m)).Elem().Interface())
+			}
+			sb.WriteByte(')')
+		}
+	}
+	rows, err := e.getExecQuery(ctx).QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close() //nolint:errcheck
+	return scanReturningByKey(rows, selected, key, models)
+}
+
+func isIntegerColumn(c types.Column) bool {
+	switch c.GetField().GetDereferencedType().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// setInteger stores id into the integer field v, allocating it when v is a
+// nil pointer.
+func setInteger(v reflect.Value, id int64) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.CanInt() {
+		v.SetInt(id)
+		return
+	}
+	v.SetUint(uint64(id)) //nolint:gosec // AUTO_INCREMENT values are non-negative
+}
+
+// updateReadBack is Update on a dialect without RETURNING: the columns are
+// selected back by the model's primary key once the UPDATE matched a row.
+func (e *executor[TModel]) updateReadBack(ctx context.Context, stmt Stmt, sql string, values []any, returning []types.Column, model *TModel) (int64, error) {
+	table, pk, err := readBackTarget(stmt)
+	if err != nil {
+		return 0, err
+	}
+	return e.execReadBack(ctx, sql, values, table, returning, pk, []*TModel{model})
+}
+
+// execReadBack runs a write that affects the rows of models and reads the
+// columns cols back by key. It reports RowsAffected of the write.
+func (e *executor[TModel]) execReadBack(ctx context.Context, sql string, values []any, table string, cols, key []types.Column, models []*TModel) (int64, error) {
+	result, err := e.getExecQuery(ctx).ExecContext(ctx, sql, values...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return n, err
+	}
+	clean(ctx, e.cacheSource)
+	if _, err := e.readBack(ctx, table, cols, key, models); err != nil {
+		return n, err
+	}
+	return n, nil
+}
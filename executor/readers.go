@@ -0,0 +1,36 @@
+package executor
+
+import "context"
+
+// forcePrimaryKey marks a context whose reads go to the writer.
+type forcePrimaryKey struct{}
+
+// ForcePrimary returns a derived context whose reads go to the writer adapter
+// even when the repository has readers (see WithReaders) — for
+// read-your-writes paths that cannot afford the replication lag. Such reads
+// skip the cache lookup, whose entries the readers may have filled with
+// lagged rows, and fill it with theirs.
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+// getReadQuery returns the ExecQuery for a read: the ctx Tx when there is
+// one, a reader unless ctx forces the primary, the writer otherwise.
+func (e *executor[TModel]) getReadQuery(ctx context.Context) ExecQuery {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	if len(e.readers) == 0 {
+		return e.db
+	}
+	if forcedPrimary(ctx) {
+		return e.db
+	}
+	return e.readers[(e.nextReader.Add(1)-1)%uint64(len(e.readers))]
+}
+
+// forcedPrimary reports whether ctx was marked with ForcePrimary.
+func forcedPrimary(ctx context.Context) bool {
+	forced, _ := ctx.Value(forcePrimaryKey{}).(bool)
+	return forced
+}
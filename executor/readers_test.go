@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReaders(t *testing.T) {
+	newMock := func(t *testing.T) (Adapter, sqlmock.Sqlmock) {
+		t.Helper()
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		return databasesql.NewAdapter(db), mock
+	}
+	countRows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"count"}).AddRow(1) }
+	stmt := new(mockStmt)
+	stmt.On("SQL").Return(`SELECT COUNT(*) FROM users`, []any{}, nil)
+
+	writer, writerMock := newMock(t)
+	reader1, reader1Mock := newMock(t)
+	reader2, reader2Mock := newMock(t)
+	e := New[testModel](writer, WithReaders(reader1, nil, reader2)).(*executor[testModel])
+
+	t.Run("Reads go to the readers round-robin", func(t *testing.T) {
+		reader1Mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(countRows())
+		reader2Mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(countRows())
+		reader1Mock.ExpectQuery(`SELECT COUNT`).WillReturnRows(countRows())
+		for range 3 {
+			_, err := e.Count(context.Background(), stmt)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Writes go to the writer", func(t *testing.T) {
+		writerMock.ExpectExec(`SELECT COUNT`).WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err := e.Delete(context.Background(), stmt)
+		require.NoError(t, err)
+	})
+
+	t.Run("ForcePrimary keeps reads on the writer", func(t *testing.T) {
+		writerMock.ExpectQuery(`SELECT COUNT`).WillReturnRows(countRows())
+		_, err := e.Count(ForcePrimary(context.Background()), stmt)
+		require.NoError(t, err)
+	})
+
+	t.Run("A ctx transaction wins over the readers", func(t *testing.T) {
+		tx := &stubTxExecQuery{}
+		assert.Same(t, tx, e.getReadQuery(WithTx(context.Background(), tx)))
+	})
+
+	t.Run("Without readers reads go to the writer", func(t *testing.T) {
+		plain := New[testModel](writer).(*executor[testModel])
+		assert.Same(t, writer, plain.getReadQuery(context.Background()))
+	})
+
+	require.NoError(t, writerMock.ExpectationsWereMet())
+	require.NoError(t, reader1Mock.ExpectationsWereMet())
+	require.NoError(t, reader2Mock.ExpectationsWereMet())
+}
@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/insei/gerpo/types"
+)
+
+// flightGroup coalesces the identical reads running at the same time; see
+// WithSingleFlight.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is one read shared by the callers waiting for it.
+type flight struct {
+	done    chan struct{}
+	value   any
+	err     error
+	panic   *flightPanic
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// flightPanic is what the callers of a flight panic with when its read
+// panicked: the value read panicked with and the stack of the read.
+type flightPanic struct {
+	value any
+	stack []byte
+}
+
+func (p *flightPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// Unwrap returns the value read panicked with when it is an error.
+func (p *flightPanic) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// do runs read once for the callers of do with the same key until it
+// returns, and hands its result to each of them. read runs on a context
+// carrying the values of the ctx of the first caller but not its deadline:
+// a caller whose ctx ends stops waiting with ctx.Err(), and read is
+// cancelled only once every caller has stopped waiting. A panic of read does
+// not crash the process from the goroutine running it: every caller waiting
+// for the flight panics with a *flightPanic instead.
+func (g *flightGroup) do(ctx context.Context, key string, read func(ctx context.Context) (any, error)) (any, error) {
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		readCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go func() {
+			defer cancel()
+			defer func() {
+				if r := recover(); r != nil {
+					f.panic = &flightPanic{value: r, stack: debug.Stack()}
+				}
+				g.mu.Lock()
+				g.forget(key, f)
+				g.mu.Unlock()
+				close(f.done)
+			}()
+			f.value, f.err = read(readCtx)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.panic != nil {
+			panic(f.panic)
+		}
+		return f.value, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if f.waiters--; f.waiters == 0 {
+			f.cancel()
+			g.forget(key, f)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget drops f from the group unless a newer flight took its key. g.mu is
+// held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
+
+// coalesce runs read through the flight group of e, keyed by kind — the
+// result type of read — and the statement. Without WithSingleFlight, and
+// inside a transaction, whose reads may see its own uncommitted rows, read
+// runs on its own.
+func coalesce[TModel, T any](ctx context.Context, e *executor[TModel], kind, sql string, args []any, read func(ctx context.Context) (T, error)) (T, error) {
+	if e.flights == nil {
+		return read(ctx)
+	}
+	if _, ok := TxFromContext(ctx); ok {
+		return read(ctx)
+	}
+	key := kind + ":"
+	if forcedPrimary(ctx) {
+		key += "primary:"
+	}
+	value, err := e.flights.do(ctx, key+cache.Key(sql, args...), func(ctx context.Context) (any, error) {
+		return read(ctx)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
+
+// columnsOf returns how a read gets the columns of stmt. A flight may outlive
+// the call that started it, and the statement with it — the repository
+// releases it to its pool — so under WithSingleFlight they are taken at once.
+func (e *executor[TModel]) columnsOf(stmt Stmt) func() types.ExecutionColumns {
+	if e.flights == nil {
+		return stmt.Columns
+	}
+	columns := stmt.Columns()
+	return func() types.ExecutionColumns { return columns }
+}
@@ -0,0 +1,229 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingRead is a read counting its calls and returning value once release
+// is closed or failing with the error of its ctx.
+type blockingRead struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRead() *blockingRead {
+	return &blockingRead{started: make(chan struct{}, 16), release: make(chan struct{})}
+}
+
+func (r *blockingRead) read(value any) func(ctx context.Context) (any, error) {
+	return func(ctx context.Context) (any, error) {
+		r.calls.Add(1)
+		r.started <- struct{}{}
+		select {
+		case <-r.release:
+			return value, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// waiters reports the number of callers waiting for the flight of key.
+func (g *flightGroup) waiters(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.flights[key]; ok {
+		return f.waiters
+	}
+	return 0
+}
+
+func TestFlightGroup_Do(t *testing.T) {
+	g := newFlightGroup()
+	r := newBlockingRead()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]any, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = g.do(context.Background(), "key", r.read("value"))
+		}()
+	}
+	require.Eventually(t, func() bool { return g.waiters("key") == callers }, time.Second, time.Millisecond)
+	close(r.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), r.calls.Load(), "identical reads run once")
+	for _, got := range results {
+		assert.Equal(t, "value", got)
+	}
+	assert.Zero(t, g.waiters("key"))
+
+	r = newBlockingRead()
+	close(r.release)
+	_, _ = g.do(context.Background(), "key", r.read("next"))
+	assert.Equal(t, int32(1), r.calls.Load(), "a finished flight is not reused")
+}
+
+func TestFlightGroup_Do_Cancel(t *testing.T) {
+	g := newFlightGroup()
+	r := newBlockingRead()
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(leaderCtx, "key", r.read("value"))
+		leaderErr <- err
+	}()
+	<-r.started
+	follower := make(chan any, 1)
+	go func() {
+		v, _ := g.do(context.Background(), "key", r.read("other"))
+		follower <- v
+	}()
+	require.Eventually(t, func() bool { return g.waiters("key") == 2 }, time.Second, time.Millisecond)
+
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled, "a cancelled caller stops waiting")
+	close(r.release)
+	assert.Equal(t, "value", <-follower, "the read goes on for the other callers")
+
+	r = newBlockingRead()
+	ctx, cancel := context.WithCancel(context.Background())
+	readErr := make(chan error, 1)
+	go func() {
+		_, _ = g.do(ctx, "key", func(ctx context.Context) (any, error) {
+			v, err := r.read(nil)(ctx)
+			readErr <- err
+			return v, err
+		})
+	}()
+	<-r.started
+	cancel()
+	assert.ErrorIs(t, <-readErr, context.Canceled, "the read is cancelled once no caller waits")
+	assert.Zero(t, g.waiters("key"))
+}
+
+func TestFlightGroup_Do_Panic(t *testing.T) {
+	g := newFlightGroup()
+	r := newBlockingRead()
+	read := func(ctx context.Context) (any, error) {
+		_, _ = r.read(nil)(ctx)
+		panic(assert.AnError)
+	}
+
+	const callers = 3
+	var wg sync.WaitGroup
+	recovered := make([]any, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { recovered[i] = recover() }()
+			_, _ = g.do(context.Background(), "key", read)
+		}()
+	}
+	require.Eventually(t, func() bool { return g.waiters("key") == callers }, time.Second, time.Millisecond)
+	close(r.release)
+	wg.Wait()
+
+	for _, got := range recovered {
+		err, ok := got.(error)
+		require.True(t, ok, "every caller panics")
+		assert.ErrorIs(t, err, assert.AnError)
+	}
+	assert.Zero(t, g.waiters("key"))
+}
+
+func TestCoalesce(t *testing.T) {
+	e := New[testModel](nil, WithSingleFlight()).(*executor[testModel])
+	r := newBlockingRead()
+	read := func(ctx context.Context) (string, error) {
+		v, err := r.read("value")(ctx)
+		if err != nil {
+			return "", err
+		}
+		return v.(string), nil
+	}
+
+	var wg sync.WaitGroup
+	for _, ctx := range []context.Context{
+		context.Background(),
+		context.Background(),
+		ForcePrimary(context.Background()),
+		WithTx(context.Background(), &stubTxExecQuery{}),
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := coalesce(ctx, e, "one", "SELECT", []any{1}, read)
+			assert.NoError(t, err)
+			assert.Equal(t, "value", got)
+		}()
+	}
+	require.Eventually(t, func() bool { return r.calls.Load() == 3 }, time.Second, time.Millisecond,
+		"reads forced to the primary and inside a tx keep apart")
+	require.Eventually(t, func() bool { return e.flights.waiters("one:SELECT[1]") == 2 }, time.Second, time.Millisecond)
+	close(r.release)
+	wg.Wait()
+
+	plain := New[testModel](nil).(*executor[testModel])
+	assert.Nil(t, plain.flights)
+}
+
+// testModelColumns scans id, age and name into a testModel.
+type testModelColumns struct {
+	types.ExecutionColumns
+}
+
+func (testModelColumns) GetModelPointers(model any) []any {
+	m := model.(*testModel)
+	return []any{&m.ID, &m.Age, &m.Name}
+}
+
+func TestGetMultiple_SingleFlight(t *testing.T) {
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mockDB.ExpectQuery("SELECT id, age, name FROM users").
+		WillDelayFor(100 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "age", "name"}).AddRow(1, 2, "test"))
+
+	stmt := new(mockStmt)
+	stmt.On("SQL").Return("SELECT id, age, name FROM users", []any{}, nil)
+	stmt.On("Columns").Return(testModelColumns{})
+	e := New[testModel](databasesql.NewAdapter(db), WithSingleFlight())
+
+	var wg sync.WaitGroup
+	results := make([][]*testModel, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models, err := e.GetMultiple(context.Background(), stmt)
+			assert.NoError(t, err)
+			results[i] = models
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, mockDB.ExpectationsWereMet(), "one query for the three calls")
+	for _, models := range results {
+		assert.Equal(t, []*testModel{{ID: 1, Age: 2, Name: "test"}}, models)
+	}
+	assert.NotSame(t, results[0][0], results[1][0], "each caller gets its own models")
+}
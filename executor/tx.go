@@ -27,10 +27,10 @@ func WithTx(ctx context.Context, tx extypes.Tx) context.Context {
 	return context.WithValue(ctx, txKey{}, tx)
 }
 
-// txFromContext retrieves a Tx previously installed by WithTx. The boolean
+// TxFromContext retrieves a Tx previously installed by WithTx. The boolean
 // signals whether the context actually carries one — a plain nil check on the
 // Tx is not enough because a typed-nil interface would read as non-nil.
-func txFromContext(ctx context.Context) (extypes.Tx, bool) {
+func TxFromContext(ctx context.Context) (extypes.Tx, bool) {
 	tx, ok := ctx.Value(txKey{}).(extypes.Tx)
 	return tx, ok && tx != nil
 }
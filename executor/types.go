@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	extypes "github.com/insei/gerpo/executor/types"
 	"github.com/insei/gerpo/sqlstmt"
@@ -10,22 +11,56 @@ import (
 )
 
 var ErrNoInsertedRows = fmt.Errorf("failed to insert: inserted 0 rows")
+var ErrInsertConflict = fmt.Errorf("failed to insert: row skipped by ON CONFLICT")
 var ErrNoRows = fmt.Errorf("executor: no rows in result set")
+var ErrCopyNotSupported = fmt.Errorf("executor: adapter does not support COPY FROM")
+var ErrReturningNotSupported = fmt.Errorf("executor: dialect has no RETURNING and the rows cannot be read back")
 
 type Tx = extypes.Tx
 type ExecQuery = extypes.ExecQuery
 type Adapter extypes.Adapter
+type Copier = extypes.Copier
+type CopySource = extypes.CopySource
+type TxOptions = extypes.TxOptions
+type TxOptionsBeginner = extypes.TxOptionsBeginner
+type TxCallbacks = extypes.TxCallbacks
+type ConstraintViolation = extypes.ConstraintViolation
+type ErrUniqueViolation = extypes.ErrUniqueViolation
+type ErrForeignKeyViolation = extypes.ErrForeignKeyViolation
+type ErrCheckViolation = extypes.ErrCheckViolation
+type ErrNotNullViolation = extypes.ErrNotNullViolation
+type IsolationLevel = extypes.IsolationLevel
+
+const (
+	LevelDefault         = extypes.LevelDefault
+	LevelReadUncommitted = extypes.LevelReadUncommitted
+	LevelReadCommitted   = extypes.LevelReadCommitted
+	LevelRepeatableRead  = extypes.LevelRepeatableRead
+	LevelSerializable    = extypes.LevelSerializable
+)
 
 type Executor[TModel any] interface {
 	GetOne(ctx context.Context, stmt Stmt) (*TModel, error)
 	GetMultiple(ctx context.Context, stmt Stmt) ([]*TModel, error)
+	GetPage(ctx context.Context, stmt PageStmt) ([]*TModel, uint64, error)
+	Iterate(ctx context.Context, stmt Stmt) iter.Seq2[*TModel, error]
 	InsertOne(ctx context.Context, stmt Stmt, model *TModel) error
 	InsertMany(ctx context.Context, stmt BatchStmt, models []*TModel) (int64, error)
+	BulkCopy(ctx context.Context, stmt CopyStmt, models []*TModel) (int64, error)
 	Update(ctx context.Context, stmt Stmt, model *TModel) (int64, error)
+	UpdateWhere(ctx context.Context, stmt Stmt) ([]*TModel, int64, error)
+	UpdateMany(ctx context.Context, stmt KeyedBatchStmt, models []*TModel) (int64, error)
 	Count(ctx context.Context, stmt CountStmt) (uint64, error)
 	Delete(ctx context.Context, stmt CountStmt) (int64, error)
 }
 
+// CacheBinder is an optional capability of an Executor: handing its cache
+// storage the tables the reads of the repository depend on (cache.Binder).
+// The repository calls BindCache once, when it is built.
+type CacheBinder interface {
+	BindCache(table string, dependencies []string)
+}
+
 type CountStmt interface {
 	SQL(...sqlstmt.Option) (string, []any, error)
 }
@@ -40,6 +75,14 @@ type Stmt interface {
 	Columns() types.ExecutionColumns
 }
 
+// PageStmt is a list statement that can carry the size of the whole filtered
+// set as one extra trailing column (count(*) over()). WithTotal switches the
+// column on; the executor scans it after the model columns of every row.
+type PageStmt interface {
+	Stmt
+	WithTotal()
+}
+
 // BatchStmt is the shape the executor expects for multi-row writes. The
 // executor feeds a chunk of models via SetModels, then asks for the SQL — this
 // lets one InsertBatch value render many chunked statements per call.
@@ -48,6 +91,23 @@ type BatchStmt interface {
 	SetModels(models []any)
 }
 
+// KeyedBatchStmt is the shape the executor expects for batched UPDATEs: a
+// BatchStmt whose rows are matched to models by the Key columns. RETURNING
+// rows are paired with models by the key values, not by position.
+type KeyedBatchStmt interface {
+	BatchStmt
+	Key() []types.Column
+}
+
+// CopyStmt is the shape the executor expects for COPY FROM loads: the target
+// table and the INSERT column set, names in the order GetModelValues yields
+// the values.
+type CopyStmt interface {
+	Table() string
+	Columns() types.ExecutionColumns
+	ColumnNames() ([]string, error)
+}
+
 // ReturningStmt is an optional capability of write statements (Insert / Update)
 // that can emit a RETURNING clause. The returned slice lists the columns
 // scanned back into the caller's model after the SQL runs; an empty slice
@@ -55,3 +115,11 @@ type BatchStmt interface {
 type ReturningStmt interface {
 	ReturningColumns() []types.Column
 }
+
+// ConflictStmt is an optional capability of INSERT statements that can carry
+// an ON CONFLICT clause. When HasConflictClause reports true, an INSERT that
+// affected no rows is reported as ErrInsertConflict — the row was skipped by
+// DO NOTHING (or by the DO UPDATE ... WHERE condition) rather than lost.
+type ConflictStmt interface {
+	HasConflictClause() bool
+}
@@ -3,7 +3,11 @@
 // compatibility with the public API.
 package types //nolint:revive // public API package name kept for backwards compatibility
 
-import "context"
+import (
+	"context"
+
+	"github.com/insei/gerpo/dialect"
+)
 
 type Result interface {
 	RowsAffected() (int64, error)
@@ -29,13 +33,77 @@ type Tx interface {
 
 // Adapter is the interface gerpo expects from a bundled or custom database
 // adapter — the thin shim between a driver (pgx v5, pgx v4, database/sql, ...)
-// and the executor.
+// and the executor. Dialect reports the SQL flavour of the database behind
+// it; the statements the repository renders follow it.
 type Adapter interface {
 	ExecQuery
 	BeginTx(ctx context.Context) (Tx, error)
+	Dialect() dialect.Dialect
 }
 
 type ExecQuery interface {
 	ExecContext(ctx context.Context, query string, args ...any) (Result, error)
 	QueryContext(ctx context.Context, query string, args ...any) (Rows, error)
 }
+
+// CopySource streams rows into Copier.CopyFrom. The method set matches
+// pgx.CopyFromSource, so bundled pgx adapters hand it over to the driver
+// as-is.
+type CopySource interface {
+	Next() bool
+	Values() ([]any, error)
+	Err() error
+}
+
+// Copier is an optional capability of an Adapter (and of the Tx it opens):
+// bulk loading rows through PostgreSQL COPY FROM. The executor discovers it
+// with a type assertion. COPY reports the number of rows written only — there
+// is no per-row RETURNING.
+type Copier interface {
+	CopyFrom(ctx context.Context, table string, columns []string, src CopySource) (int64, error)
+}
+
+// IsolationLevel is the isolation level of a transaction opened with
+// TxOptions. The zero value leaves the database default.
+type IsolationLevel int
+
+const (
+	LevelDefault IsolationLevel = iota
+	LevelReadUncommitted
+	LevelReadCommitted
+	LevelRepeatableRead
+	LevelSerializable
+)
+
+// TxOptions configures a transaction opened by TxOptionsBeginner. The zero
+// value is a plain BeginTx.
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+	// Deferrable lets a SERIALIZABLE READ ONLY transaction wait for a
+	// snapshot that cannot fail serialization. PostgreSQL only.
+	Deferrable bool
+}
+
+// TxOptionsBeginner is an optional capability of an Adapter: opening a
+// transaction with TxOptions. The bundled adapters have it; gerpo.RunInTx
+// discovers it with a type assertion when options are passed.
+type TxOptionsBeginner interface {
+	BeginTxWithOptions(ctx context.Context, opts TxOptions) (Tx, error)
+}
+
+// TxCallbacks is an optional capability of a Tx: functions to run once the
+// transaction has ended. OnCommit functions run after a successful Commit,
+// OnRollback functions after Rollback — both in the order they were
+// registered, each at most once. Transactions of the bundled adapters have it.
+type TxCallbacks interface {
+	OnCommit(fn func())
+	OnRollback(fn func())
+}
+
+// InsertIDResult is an optional capability of a Result: the value the INSERT
+// generated for an AUTO_INCREMENT column. database/sql results have it; the
+// executor uses it to read the key back on dialects without RETURNING.
+type InsertIDResult interface {
+	LastInsertId() (int64, error)
+}
@@ -0,0 +1,71 @@
+package types //nolint:revive // public API package name kept for backwards compatibility
+
+// ConstraintKind is the kind of constraint a ConstraintViolation broke.
+type ConstraintKind int
+
+const (
+	ConstraintUnique ConstraintKind = iota + 1
+	ConstraintForeignKey
+	ConstraintCheck
+	ConstraintNotNull
+)
+
+// ConstraintViolation carries what the database reported about a broken
+// constraint. It is embedded in ErrUniqueViolation, ErrForeignKeyViolation,
+// ErrCheckViolation and ErrNotNullViolation; the adapter fills everything but
+// Field, the repository the failing column belongs to fills Field.
+type ConstraintViolation struct {
+	// Constraint is the name of the constraint, empty when the database does
+	// not report it (SQLite names CHECK constraints only).
+	Constraint string
+	// Table is the table the constraint belongs to, unqualified.
+	Table string
+	// Column is the failing column; the columns of a multi-column key are
+	// joined with ", ". Empty when the database does not report it.
+	Column string
+	// Field is the struct path of the model field Column maps to, fields
+	// joined with ", " like the columns. Empty when a column maps to none.
+	Field string
+	// Err is the driver error.
+	Err error
+}
+
+func (v *ConstraintViolation) Error() string { return v.Err.Error() }
+func (v *ConstraintViolation) Unwrap() error { return v.Err }
+
+// Violation returns v. Promoted to every violation type, it reaches their
+// common fields through one interface:
+//
+//	var v interface{ Violation() *ConstraintViolation }
+//	if errors.As(err, &v) { ... v.Violation().Constraint ... }
+func (v *ConstraintViolation) Violation() *ConstraintViolation { return v }
+
+// ErrUniqueViolation is a duplicate key of a UNIQUE or PRIMARY KEY constraint
+// (SQLSTATE 23505).
+type ErrUniqueViolation struct{ ConstraintViolation }
+
+// ErrForeignKeyViolation is a reference to a missing row, or a delete of a
+// referenced one (SQLSTATE 23503).
+type ErrForeignKeyViolation struct{ ConstraintViolation }
+
+// ErrCheckViolation is a row failing a CHECK constraint (SQLSTATE 23514).
+type ErrCheckViolation struct{ ConstraintViolation }
+
+// ErrNotNullViolation is a NULL in a NOT NULL column (SQLSTATE 23502).
+type ErrNotNullViolation struct{ ConstraintViolation }
+
+// NewConstraintViolation returns v as the violation type of kind, nil for an
+// unknown kind. Adapters use it to hand driver errors back normalized.
+func NewConstraintViolation(kind ConstraintKind, v ConstraintViolation) error {
+	switch kind {
+	case ConstraintUnique:
+		return &ErrUniqueViolation{v}
+	case ConstraintForeignKey:
+		return &ErrForeignKeyViolation{v}
+	case ConstraintCheck:
+		return &ErrCheckViolation{v}
+	case ConstraintNotNull:
+		return &ErrNotNullViolation{v}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+)
+
+// UpdateMany emits one batched UPDATE per chunk, chunking the input at the
+// same placeholder budget as InsertMany. Every row carries its key and SET
+// values, so the budget is split by their sum. With RETURNING configured the
+// scanned rows are written back into the models they belong to by key —
+// models whose row does not exist (or is filtered out) are left untouched and
+// not counted. Dialects without RETURNING select the columns back by key
+// after every chunk; there the count comes from RowsAffected and the rows the
+// UPDATE skipped are read back too.
+//
+// As with InsertMany, failures leave the chunks already applied in place.
+func (e *executor[TModel]) UpdateMany(ctx context.Context, stmt KeyedBatchStmt, models []*TModel) (int64, error) {
+	if len(models) == 0 {
+		return 0, nil
+	}
+	key := stmt.Key()
+	valsPerRow := len(key)
+	for _, c := range stmt.Columns().GetAll() {
+		if _, ok := c.Name(); ok {
+			valsPerRow++
+		}
+	}
+	chunkSize := chunkRows(ctx, valsPerRow)
+
+	returning := returningColumnsOfBatch(stmt)
+	var readBackTable string
+	if len(returning) > 0 && !supportsReturning(ctx) {
+		rs, ok := any(stmt).(ReadBackStmt)
+		if !ok {
+			return 0, fmt.Errorf("%w: statement does not expose its table", ErrReturningNotSupported)
+		}
+		readBackTable = rs.Table()
+	}
+	chunkBuf := make([]any, 0, min(chunkSize, len(models)))
+
+	var total int64
+	for start := 0; start < len(models); start += chunkSize {
+		end := min(start+chunkSize, len(models))
+		chunkBuf = chunkBuf[:0]
+		for _, m := range models[start:end] {
+			chunkBuf = append(chunkBuf, m)
+		}
+		stmt.SetModels(chunkBuf)
+
+		sql, values, err := stmt.SQL()
+		if err != nil {
+			return total, fmt.Errorf("failed to get sql query from stmt: %w", err)
+		}
+
+		if readBackTable != "" {
+			n, err := e.execReadBack(ctx, sql, values, readBackTable, returning, key, models[start:end])
+			total += n
+			if err != nil {
+				return total, err
+			}
+			continue
+		}
+		if len(returning) > 0 {
+			rows, err := e.getExecQuery(ctx).QueryContext(ctx, sql, values...)
+			if err != nil {
+				return total, err
+			}
+			n, err := scanReturningByKey(rows, returning, key, models[start:end])
+			_ = rows.Close()
+			total += n
+			if err != nil {
+				return total, err
+			}
+			continue
+		}
+		result, err := e.getExecQuery(ctx).ExecContext(ctx, sql, values...)
+		if err != nil {
+			return total, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	if total > 0 {
+		clean(ctx, e.cacheSource)
+	}
+	return total, nil
+}
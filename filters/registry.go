@@ -21,8 +21,8 @@ type Filter func(ctx context.Context, value any) (sql string, args []any, err er
 func stockFilter(op types.Operation, columnSQL string) Filter {
 	if gen := stockGenerator(op); gen != nil {
 		legacy := gen(columnSQL)
-		return func(_ context.Context, value any) (string, []any, error) {
-			sql, appendValue := legacy(nil, value)
+		return func(ctx context.Context, value any) (string, []any, error) {
+			sql, appendValue := legacy(ctx, value)
 			if !appendValue {
 				return sql, nil, nil
 			}
@@ -0,0 +1,21 @@
+// Package sqlstate reads the SQLSTATE code out of driver errors without
+// importing the drivers.
+package sqlstate
+
+import "errors"
+
+// Of returns the SQLSTATE of err or of an error it wraps, "" when none
+// carries one. It understands *pgconn.PgError of pgx v4 and v5 — also behind
+// database/sql with pgx/stdlib — through its SQLState method, and *pq.Error
+// of lib/pq through Get('C'), the code field of the server message.
+func Of(err error) string {
+	var withState interface{ SQLState() string }
+	if errors.As(err, &withState) {
+		return withState.SQLState()
+	}
+	var withFields interface{ Get(k byte) string }
+	if errors.As(err, &withFields) {
+		return withFields.Get('C')
+	}
+	return ""
+}
@@ -13,6 +13,8 @@ import (
 	"context"
 	"strings"
 	"unsafe"
+
+	"github.com/insei/gerpo/dialect"
 )
 
 // Generator is the shared shape of every fragment generator. A generator is
@@ -92,54 +94,74 @@ func NotIn(query string) func(ctx context.Context, value any) (string, bool) {
 	}
 }
 
-// LIKE-family: bound arg wrapped in CAST(? AS text) so PostgreSQL can infer
-// the parameter type inside CONCAT. Works the same way on MySQL, so the
-// fragment stays portable.
+// LIKE-family: bound arg wrapped in a text cast so PostgreSQL can infer the
+// parameter type inside CONCAT. The cast and the predicate itself come from
+// the ctx dialect — CAST(? AS text) on PostgreSQL, CAST(? AS CHAR) on MySQL,
+// which has no text type for CAST; SQLite matches with GLOB / LIKE instead of
+// LOWER (see dialect.Dialect.Like).
+
+// textCast returns the bound placeholder cast to the text type of the ctx
+// dialect.
+func textCast(ctx context.Context) string {
+	return dialect.FromContext(ctx).TextCast("?")
+}
+
+// like renders the LIKE-family predicate l for the ctx dialect, matching query
+// against the bound value.
+func like(ctx context.Context, query string, l dialect.Like) string {
+	l.Expr, l.Value = query, textCast(ctx)
+	return dialect.FromContext(ctx).Like(l)
+}
 
 func Contains(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " LIKE CONCAT('%', CAST(? AS text), '%')", true
+		return like(ctx, query, dialect.Like{Prefix: true, Suffix: true}), true
 	}
 }
 
 func NotContains(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " NOT LIKE CONCAT('%', CAST(? AS text), '%')", true
+		return like(ctx, query, dialect.Like{Prefix: true, Suffix: true, Not: true}), true
 	}
 }
 
 func StartsWith(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " LIKE CONCAT(CAST(? AS text), '%')", true
+		return like(ctx, query, dialect.Like{Suffix: true}), true
 	}
 }
 
 func NotStartsWith(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " NOT LIKE CONCAT(CAST(? AS text), '%')", true
+		return like(ctx, query, dialect.Like{Suffix: true, Not: true}), true
 	}
 }
 
 func EndsWith(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " LIKE CONCAT('%', CAST(? AS text))", true
+		return like(ctx, query, dialect.Like{Prefix: true}), true
 	}
 }
 
 func NotEndsWith(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return query + " NOT LIKE CONCAT('%', CAST(? AS text))", true
+		return like(ctx, query, dialect.Like{Prefix: true, Not: true}), true
 	}
 }
 
 // Case-insensitive "fold" variants — naming mirrors strings.EqualFold.
 
+// lower folds the case of expr the way the ctx dialect does.
+func lower(ctx context.Context, expr string) string {
+	return dialect.FromContext(ctx).Lower(expr)
+}
+
 func EQFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
 		if value == nil {
 			return query + " IS NULL", false
 		}
-		return "LOWER(" + query + ") = LOWER(CAST(? AS text))", true
+		return lower(ctx, query) + " = " + lower(ctx, textCast(ctx)), true
 	}
 }
 
@@ -148,42 +170,42 @@ func NotEQFold(query string) func(ctx context.Context, value any) (string, bool)
 		if value == nil {
 			return query + " IS NOT NULL", false
 		}
-		return "LOWER(" + query + ") != LOWER(CAST(? AS text))", true
+		return lower(ctx, query) + " != " + lower(ctx, textCast(ctx)), true
 	}
 }
 
 func ContainsFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") LIKE LOWER(CONCAT('%', CAST(? AS text), '%'))", true
+		return like(ctx, query, dialect.Like{Prefix: true, Suffix: true, Fold: true}), true
 	}
 }
 
 func NotContainsFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") NOT LIKE LOWER(CONCAT('%', CAST(? AS text), '%'))", true
+		return like(ctx, query, dialect.Like{Prefix: true, Suffix: true, Fold: true, Not: true}), true
 	}
 }
 
 func StartsWithFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") LIKE LOWER(CONCAT(CAST(? AS text), '%'))", true
+		return like(ctx, query, dialect.Like{Suffix: true, Fold: true}), true
 	}
 }
 
 func NotStartsWithFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") NOT LIKE LOWER(CONCAT(CAST(? AS text), '%'))", true
+		return like(ctx, query, dialect.Like{Suffix: true, Fold: true, Not: true}), true
 	}
 }
 
 func EndsWithFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") LIKE LOWER(CONCAT('%', CAST(? AS text)))", true
+		return like(ctx, query, dialect.Like{Prefix: true, Fold: true}), true
 	}
 }
 
 func NotEndsWithFold(query string) func(ctx context.Context, value any) (string, bool) {
 	return func(ctx context.Context, value any) (string, bool) {
-		return "LOWER(" + query + ") NOT LIKE LOWER(CONCAT('%', CAST(? AS text)))", true
+		return like(ctx, query, dialect.Like{Prefix: true, Fold: true, Not: true}), true
 	}
 }
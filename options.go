@@ -2,7 +2,10 @@ package gerpo
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/query"
 )
 
@@ -174,6 +177,58 @@ func WithAfterInsertMany[TModel any](fn func(ctx context.Context, models []*TMod
 	})
 }
 
+// WithBeforeUpdateMany registers a callback invoked right before a batch of
+// models is updated via UpdateMany. The callback receives the full slice in
+// one call — the batched counterpart of WithBeforeUpdate, typically used to
+// stamp UpdatedAt on every model. Returning a non-nil error aborts the
+// UpdateMany; the SQL does NOT run.
+//
+// Chaining semantics match WithBeforeInsert.
+func WithBeforeUpdateMany[TModel any](fn func(ctx context.Context, models []*TModel) error) Option[TModel] {
+	return optionFn[TModel](func(o *repository[TModel]) error {
+		if fn == nil {
+			return nil
+		}
+		if o.beforeUpdateMany == nil {
+			o.beforeUpdateMany = fn
+			return nil
+		}
+		wrap := o.beforeUpdateMany
+		o.beforeUpdateMany = func(ctx context.Context, models []*TModel) error {
+			if err := wrap(ctx, models); err != nil {
+				return err
+			}
+			return fn(ctx, models)
+		}
+		return nil
+	})
+}
+
+// WithAfterUpdateMany registers a callback invoked after a successful
+// UpdateMany with the full slice.
+//
+// A non-nil error is surfaced AFTER the rows are already modified; the caller
+// decides whether to roll back an ambient transaction.
+func WithAfterUpdateMany[TModel any](fn func(ctx context.Context, models []*TModel) error) Option[TModel] {
+	return optionFn[TModel](func(o *repository[TModel]) error {
+		if fn == nil {
+			return nil
+		}
+		if o.afterUpdateMany == nil {
+			o.afterUpdateMany = fn
+			return nil
+		}
+		wrap := o.afterUpdateMany
+		o.afterUpdateMany = func(ctx context.Context, models []*TModel) error {
+			if err := wrap(ctx, models); err != nil {
+				return err
+			}
+			return fn(ctx, models)
+		}
+		return nil
+	})
+}
+
 // WithAfterUpdate registers a callback invoked after a successful Update.
 // Returning a non-nil error surfaces it to the caller AFTER the row was
 // already modified — same contract as WithAfterInsert.
@@ -228,3 +283,39 @@ func WithTracer[TModel any](tracer Tracer) Option[TModel] {
 		return nil
 	})
 }
+
+// defaultIterateBatchSize is the AfterSelect batch of Iterate unless
+// WithIterateBatchSize says otherwise.
+const defaultIterateBatchSize = 100
+
+// WithIterateBatchSize sets how many models Iterate collects before running
+// the AfterSelect hook on them and yielding them to the caller. Larger
+// batches mean fewer hook calls (and fewer round-trips for cascade reads) at
+// the cost of holding more models in memory. Size must be positive.
+func WithIterateBatchSize[TModel any](size int) Option[TModel] {
+	return optionFn[TModel](func(o *repository[TModel]) error {
+		if size < 1 {
+			return fmt.Errorf("iterate batch size must be positive, got %d", size)
+		}
+		o.iterateBatchSize = size
+		return nil
+	})
+}
+
+// withAdapter sets the adapter the repository opens its own transactions on —
+// the one a versioned UpdateMany runs in. Build passes its adapter through it.
+func withAdapter[TModel any](a executor.Adapter) Option[TModel] {
+	return optionFn[TModel](func(o *repository[TModel]) error {
+		o.adapter = a
+		return nil
+	})
+}
+
+// withDialect sets the SQL dialect the repository binds to the context of
+// every call. Build passes the adapter's dialect through it.
+func withDialect[TModel any](d dialect.Dialect) Option[TModel] {
+	return optionFn[TModel](func(o *repository[TModel]) error {
+		o.dialect = d
+		return nil
+	})
+}
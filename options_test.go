@@ -231,3 +231,19 @@ func TestWithErrorTransformer(t *testing.T) {
 		})
 	}
 }
+
+func TestWithIterateBatchSize(t *testing.T) {
+	r := &repository[exampleModel]{iterateBatchSize: defaultIterateBatchSize}
+	if err := WithIterateBatchSize[exampleModel](500).apply(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.iterateBatchSize != 500 {
+		t.Errorf("expected batch size 500, got %d", r.iterateBatchSize)
+	}
+	if err := WithIterateBatchSize[exampleModel](0).apply(r); err == nil {
+		t.Error("expected an error for a non-positive batch size")
+	}
+	if r.iterateBatchSize != 500 {
+		t.Errorf("rejected size must not change the batch size, got %d", r.iterateBatchSize)
+	}
+}
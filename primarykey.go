@@ -0,0 +1,88 @@
+package gerpo
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/insei/gerpo/types"
+)
+
+// keyChunkParams caps the key values bound by one GetByIDs statement. It stays
+// far below the PostgreSQL limit on purpose: other databases allow much fewer
+// parameters, and planners handle long IN lists poorly.
+const keyChunkParams = 1000
+
+// checkKey validates the values of one primary key against the declared key
+// columns.
+func (r *repository[TModel]) checkKey(key []any) error {
+	if len(r.primaryKey) == 0 {
+		return fmt.Errorf("%w: %w", ErrApplyQuery, ErrNoPrimaryKey)
+	}
+	if len(key) != len(r.primaryKey) {
+		return fmt.Errorf("%w: primary key has %d columns, got %d values", ErrApplyQuery, len(r.primaryKey), len(key))
+	}
+	return nil
+}
+
+// splitKeys turns the GetByIDs arguments into per-row key values: a value per
+// row for a single-column key, a []any per row for a composite one.
+func (r *repository[TModel]) splitKeys(keys []any) ([][]any, error) {
+	out := make([][]any, 0, len(keys))
+	for _, k := range keys {
+		key := []any{k}
+		if len(r.primaryKey) > 1 {
+			values, ok := k.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: composite primary key must be passed as []any, got %T", ErrApplyQuery, k)
+			}
+			key = values
+		}
+		if err := r.checkKey(key); err != nil {
+			return nil, err
+		}
+		out = append(out, key)
+	}
+	return out, nil
+}
+
+// modelKey reads the primary key values of the model.
+func (r *repository[TModel]) modelKey(model *TModel) []any {
+	key := make([]any, 0, len(r.primaryKey))
+	for _, col := range r.primaryKey {
+		key = append(key, reflect.ValueOf(col.GetPtr(model)).Elem().Interface())
+	}
+	return key
+}
+
+// whereKey matches the row of one key: `pk1 = ? AND pk2 = ?`.
+func whereKey(t types.WhereTarget, pk []types.Column, key []any) {
+	var cond types.ANDOR
+	for i, col := range pk {
+		if i > 0 {
+			t = cond.AND()
+		}
+		cond = t.Column(col).EQ(key[i])
+	}
+}
+
+// whereKeys matches the rows of several keys: `pk IN (...)` for a single-column
+// key, an OR of whereKey groups for a composite one.
+func whereKeys(t types.WhereTarget, pk []types.Column, keys [][]any) {
+	if len(pk) == 1 {
+		values := make([]any, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, key[0])
+		}
+		t.Column(pk[0]).In(values...)
+		return
+	}
+	var cond types.ANDOR
+	for i, key := range keys {
+		if i > 0 {
+			t = cond.OR()
+		}
+		cond = t.Group(func(t types.WhereTarget) {
+			whereKey(t, pk, key)
+		})
+	}
+}
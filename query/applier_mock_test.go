@@ -137,6 +137,21 @@ func (m *mockLimitOffset) SetLimit(v uint64)  { m.limit = v }
 func (m *mockLimitOffset) GetOffset() uint64  { return m.offset }
 func (m *mockLimitOffset) GetLimit() uint64   { return m.limit }
 
+type mockConflict struct {
+	sqlpart.Conflict
+	target     []types.Column
+	constraint string
+	nothing    bool
+	set        []types.Column
+	where      *mockWhere
+}
+
+func (m *mockConflict) OnColumns(cols ...types.Column) { m.target = cols }
+func (m *mockConflict) OnConstraint(name string)       { m.constraint = name }
+func (m *mockConflict) DoNothing()                     { m.nothing = true }
+func (m *mockConflict) DoUpdate(cols ...types.Column)  { m.set = cols }
+func (m *mockConflict) Where() sqlpart.Where           { return m.where }
+
 // --- Applier — implements every <Op>Applier interface of the query package --
 
 type mockApplier struct {
@@ -148,7 +163,10 @@ type mockApplier struct {
 	group        *mockGroup
 	join         *mockJoin
 	limit        *mockLimitOffset
+	conflict     *mockConflict
 	returningSet []types.Column // captured by SetReturning, asserted by tests
+	assignments  []string       // captured by AppendAssignment as "name = expr"
+	assignArgs   []any
 }
 
 func newMockApplier() *mockApplier {
@@ -166,6 +184,9 @@ func newMockApplier() *mockApplier {
 		group:   &mockGroup{},
 		join:    &mockJoin{},
 		limit:   &mockLimitOffset{},
+		conflict: &mockConflict{
+			where: &mockWhere{},
+		},
 	}
 }
 
@@ -178,3 +199,10 @@ func (m *mockApplier) Group() sqlpart.Group                 { return m.group }
 func (m *mockApplier) Join() sqlpart.Join                   { return m.join }
 func (m *mockApplier) LimitOffset() sqlpart.LimitOffset     { return m.limit }
 func (m *mockApplier) SetReturning(cols []types.Column)     { m.returningSet = cols }
+func (m *mockApplier) Conflict() sqlpart.Conflict           { return m.conflict }
+
+func (m *mockApplier) AppendAssignment(col types.Column, expr string, args ...any) {
+	name, _ := col.Name()
+	m.assignments = append(m.assignments, name+" = "+expr)
+	m.assignArgs = append(m.assignArgs, args...)
+}
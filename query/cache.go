@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/insei/gerpo/executor/cache"
+)
+
+// cacheControl implements Cacheable for the read helpers.
+type cacheControl struct {
+	control cache.Control
+	set     bool
+}
+
+func (c *cacheControl) NoCache() {
+	c.control.NoCache = true
+	c.set = true
+}
+
+func (c *cacheControl) CacheTTL(ttl time.Duration) {
+	if ttl > 0 {
+		c.control.TTL = ttl
+		c.set = true
+	}
+}
+
+func (c *cacheControl) CacheKey(tag string) {
+	if tag != "" {
+		c.control.Tags = append(c.control.Tags, tag)
+		c.set = true
+	}
+}
+
+// CacheContext returns ctx carrying the cache control of the request for the
+// storage, ctx itself when the request set none.
+func (c *cacheControl) CacheContext(ctx context.Context) context.Context {
+	if !c.set {
+		return ctx
+	}
+	return cache.WithControl(ctx, c.control)
+}
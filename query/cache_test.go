@@ -0,0 +1,30 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/insei/gerpo/executor/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl_CacheContext(t *testing.T) {
+	ctx := context.Background()
+
+	h := NewGetList(&listModel{})
+	assert.Equal(t, ctx, h.CacheContext(ctx), "no call keeps the context")
+
+	h.CacheTTL(0)
+	h.CacheKey("")
+	assert.Equal(t, ctx, h.CacheContext(ctx), "empty values are ignored")
+
+	h.HandleFn(func(m *listModel, h GetListHelper[listModel]) {
+		h.NoCache()
+		h.CacheTTL(time.Minute)
+		h.CacheKey("a")
+		h.CacheKey("b")
+	})
+	assert.Equal(t, cache.Control{NoCache: true, TTL: time.Minute, Tags: []string{"a", "b"}},
+		cache.ControlFromContext(h.CacheContext(ctx)))
+}
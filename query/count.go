@@ -8,10 +8,11 @@ import (
 	"github.com/insei/gerpo/types"
 )
 
-// CountHelper is the per-request helper for repo.Count. It only filters —
-// see interfaces.go for the Filterable contract.
+// CountHelper is the per-request helper for repo.Count. It filters and steers
+// the cache — see interfaces.go for the Filterable and Cacheable contracts.
 type CountHelper[TModel any] interface {
 	Filterable
+	Cacheable
 }
 
 type CountApplier interface {
@@ -23,6 +24,8 @@ type Count[TModel any] struct {
 	baseModel any
 
 	whereBuilder *linq.WhereBuilder
+
+	cacheControl
 }
 
 func (h *Count[TModel]) Where() types.WhereTarget {
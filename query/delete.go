@@ -9,10 +9,12 @@ import (
 	"github.com/insei/gerpo/types"
 )
 
-// DeleteHelper is the per-request helper for repo.Delete. It only filters —
-// see interfaces.go for the Filterable contract.
+// DeleteHelper is the per-request helper for repo.Delete. It filters and can
+// pin the expected row version — see interfaces.go for the Filterable and
+// Versioned contracts.
 type DeleteHelper[TModel any] interface {
 	Filterable
+	Versioned
 }
 
 type DeleteApplier interface {
@@ -25,14 +27,25 @@ type DeleteApplier interface {
 type Delete[TModel any] struct {
 	baseModel *TModel
 
-	whereBuilder *linq.WhereBuilder
-	joinBuilder  *linq.JoinBuilder
+	whereBuilder   *linq.WhereBuilder
+	joinBuilder    *linq.JoinBuilder
+	versionBuilder *linq.VersionBuilder
 }
 
 func (h *Delete[TModel]) Where() types.WhereTarget {
 	return h.whereBuilder
 }
 
+func (h *Delete[TModel]) ExpectVersion(version any) {
+	h.versionBuilder.ExpectVersion(version)
+}
+
+// ExpectsVersion reports whether the request pinned the row version through
+// ExpectVersion.
+func (h *Delete[TModel]) ExpectsVersion() bool {
+	return h.versionBuilder.IsSet()
+}
+
 func (h *Delete[TModel]) Apply(applier DeleteApplier) error {
 	err := h.whereBuilder.Apply(applier)
 	if err != nil {
@@ -42,6 +55,9 @@ func (h *Delete[TModel]) Apply(applier DeleteApplier) error {
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrApplyJoinClause, err)
 	}
+	if err = h.versionBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyVersionCondition, err)
+	}
 	return nil
 }
 
@@ -53,8 +69,9 @@ func (h *Delete[TModel]) HandleFn(qFns ...func(m *TModel, h DeleteHelper[TModel]
 
 func NewDelete[TModel any](baseModel *TModel) *Delete[TModel] {
 	return &Delete[TModel]{
-		whereBuilder: linq.NewWhereBuilder(baseModel),
-		joinBuilder:  linq.NewJoinBuilder(),
-		baseModel:    baseModel,
+		whereBuilder:   linq.NewWhereBuilder(baseModel),
+		joinBuilder:    linq.NewJoinBuilder(),
+		versionBuilder: linq.NewVersionBuilder(),
+		baseModel:      baseModel,
 	}
 }
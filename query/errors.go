@@ -1,6 +1,10 @@
 package query
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/insei/gerpo/query/linq"
+)
 
 var (
 	ErrApplyWhereClause         = fmt.Errorf("failed to apply WHERE clause")
@@ -10,4 +14,13 @@ var (
 	ErrApplyGroupByClause       = fmt.Errorf("failed to apply GROUP BY operator")
 	ErrApplyExcludeColumnRules  = fmt.Errorf("failed to apply exclude column rules")
 	ErrApplyReturningClause     = fmt.Errorf("failed to apply RETURNING clause")
+	ErrApplyConflictClause      = fmt.Errorf("failed to apply ON CONFLICT clause")
+	ErrApplyKeysetPagination    = fmt.Errorf("failed to apply keyset pagination")
+	ErrApplySetClause           = fmt.Errorf("failed to apply SET clause")
+	ErrApplyVersionCondition    = fmt.Errorf("failed to apply version condition")
+	ErrApplyUpdateKey           = fmt.Errorf("failed to apply UPDATE key")
+
+	// ErrInvalidCursor reports a keyset cursor that cannot be decoded or does
+	// not fit the request's ORDER BY.
+	ErrInvalidCursor = linq.ErrInvalidCursor
 )
@@ -9,12 +9,13 @@ import (
 )
 
 // GetFirstHelper is the per-request helper for repo.GetFirst. It composes the
-// small contracts from interfaces.go: filtering, sorting and narrowing the
-// column set.
+// small contracts from interfaces.go: filtering, sorting, narrowing the
+// column set and steering the cache.
 type GetFirstHelper[TModel any] interface {
 	Filterable
 	Sortable
 	Excludable
+	Cacheable
 }
 
 // GetFirstApplier defines an interface for applying columns, filters, and ordering in a query construction process.
@@ -32,6 +33,8 @@ type GetFirst[TModel any] struct {
 	whereBuilder   *linq.WhereBuilder
 	orderBuilder   *linq.OrderBuilder
 	excludeBuilder *linq.ExcludeBuilder
+
+	cacheControl
 }
 
 func (h *GetFirst[TModel]) Exclude(fieldPointers ...any) {
@@ -4,21 +4,25 @@ import (
 	"fmt"
 
 	"github.com/insei/gerpo/query/linq"
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 )
 
-// InsertHelper is the per-request helper for repo.Insert. It narrows the
-// column set (Excludable) and lets the caller override the RETURNING clause
-// (Returnable) — see interfaces.go for the small contracts.
+// InsertHelper is the per-request helper for repo.Insert and repo.Upsert. It
+// narrows the column set (Excludable), lets the caller override the RETURNING
+// clause (Returnable) and attaches an ON CONFLICT spec (Conflictable) — see
+// interfaces.go for the small contracts.
 type InsertHelper[TModel any] interface {
 	Excludable
 	Returnable
+	Conflictable
 }
 
 type InsertApplier interface {
 	ColumnsStorage() types.ColumnsStorage
 	Columns() types.ExecutionColumns
 	SetReturning(cols []types.Column)
+	Conflict() sqlpart.Conflict
 }
 
 type Insert[TModel any] struct {
@@ -26,6 +30,7 @@ type Insert[TModel any] struct {
 
 	excludeBuilder   *linq.ExcludeBuilder
 	returningBuilder *linq.ReturningBuilder
+	conflictBuilder  *linq.ConflictBuilder
 }
 
 func (h *Insert[TModel]) Exclude(fieldsPtr ...any) {
@@ -40,6 +45,14 @@ func (h *Insert[TModel]) Returning(fieldsPtr ...any) {
 	h.returningBuilder.Returning(fieldsPtr...)
 }
 
+func (h *Insert[TModel]) OnConflict(fieldsPtr ...any) types.ConflictAction {
+	return h.conflictBuilder.OnConflict(fieldsPtr...)
+}
+
+func (h *Insert[TModel]) OnConflictConstraint(name string) types.ConflictAction {
+	return h.conflictBuilder.OnConflictConstraint(name)
+}
+
 func (h *Insert[TModel]) Apply(applier InsertApplier) error {
 	err := h.excludeBuilder.Apply(applier)
 	if err != nil {
@@ -48,6 +61,9 @@ func (h *Insert[TModel]) Apply(applier InsertApplier) error {
 	if err := h.returningBuilder.Apply(applier); err != nil {
 		return fmt.Errorf("%w: %w", ErrApplyReturningClause, err)
 	}
+	if err := h.conflictBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyConflictClause, err)
+	}
 	return nil
 }
 
@@ -63,5 +79,6 @@ func NewInsert[TModel any](baseModel *TModel) *Insert[TModel] {
 
 		excludeBuilder:   linq.NewExcludeBuilder(baseModel),
 		returningBuilder: linq.NewReturningBuilder(baseModel),
+		conflictBuilder:  linq.NewConflictBuilder(baseModel),
 	}
 }
@@ -4,22 +4,26 @@ import (
 	"fmt"
 
 	"github.com/insei/gerpo/query/linq"
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 )
 
 // InsertManyHelper is the per-request helper for repo.InsertMany. It mirrors
 // the single-row InsertHelper: Excludable narrows the column set, Returnable
-// overrides the RETURNING clause. The RETURNING set is identical for every row
-// in the batch — there is no per-row override.
+// overrides the RETURNING clause, Conflictable turns the batch into an upsert.
+// The RETURNING set and the ON CONFLICT spec are identical for every row in
+// the batch — there is no per-row override.
 type InsertManyHelper[TModel any] interface {
 	Excludable
 	Returnable
+	Conflictable
 }
 
 type InsertManyApplier interface {
 	ColumnsStorage() types.ColumnsStorage
 	Columns() types.ExecutionColumns
 	SetReturning(cols []types.Column)
+	Conflict() sqlpart.Conflict
 }
 
 type InsertMany[TModel any] struct {
@@ -27,6 +31,7 @@ type InsertMany[TModel any] struct {
 
 	excludeBuilder   *linq.ExcludeBuilder
 	returningBuilder *linq.ReturningBuilder
+	conflictBuilder  *linq.ConflictBuilder
 }
 
 func (h *InsertMany[TModel]) Exclude(fieldsPtr ...any) {
@@ -41,6 +46,14 @@ func (h *InsertMany[TModel]) Returning(fieldsPtr ...any) {
 	h.returningBuilder.Returning(fieldsPtr...)
 }
 
+func (h *InsertMany[TModel]) OnConflict(fieldsPtr ...any) types.ConflictAction {
+	return h.conflictBuilder.OnConflict(fieldsPtr...)
+}
+
+func (h *InsertMany[TModel]) OnConflictConstraint(name string) types.ConflictAction {
+	return h.conflictBuilder.OnConflictConstraint(name)
+}
+
 func (h *InsertMany[TModel]) Apply(applier InsertManyApplier) error {
 	if err := h.excludeBuilder.Apply(applier); err != nil {
 		return fmt.Errorf("%w: %w", ErrApplyExcludeColumnRules, err)
@@ -48,6 +61,9 @@ func (h *InsertMany[TModel]) Apply(applier InsertManyApplier) error {
 	if err := h.returningBuilder.Apply(applier); err != nil {
 		return fmt.Errorf("%w: %w", ErrApplyReturningClause, err)
 	}
+	if err := h.conflictBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyConflictClause, err)
+	}
 	return nil
 }
 
@@ -63,5 +79,6 @@ func NewInsertMany[TModel any](baseModel *TModel) *InsertMany[TModel] {
 
 		excludeBuilder:   linq.NewExcludeBuilder(baseModel),
 		returningBuilder: linq.NewReturningBuilder(baseModel),
+		conflictBuilder:  linq.NewConflictBuilder(baseModel),
 	}
 }
@@ -44,3 +44,86 @@ func TestInsert_Apply_ExcludeMissing_Err(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, ErrApplyExcludeColumnRules))
 }
+
+func TestInsert_Apply_OnConflictDoNothing(t *testing.T) {
+	m := &insertModel{}
+	idCol := &mockColumn{name: "id", hasName: true, allowed: true}
+	a := newMockApplier()
+	a.storage.byPtr[&m.ID] = idCol
+
+	h := NewInsert(m)
+	h.HandleFn(func(m *insertModel, h InsertHelper[insertModel]) {
+		h.OnConflict(&m.ID).DoNothing()
+	})
+	require.NoError(t, h.Apply(a))
+	assert.Equal(t, []types.Column{idCol}, a.conflict.target)
+	assert.True(t, a.conflict.nothing)
+}
+
+func TestInsert_Apply_OnConflictDoUpdate_DefaultSet(t *testing.T) {
+	m := &insertModel{}
+	idCol := &mockColumn{name: "id", hasName: true, allowed: true}
+	nameCol := &mockColumn{name: "name", hasName: true, allowed: true}
+	a := newMockApplier()
+	a.storage.byPtr[&m.ID] = idCol
+	a.cols.all = []types.Column{idCol, nameCol}
+
+	h := NewInsert(m)
+	h.HandleFn(func(m *insertModel, h InsertHelper[insertModel]) {
+		h.OnConflict(&m.ID).DoUpdate()
+	})
+	require.NoError(t, h.Apply(a))
+	assert.Equal(t, []types.Column{nameCol}, a.conflict.set)
+}
+
+func TestInsert_Apply_OnConflictConstraint(t *testing.T) {
+	m := &insertModel{}
+	nameCol := &mockColumn{name: "name", hasName: true, allowed: true}
+	a := newMockApplier()
+	a.storage.byPtr[&m.Name] = nameCol
+	a.cols.all = []types.Column{nameCol}
+
+	h := NewInsert(m)
+	h.HandleFn(func(m *insertModel, h InsertHelper[insertModel]) {
+		h.OnConflictConstraint("users_name_key").DoUpdate(&m.Name)
+	})
+	require.NoError(t, h.Apply(a))
+	assert.Equal(t, "users_name_key", a.conflict.constraint)
+	assert.Equal(t, []types.Column{nameCol}, a.conflict.set)
+}
+
+func TestInsert_Apply_OnConflict_Errors(t *testing.T) {
+	m := &insertModel{}
+	tests := []struct {
+		name string
+		fn   func(m *insertModel, h InsertHelper[insertModel])
+	}{
+		{
+			name: "action is missing",
+			fn: func(m *insertModel, h InsertHelper[insertModel]) {
+				h.OnConflict(&m.ID)
+			},
+		},
+		{
+			name: "DoUpdate without target",
+			fn: func(m *insertModel, h InsertHelper[insertModel]) {
+				h.OnConflict().DoUpdate()
+			},
+		},
+		{
+			name: "target field is unknown",
+			fn: func(m *insertModel, h InsertHelper[insertModel]) {
+				h.OnConflict(&m.ID).DoNothing() // нет в storage
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewInsert(m)
+			h.HandleFn(tt.fn)
+			err := h.Apply(newMockApplier())
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrApplyConflictClause))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package query
 
-import "github.com/insei/gerpo/types"
+import (
+	"time"
+
+	"github.com/insei/gerpo/types"
+)
 
 // The interfaces in this file are small composable contracts that the
 // per-operation helpers (GetFirstHelper, GetListHelper, …) embed. They are
@@ -56,6 +60,49 @@ type Pageable[TModel any] interface {
 	Size(size uint64) GetListHelper[TModel]
 }
 
+// KeysetPageable describes keyset (cursor) pagination on a list helper — the
+// alternative to Page for deep listings, where OFFSET gets slow. The cursor
+// is derived from the request's OrderBy fields, so the ORDER BY must be the
+// same on every page and should end with a unique field (usually the primary
+// key) to keep ties from being skipped. Combine with Size; Page is rejected.
+//
+// Cursors are opaque URL-safe strings, fit to be handed out by REST APIs.
+// A malformed cursor fails the call with ErrInvalidCursor.
+type KeysetPageable[TModel any] interface {
+	// After continues the listing right after the row the cursor points at. An empty cursor starts from the first row.
+	After(cursor string) GetListHelper[TModel]
+	// NextCursor makes GetList write the cursor of the next page into dst — empty when the page came back short,
+	// i.e. there is nothing left to read.
+	NextCursor(dst *string) GetListHelper[TModel]
+}
+
+// Settable describes any helper that builds the SET clause of an UPDATE
+// explicitly instead of binding it from a model. Update satisfies it.
+//
+// Once any assignment is made, the SET clause consists of the assignments
+// only; the fields must be allowed on update (not OmitOnUpdate, not virtual).
+// Assigning the same field twice keeps the last assignment.
+type Settable interface {
+	// Set assigns value to the field: SET name = ?.
+	Set(fieldPtr any, value any)
+	// Increment adds n to the field's current value: SET name = name + ?. Pass a negative n to decrement.
+	Increment(fieldPtr any, n any)
+	// SetExpr assigns a raw SQL expression to the field: SET name = <sql>. The expression binds args through ?
+	// placeholders, e.g. SetExpr(&m.Tags, "array_append(tags, ?)", tag).
+	SetExpr(fieldPtr any, sql string, args ...any)
+}
+
+// Versioned describes any helper that can pin the optimistic-locking version
+// the row is expected to be at. Delete satisfies it.
+//
+// ExpectVersion needs a column marked Version() on the repository; it adds
+// `version = ?` to the WHERE clause, and a call that matches no row fails
+// with gerpo.ErrStaleVersion instead of ErrNotFound.
+type Versioned interface {
+	// ExpectVersion limits the write to the row still at the given version.
+	ExpectVersion(version any)
+}
+
 // Returnable describes any helper that exposes per-request control over the
 // RETURNING clause. Insert and Update satisfy it.
 //
@@ -68,3 +115,36 @@ type Returnable interface {
 	// calling with explicit fields replaces the repository's default returning set with exactly those columns.'
 	Returning(fieldsPtr ...any)
 }
+
+// Conflictable describes any helper that can turn an INSERT into an upsert by
+// attaching an ON CONFLICT clause. Insert satisfies it.
+//
+// Without a call the INSERT is plain and a conflicting row surfaces as the
+// driver's unique-violation error. OnConflict / OnConflictConstraint pick the
+// conflict target; the returned types.ConflictAction picks DoNothing or
+// DoUpdate.
+type Conflictable interface {
+	// OnConflict sets the conflict target to the columns behind fieldsPtr. Called with no fields the target is
+	// omitted, which is only valid together with DoNothing. RETURNING rows of InsertMany are paired with models by
+	// these columns, so they should come back exactly as sent — see docs/features/crud.md for folded keys.
+	OnConflict(fieldsPtr ...any) types.ConflictAction
+	// OnConflictConstraint sets the conflict target to a named unique or exclusion constraint.
+	OnConflictConstraint(name string) types.ConflictAction
+}
+
+// Cacheable describes any helper that steers the cache storage of the
+// repository for one read. GetFirst, GetList and Count satisfy it; Iterate
+// never reads the cache and ignores it.
+//
+// Without a call the read goes through the storage as configured on the
+// repository (executor.WithCacheStorage).
+type Cacheable interface {
+	// NoCache makes the read skip the cache: it neither reads a cached result nor caches its own.
+	NoCache()
+	// CacheTTL sets how long the result of the read stays cached, for the storages whose entries expire
+	// (executor/cache/lru, executor/cache/redis).
+	CacheTTL(ttl time.Duration)
+	// CacheKey tags the cached result of the read, for the storages invalidating entries per tag: their
+	// InvalidateTags(tag) drops it. Calling it again adds another tag.
+	CacheKey(tag string)
+}
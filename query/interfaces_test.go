@@ -7,15 +7,18 @@ var (
 	_ Filterable          = (*GetFirst[any])(nil)
 	_ Sortable            = (*GetFirst[any])(nil)
 	_ Excludable          = (*GetFirst[any])(nil)
+	_ Cacheable           = (*GetFirst[any])(nil)
 	_ GetFirstHelper[any] = (*GetFirst[any])(nil)
 
 	_ Filterable         = (*GetList[any])(nil)
 	_ Sortable           = (*GetList[any])(nil)
 	_ Excludable         = (*GetList[any])(nil)
 	_ Pageable[any]      = (*GetList[any])(nil)
+	_ Cacheable          = (*GetList[any])(nil)
 	_ GetListHelper[any] = (*GetList[any])(nil)
 
 	_ Filterable       = (*Count[any])(nil)
+	_ Cacheable        = (*Count[any])(nil)
 	_ CountHelper[any] = (*Count[any])(nil)
 
 	_ Excludable        = (*Insert[any])(nil)
@@ -25,6 +28,11 @@ var (
 	_ Excludable        = (*Update[any])(nil)
 	_ UpdateHelper[any] = (*Update[any])(nil)
 
+	_ Filterable            = (*UpdateMany[any])(nil)
+	_ Excludable            = (*UpdateMany[any])(nil)
+	_ Returnable            = (*UpdateMany[any])(nil)
+	_ UpdateManyHelper[any] = (*UpdateMany[any])(nil)
+
 	_ Filterable        = (*Delete[any])(nil)
 	_ DeleteHelper[any] = (*Delete[any])(nil)
 )
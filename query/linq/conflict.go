@@ -0,0 +1,175 @@
+package linq
+
+import (
+	"fmt"
+
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
+	"github.com/insei/gerpo/types"
+)
+
+// ConflictApplier is the slice of stmt API the ConflictBuilder needs: resolve
+// fields through ColumnsStorage, read the final INSERT column set and write
+// the ON CONFLICT clause.
+type ConflictApplier interface {
+	ColumnsStorage() types.ColumnsStorage
+	Columns() types.ExecutionColumns
+	Conflict() sqlpart.Conflict
+}
+
+type conflictActionKind uint8
+
+const (
+	conflictActionUnset conflictActionKind = iota
+	conflictActionNothing
+	conflictActionUpdate
+)
+
+// ConflictBuilder collects the ON CONFLICT spec of an INSERT. Nothing is
+// rendered until OnConflict / OnConflictConstraint is called; the last call
+// wins when the spec is configured more than once.
+type ConflictBuilder struct {
+	model      any
+	enabled    bool
+	targetPtrs []any
+	constraint string
+	action     conflictActionKind
+	updatePtrs []any
+	where      *WhereBuilder
+}
+
+func NewConflictBuilder(model any) *ConflictBuilder {
+	return &ConflictBuilder{
+		model: model,
+		where: NewWhereBuilder(model),
+	}
+}
+
+// OnConflict sets the conflict target to the columns behind fieldsPtr. With
+// no fields the target is left out, which is only valid with DoNothing.
+func (b *ConflictBuilder) OnConflict(fieldsPtr ...any) types.ConflictAction {
+	b.enabled = true
+	b.constraint = ""
+	b.targetPtrs = append(b.targetPtrs[:0], fieldsPtr...)
+	return b
+}
+
+// OnConflictConstraint sets the conflict target to a named unique or
+// exclusion constraint.
+func (b *ConflictBuilder) OnConflictConstraint(name string) types.ConflictAction {
+	b.enabled = true
+	b.constraint = name
+	b.targetPtrs = b.targetPtrs[:0]
+	return b
+}
+
+func (b *ConflictBuilder) DoNothing() {
+	b.action = conflictActionNothing
+	b.updatePtrs = b.updatePtrs[:0]
+}
+
+func (b *ConflictBuilder) DoUpdate(fieldsPtr ...any) types.ConflictUpdate {
+	b.action = conflictActionUpdate
+	b.updatePtrs = append(b.updatePtrs[:0], fieldsPtr...)
+	return b
+}
+
+func (b *ConflictBuilder) Where() types.WhereTarget {
+	return b.where
+}
+
+// IsSet reports whether the caller configured an ON CONFLICT spec.
+func (b *ConflictBuilder) IsSet() bool {
+	return b.enabled
+}
+
+func (b *ConflictBuilder) Apply(applier ConflictApplier) error {
+	if !b.enabled {
+		return nil
+	}
+	if b.action == conflictActionUnset {
+		return fmt.Errorf("on conflict: DoNothing or DoUpdate is required")
+	}
+	storage := applier.ColumnsStorage()
+	target := make([]types.Column, 0, len(b.targetPtrs))
+	for _, ptr := range b.targetPtrs {
+		col, err := storage.GetByFieldPtr(b.model, ptr)
+		if err != nil {
+			return err
+		}
+		target = append(target, col)
+	}
+	c := applier.Conflict()
+	if b.constraint != "" {
+		c.OnConstraint(b.constraint)
+	} else {
+		c.OnColumns(target...)
+	}
+	if b.action == conflictActionNothing {
+		c.DoNothing()
+		return nil
+	}
+	if b.constraint == "" && len(target) == 0 {
+		return fmt.Errorf("on conflict: DoUpdate requires a conflict target")
+	}
+
+	inserted := applier.Columns().GetAll()
+	var set []types.Column
+	if len(b.updatePtrs) == 0 {
+		set = defaultConflictUpdateSet(inserted, target)
+	} else {
+		set = make([]types.Column, 0, len(b.updatePtrs))
+		for _, ptr := range b.updatePtrs {
+			col, err := storage.GetByFieldPtr(b.model, ptr)
+			if err != nil {
+				return err
+			}
+			if !col.IsAllowedAction(types.SQLActionUpdate) {
+				return fmt.Errorf("on conflict: field %s is not allowed on update", col.GetField().GetStructPath())
+			}
+			if !containsColumn(inserted, col) {
+				return fmt.Errorf("on conflict: field %s is not part of the inserted columns", col.GetField().GetStructPath())
+			}
+			set = append(set, col)
+		}
+	}
+	if len(set) == 0 {
+		return fmt.Errorf("on conflict: DoUpdate has no columns to update")
+	}
+	c.DoUpdate(set...)
+	return b.where.Apply(&conflictWhereApplier{storage: storage, where: c.Where()})
+}
+
+// defaultConflictUpdateSet is what DoUpdate() without fields overwrites: every
+// inserted column that may be updated, minus the conflict target itself.
+func defaultConflictUpdateSet(inserted, target []types.Column) []types.Column {
+	set := make([]types.Column, 0, len(inserted))
+	for _, col := range inserted {
+		if _, ok := col.Name(); !ok {
+			continue
+		}
+		if !col.IsAllowedAction(types.SQLActionUpdate) || containsColumn(target, col) {
+			continue
+		}
+		set = append(set, col)
+	}
+	return set
+}
+
+func containsColumn(cols []types.Column, col types.Column) bool {
+	for _, c := range cols {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictWhereApplier points the regular WhereBuilder at the DO UPDATE
+// condition buffer instead of the statement-level WHERE.
+type conflictWhereApplier struct {
+	storage types.ColumnsStorage
+	where   sqlpart.Where
+}
+
+func (a *conflictWhereApplier) Where() sqlpart.Where                 { return a.where }
+func (a *conflictWhereApplier) ColumnsStorage() types.ColumnsStorage { return a.storage }
@@ -3,6 +3,7 @@ package linq
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/insei/gerpo/sqlstmt/sqlpart"
 )
@@ -104,6 +105,18 @@ func (q *JoinBuilder) InnerJoinOn(table, on string, resolver ...JoinArgsResolver
 	})
 }
 
+// Tables returns the tables of the registered JOINs in registration order,
+// without the alias of a `posts AS p` reference.
+func (q *JoinBuilder) Tables() []string {
+	tables := make([]string, 0, len(q.entries))
+	for _, e := range q.entries {
+		if fields := strings.Fields(e.table); len(fields) > 0 {
+			tables = append(tables, fields[0])
+		}
+	}
+	return tables
+}
+
 func pickResolver(method string, resolver []JoinArgsResolver) JoinArgsResolver {
 	switch len(resolver) {
 	case 0:
@@ -105,3 +105,12 @@ func TestJoinBuilder_MultipleResolvers_Panic(t *testing.T) {
 		"gerpo: InnerJoinOn accepts at most one resolver, got 2",
 		func() { builder.InnerJoinOn("posts", "on", r, r) })
 }
+
+func TestJoinBuilder_Tables(t *testing.T) {
+	builder := NewJoinBuilder()
+	assert.Empty(t, builder.Tables())
+
+	builder.LeftJoinOn("posts AS p", "p.user_id = users.id")
+	builder.InnerJoinOn(" app.tenants ", "app.tenants.id = users.tenant_id")
+	assert.Equal(t, []string{"posts", "app.tenants"}, builder.Tables())
+}
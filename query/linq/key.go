@@ -0,0 +1,46 @@
+package linq
+
+import (
+	"github.com/insei/gerpo/types"
+)
+
+// KeyBuilder collects the columns a batched UPDATE matches rows on. Every
+// model of the batch is written to the row whose key columns hold the same
+// values as the model's key fields.
+type KeyBuilder struct {
+	model     any
+	fieldPtrs []any
+}
+
+// KeyApplier is the slice of stmt API the KeyBuilder pokes at: resolve fields
+// through ColumnsStorage and install the key on the stmt.
+type KeyApplier interface {
+	ColumnsStorage() types.ColumnsStorage
+	SetKey(cols []types.Column)
+}
+
+func NewKeyBuilder(model any) *KeyBuilder {
+	return &KeyBuilder{model: model}
+}
+
+// Key sets the key fields. A later call replaces the earlier one.
+func (b *KeyBuilder) Key(fieldsPtr ...any) {
+	b.fieldPtrs = append(b.fieldPtrs[:0], fieldsPtr...)
+}
+
+func (b *KeyBuilder) Apply(applier KeyApplier) error {
+	if len(b.fieldPtrs) == 0 {
+		return nil
+	}
+	cols := make([]types.Column, 0, len(b.fieldPtrs))
+	storage := applier.ColumnsStorage()
+	for _, ptr := range b.fieldPtrs {
+		col, err := storage.GetByFieldPtr(b.model, ptr)
+		if err != nil {
+			return err
+		}
+		cols = append(cols, col)
+	}
+	applier.SetKey(cols)
+	return nil
+}
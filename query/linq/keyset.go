@@ -0,0 +1,167 @@
+package linq
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
+	"github.com/insei/gerpo/types"
+)
+
+// ErrInvalidCursor is returned when a keyset cursor cannot be decoded or does
+// not match the ORDER BY of the request it is passed to.
+var ErrInvalidCursor = fmt.Errorf("invalid keyset cursor")
+
+type KeysetApplier interface {
+	ColumnsStorage() types.ColumnsStorage
+	Columns() types.ExecutionColumns
+	Where() sqlpart.Where
+}
+
+// KeysetBuilder implements keyset (cursor) pagination on top of the ORDER BY
+// collected by an OrderBuilder. The cursor is the list of ORDER BY values of
+// the last row of a page, JSON-encoded and wrapped in URL-safe base64 so it
+// can travel through query strings as-is.
+type KeysetBuilder struct {
+	model any
+	order *OrderBuilder
+
+	after string
+	next  *string
+
+	// resolved by Apply, used to encode the next cursor.
+	columns []types.Column
+}
+
+func NewKeysetBuilder(baseModel any, order *OrderBuilder) *KeysetBuilder {
+	return &KeysetBuilder{
+		model: baseModel,
+		order: order,
+	}
+}
+
+// After sets the cursor the listing continues from. An empty cursor starts
+// from the first row.
+func (q *KeysetBuilder) After(cursor string) {
+	q.after = cursor
+}
+
+// NextCursor registers dst as the destination of the next page cursor.
+func (q *KeysetBuilder) NextCursor(dst *string) {
+	q.next = dst
+}
+
+// HasCursor reports whether After was given a non-empty cursor.
+func (q *KeysetBuilder) HasCursor() bool {
+	return q.after != ""
+}
+
+// IsActive reports whether the request uses keyset pagination.
+func (q *KeysetBuilder) IsActive() bool {
+	return q.after != "" || q.next != nil
+}
+
+func (q *KeysetBuilder) Apply(applier KeysetApplier) error {
+	if !q.IsActive() {
+		return nil
+	}
+	columns, directions, err := q.orderColumns(applier.ColumnsStorage())
+	if err != nil {
+		return err
+	}
+	for _, col := range columns {
+		if !col.IsAllowedAction(types.SQLActionSort) {
+			return fmt.Errorf("keyset pagination: field %q is not sortable", col.GetField().GetStructPath())
+		}
+		if col.IsAggregate() {
+			return fmt.Errorf("keyset pagination: aggregate column %q cannot be used as a cursor key", col.GetField().GetStructPath())
+		}
+		if _, err := applier.Columns().GetByFieldPtr(q.model, col.GetPtr(q.model)); err != nil {
+			return fmt.Errorf("keyset pagination: ordered field %q must be selected: %w", col.GetField().GetStructPath(), err)
+		}
+	}
+	q.columns = columns
+	if q.after == "" {
+		return nil
+	}
+	values, err := decodeCursor(q.after, columns, q.model)
+	if err != nil {
+		return err
+	}
+	return applier.Where().AppendKeysetCondition(columns, directions, values)
+}
+
+// orderColumns resolves the ORDER BY entries to columns. Keyset pagination
+// needs at least one, and the last one should be unique (usually the primary
+// key) so that rows with equal sort values are not skipped between pages.
+func (q *KeysetBuilder) orderColumns(storage types.ColumnsStorage) ([]types.Column, []types.OrderDirection, error) {
+	if q.order == nil || len(q.order.ops) == 0 {
+		return nil, nil, fmt.Errorf("keyset pagination requires OrderBy")
+	}
+	columns := make([]types.Column, 0, len(q.order.ops))
+	directions := make([]types.OrderDirection, 0, len(q.order.ops))
+	for i := range q.order.ops {
+		op := &q.order.ops[i]
+		column := op.column
+		if op.kind == orderKindField {
+			var err error
+			column, err = storage.GetByFieldPtr(q.order.model, op.fieldPtr)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if column == nil {
+			return nil, nil, fmt.Errorf("column is nil")
+		}
+		columns = append(columns, column)
+		directions = append(directions, op.direction)
+	}
+	return columns, directions, nil
+}
+
+// WriteNext stores the cursor pointing after last into the destination
+// registered with NextCursor. A nil last clears it — there is no next page.
+func (q *KeysetBuilder) WriteNext(last any) error {
+	if q.next == nil {
+		return nil
+	}
+	if last == nil || len(q.columns) == 0 {
+		*q.next = ""
+		return nil
+	}
+	values := make([]any, len(q.columns))
+	for i, col := range q.columns {
+		values[i] = col.GetPtr(last)
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("keyset pagination: encode cursor: %w", err)
+	}
+	*q.next = base64.RawURLEncoding.EncodeToString(raw)
+	return nil
+}
+
+func decodeCursor(cursor string, columns []types.Column, model any) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	var items []json.RawMessage
+	if err = json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(items) != len(columns) {
+		return nil, fmt.Errorf("%w: got %d values for %d ORDER BY columns", ErrInvalidCursor, len(items), len(columns))
+	}
+	values := make([]any, len(columns))
+	for i, col := range columns {
+		ptr := reflect.New(reflect.TypeOf(col.GetPtr(model)).Elem())
+		if err = json.Unmarshal(items[i], ptr.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCursor, col.GetField().GetStructPath(), err)
+		}
+		values[i] = ptr.Elem().Interface()
+	}
+	return values, nil
+}
@@ -30,6 +30,16 @@ func (q *PaginationBuilder) Size(size uint64) {
 	q.size = size
 }
 
+// GetPage returns the page number set by Page; zero when unset.
+func (q *PaginationBuilder) GetPage() uint64 {
+	return q.page
+}
+
+// GetSize returns the page size set by Size; zero when unset.
+func (q *PaginationBuilder) GetSize() uint64 {
+	return q.size
+}
+
 func (q *PaginationBuilder) Apply(applier PaginationApplier) error {
 	applier.LimitOffset().SetLimit(q.size)
 	if q.page != 0 && q.size == 0 {
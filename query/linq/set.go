@@ -0,0 +1,73 @@
+package linq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/types"
+)
+
+// SetBuilder collects explicit SET assignments of an UPDATE — values and SQL
+// expressions that do not come from a model. The fields are resolved and
+// checked against the columns' update permission on Apply.
+type SetBuilder struct {
+	model any
+	ops   []setOp
+}
+
+type setOp struct {
+	fieldPtr any
+	// expr renders the right-hand side for the resolved column name.
+	expr func(name string) string
+	args []any
+}
+
+// SetApplier is the slice of stmt API the SetBuilder pokes at: resolve fields
+// through ColumnsStorage and append the assignments to the SET clause.
+type SetApplier interface {
+	Ctx() context.Context
+	ColumnsStorage() types.ColumnsStorage
+	AppendAssignment(col types.Column, expr string, args ...any)
+}
+
+func NewSetBuilder(model any) *SetBuilder {
+	return &SetBuilder{model: model}
+}
+
+// Set assigns value to the field: `name = ?`.
+func (b *SetBuilder) Set(fieldPtr any, value any) {
+	b.ops = append(b.ops, setOp{fieldPtr: fieldPtr, expr: func(string) string { return "?" }, args: []any{value}})
+}
+
+// Increment adds n to the field's current value: `name = name + ?`.
+func (b *SetBuilder) Increment(fieldPtr any, n any) {
+	b.ops = append(b.ops, setOp{fieldPtr: fieldPtr, expr: func(name string) string { return name + " + ?" }, args: []any{n}})
+}
+
+// SetExpr assigns a raw SQL expression to the field: `name = <sql>`. The
+// expression binds args through ? placeholders.
+func (b *SetBuilder) SetExpr(fieldPtr any, sql string, args ...any) {
+	b.ops = append(b.ops, setOp{fieldPtr: fieldPtr, expr: func(string) string { return sql }, args: args})
+}
+
+// IsSet reports whether any assignment was collected.
+func (b *SetBuilder) IsSet() bool {
+	return len(b.ops) > 0
+}
+
+func (b *SetBuilder) Apply(applier SetApplier) error {
+	storage := applier.ColumnsStorage()
+	for _, op := range b.ops {
+		col, err := storage.GetByFieldPtr(b.model, op.fieldPtr)
+		if err != nil {
+			return err
+		}
+		name, ok := col.Name()
+		if !ok || !col.IsAllowedAction(types.SQLActionUpdate) {
+			return fmt.Errorf("set: field %s is not allowed on update", col.GetField().GetStructPath())
+		}
+		applier.AppendAssignment(col, op.expr(dialect.Ident(dialect.FromContext(applier.Ctx()), name)), op.args...)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package linq
+
+import (
+	"fmt"
+
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
+	"github.com/insei/gerpo/types"
+)
+
+// VersionBuilder pins the optimistic-locking version a write expects the row
+// to be at: on Apply it adds `version = ?` on the table's version column as a
+// WHERE group of its own.
+type VersionBuilder struct {
+	expected any
+	isSet    bool
+}
+
+// VersionApplier is the slice of stmt API the VersionBuilder pokes at: find
+// the version column and append the condition.
+type VersionApplier interface {
+	ColumnsStorage() types.ColumnsStorage
+	Where() sqlpart.Where
+}
+
+func NewVersionBuilder() *VersionBuilder {
+	return &VersionBuilder{}
+}
+
+// ExpectVersion sets the version the row is expected to be at.
+func (b *VersionBuilder) ExpectVersion(version any) {
+	b.expected = version
+	b.isSet = true
+}
+
+// IsSet reports whether ExpectVersion was called.
+func (b *VersionBuilder) IsSet() bool {
+	return b.isSet
+}
+
+func (b *VersionBuilder) Apply(applier VersionApplier) error {
+	if !b.isSet {
+		return nil
+	}
+	col, ok := VersionColumn(applier.ColumnsStorage())
+	if !ok {
+		return fmt.Errorf("expect version: no column is marked as Version()")
+	}
+	where := applier.Where()
+	where.StartGroup()
+	if err := where.AppendCondition(col, types.OperationEQ, b.expected); err != nil {
+		return err
+	}
+	where.EndGroup()
+	return nil
+}
+
+// VersionColumn returns the column marked as the optimistic-locking version
+// counter, if any.
+func VersionColumn(storage types.ColumnsStorage) (types.Column, bool) {
+	for _, col := range storage.AsSlice() {
+		if col.IsVersion() {
+			return col, true
+		}
+	}
+	return nil, false
+}
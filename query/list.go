@@ -10,12 +10,15 @@ import (
 
 // GetListHelper is the per-request helper for repo.GetList. It composes
 // the small contracts from interfaces.go: filtering, sorting, narrowing the
-// column set, and pagination.
+// column set, pagination — by page or by keyset cursor — and steering the
+// cache.
 type GetListHelper[TModel any] interface {
 	Filterable
 	Sortable
 	Excludable
 	Pageable[TModel]
+	KeysetPageable[TModel]
+	Cacheable
 }
 
 type GetListApplier interface {
@@ -33,6 +36,9 @@ type GetList[TModel any] struct {
 	orderBuilder      *linq.OrderBuilder
 	excludeBuilder    *linq.ExcludeBuilder
 	paginationBuilder *linq.PaginationBuilder
+	keysetBuilder     *linq.KeysetBuilder
+
+	cacheControl
 }
 
 func (h *GetList[TModel]) Exclude(fieldPointers ...any) {
@@ -61,6 +67,16 @@ func (h *GetList[TModel]) Size(size uint64) GetListHelper[TModel] {
 	return h
 }
 
+func (h *GetList[TModel]) After(cursor string) GetListHelper[TModel] {
+	h.keysetBuilder.After(cursor)
+	return h
+}
+
+func (h *GetList[TModel]) NextCursor(dst *string) GetListHelper[TModel] {
+	h.keysetBuilder.NextCursor(dst)
+	return h
+}
+
 func (h *GetList[TModel]) Apply(applier GetListApplier) error {
 	err := h.excludeBuilder.Apply(applier)
 	if err != nil {
@@ -78,9 +94,31 @@ func (h *GetList[TModel]) Apply(applier GetListApplier) error {
 	if err != nil {
 		return fmt.Errorf("%w:%w", ErrApplyLimitOffsetOperator, err)
 	}
+	if h.keysetBuilder.IsActive() && h.paginationBuilder.GetPage() != 0 {
+		return fmt.Errorf("%w: Page cannot be combined with a keyset cursor", ErrApplyKeysetPagination)
+	}
+	err = h.keysetBuilder.Apply(applier)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyKeysetPagination, err)
+	}
 	return nil
 }
 
+// HasCursor reports whether the request continues from a keyset cursor.
+func (h *GetList[TModel]) HasCursor() bool {
+	return h.keysetBuilder.HasCursor()
+}
+
+// WriteNextCursor fills the NextCursor destination after the page has been
+// read: the cursor of the last model when the page is full, empty otherwise.
+func (h *GetList[TModel]) WriteNextCursor(models []*TModel) error {
+	size := h.paginationBuilder.GetSize()
+	if len(models) == 0 || size == 0 || uint64(len(models)) < size {
+		return h.keysetBuilder.WriteNext(nil)
+	}
+	return h.keysetBuilder.WriteNext(models[len(models)-1])
+}
+
 func (h *GetList[TModel]) HandleFn(qFns ...func(m *TModel, h GetListHelper[TModel])) {
 	for _, fn := range qFns {
 		fn(h.baseModel, h)
@@ -88,12 +126,14 @@ func (h *GetList[TModel]) HandleFn(qFns ...func(m *TModel, h GetListHelper[TMode
 }
 
 func NewGetList[TModel any](baseModel *TModel) *GetList[TModel] {
+	orderBuilder := linq.NewOrderBuilder(baseModel)
 	return &GetList[TModel]{
 		baseModel: baseModel,
 
 		whereBuilder:      linq.NewWhereBuilder(baseModel),
 		excludeBuilder:    linq.NewExcludeBuilder(baseModel),
-		orderBuilder:      linq.NewOrderBuilder(baseModel),
+		orderBuilder:      orderBuilder,
 		paginationBuilder: linq.NewPaginationBuilder(),
+		keysetBuilder:     linq.NewKeysetBuilder(baseModel, orderBuilder),
 	}
 }
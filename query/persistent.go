@@ -53,6 +53,11 @@ func (h *Persistent[TModel]) InnerJoinOn(table, on string, resolver ...linq.Join
 	return h
 }
 
+// JoinTables returns the tables joined by LeftJoinOn and InnerJoinOn.
+func (h *Persistent[TModel]) JoinTables() []string {
+	return h.joinBuilder.Tables()
+}
+
 func (h *Persistent[TModel]) Exclude(fieldsPtr ...any) PersistentHelper[TModel] {
 	h.excludeBuilder.Exclude(fieldsPtr...)
 	return h
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/insei/gerpo/query/linq"
@@ -10,18 +11,21 @@ import (
 
 // UpdateHelper is the per-request helper for repo.Update. It composes the
 // small contracts from interfaces.go: filtering, narrowing the column set,
-// and per-request RETURNING control.
+// per-request RETURNING control and explicit SET assignments.
 type UpdateHelper[TModel any] interface {
 	Filterable
 	Excludable
 	Returnable
+	Settable
 }
 
 type UpdateApplier interface {
+	Ctx() context.Context
 	ColumnsStorage() types.ColumnsStorage
 	Columns() types.ExecutionColumns
 	Where() sqlpart.Where
 	SetReturning(cols []types.Column)
+	AppendAssignment(col types.Column, expr string, args ...any)
 }
 
 type Update[TModel any] struct {
@@ -30,6 +34,8 @@ type Update[TModel any] struct {
 	excludeBuilder   *linq.ExcludeBuilder
 	whereBuilder     *linq.WhereBuilder
 	returningBuilder *linq.ReturningBuilder
+	setBuilder       *linq.SetBuilder
+	versionBuilder   *linq.VersionBuilder
 }
 
 func (h *Update[TModel]) Exclude(fieldsPtr ...any) {
@@ -44,10 +50,40 @@ func (h *Update[TModel]) Returning(fieldsPtr ...any) {
 	h.returningBuilder.Returning(fieldsPtr...)
 }
 
+func (h *Update[TModel]) Set(fieldPtr any, value any) {
+	h.setBuilder.Set(fieldPtr, value)
+}
+
+func (h *Update[TModel]) Increment(fieldPtr any, n any) {
+	h.setBuilder.Increment(fieldPtr, n)
+}
+
+func (h *Update[TModel]) SetExpr(fieldPtr any, sql string, args ...any) {
+	h.setBuilder.SetExpr(fieldPtr, sql, args...)
+}
+
+// ExpectVersion pins the row version. It is not part of UpdateHelper — Update
+// takes the expected version from the model — and serves soft Delete, which
+// runs its DeleteHelper functions against this helper.
+func (h *Update[TModel]) ExpectVersion(version any) {
+	h.versionBuilder.ExpectVersion(version)
+}
+
+// ExpectsVersion reports whether the request pinned the row version through
+// ExpectVersion.
+func (h *Update[TModel]) ExpectsVersion() bool {
+	return h.versionBuilder.IsSet()
+}
+
 func (h *Update[TModel]) Where() types.WhereTarget {
 	return h.whereBuilder
 }
 
+// HasWhere reports whether the request added any WHERE condition.
+func (h *Update[TModel]) HasWhere() bool {
+	return !h.whereBuilder.IsEmpty()
+}
+
 func (h *Update[TModel]) Apply(applier UpdateApplier) error {
 	err := h.excludeBuilder.Apply(applier)
 	if err != nil {
@@ -60,6 +96,12 @@ func (h *Update[TModel]) Apply(applier UpdateApplier) error {
 	if err := h.returningBuilder.Apply(applier); err != nil {
 		return fmt.Errorf("%w: %w", ErrApplyReturningClause, err)
 	}
+	if err := h.setBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplySetClause, err)
+	}
+	if err := h.versionBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyVersionCondition, err)
+	}
 	return nil
 }
 
@@ -76,5 +118,7 @@ func NewUpdate[TModel any](baseModel *TModel) *Update[TModel] {
 		excludeBuilder:   linq.NewExcludeBuilder(baseModel),
 		whereBuilder:     linq.NewWhereBuilder(baseModel),
 		returningBuilder: linq.NewReturningBuilder(baseModel),
+		setBuilder:       linq.NewSetBuilder(baseModel),
+		versionBuilder:   linq.NewVersionBuilder(),
 	}
 }
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/insei/gerpo/query/linq"
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
+	"github.com/insei/gerpo/types"
+)
+
+// UpdateManyHelper is the per-request helper for repo.UpdateMany. It mirrors
+// the single-row UpdateHelper — filtering, narrowing the column set and
+// per-request RETURNING control — and adds Key, which names the columns each
+// model is matched to its row by. The column set, the RETURNING set and the
+// WHERE conditions are identical for every row in the batch.
+type UpdateManyHelper[TModel any] interface {
+	Filterable
+	Excludable
+	Returnable
+
+	// Key sets the identity fields rows are matched on: every model updates the row whose key columns hold the
+	// model's key values. Defaults to the PrimaryKey() columns; required when the repository declares none. Key
+	// columns are never SET.
+	Key(fieldsPtr ...any)
+}
+
+type UpdateManyApplier interface {
+	ColumnsStorage() types.ColumnsStorage
+	Columns() types.ExecutionColumns
+	Where() sqlpart.Where
+	SetReturning(cols []types.Column)
+	SetKey(cols []types.Column)
+}
+
+type UpdateMany[TModel any] struct {
+	baseModel *TModel
+
+	excludeBuilder   *linq.ExcludeBuilder
+	whereBuilder     *linq.WhereBuilder
+	returningBuilder *linq.ReturningBuilder
+	keyBuilder       *linq.KeyBuilder
+}
+
+func (h *UpdateMany[TModel]) Exclude(fieldsPtr ...any) {
+	h.excludeBuilder.Exclude(fieldsPtr...)
+}
+
+func (h *UpdateMany[TModel]) Only(fieldPointers ...any) {
+	h.excludeBuilder.Only(fieldPointers...)
+}
+
+func (h *UpdateMany[TModel]) Returning(fieldsPtr ...any) {
+	h.returningBuilder.Returning(fieldsPtr...)
+}
+
+func (h *UpdateMany[TModel]) Where() types.WhereTarget {
+	return h.whereBuilder
+}
+
+func (h *UpdateMany[TModel]) Key(fieldsPtr ...any) {
+	h.keyBuilder.Key(fieldsPtr...)
+}
+
+func (h *UpdateMany[TModel]) Apply(applier UpdateManyApplier) error {
+	if err := h.keyBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyUpdateKey, err)
+	}
+	if err := h.excludeBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyExcludeColumnRules, err)
+	}
+	if err := h.whereBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyWhereClause, err)
+	}
+	if err := h.returningBuilder.Apply(applier); err != nil {
+		return fmt.Errorf("%w: %w", ErrApplyReturningClause, err)
+	}
+	return nil
+}
+
+func (h *UpdateMany[TModel]) HandleFn(qFns ...func(m *TModel, h UpdateManyHelper[TModel])) {
+	for _, fn := range qFns {
+		fn(h.baseModel, h)
+	}
+}
+
+func NewUpdateMany[TModel any](baseModel *TModel) *UpdateMany[TModel] {
+	return &UpdateMany[TModel]{
+		baseModel: baseModel,
+
+		excludeBuilder:   linq.NewExcludeBuilder(baseModel),
+		whereBuilder:     linq.NewWhereBuilder(baseModel),
+		returningBuilder: linq.NewReturningBuilder(baseModel),
+		keyBuilder:       linq.NewKeyBuilder(baseModel),
+	}
+}
@@ -64,3 +64,31 @@ func TestUpdate_Apply_WhereFailure(t *testing.T) {
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, ErrApplyWhereClause))
 }
+
+func TestUpdate_Apply_SetAssignments(t *testing.T) {
+	m := &updateModel{}
+	a := newMockApplier()
+	a.storage.byPtr[&m.Name] = &mockColumn{name: "name", hasName: true, allowed: true}
+	a.storage.byPtr[&m.ID] = &mockColumn{name: "id", hasName: true, allowed: true}
+
+	h := NewUpdate(m)
+	h.HandleFn(func(m *updateModel, h UpdateHelper[updateModel]) {
+		h.Set(&m.Name, "bob")
+		h.Increment(&m.ID, 2)
+		h.SetExpr(&m.Name, "upper(?)", "alice")
+	})
+	require.NoError(t, h.Apply(a))
+	assert.Equal(t, []string{"name = ?", "id = id + ?", "name = upper(?)"}, a.assignments)
+	assert.Equal(t, []any{"bob", 2, "alice"}, a.assignArgs)
+}
+
+func TestUpdate_Apply_SetMissing_Err(t *testing.T) {
+	m := &updateModel{}
+	h := NewUpdate(m)
+	h.HandleFn(func(m *updateModel, h UpdateHelper[updateModel]) {
+		h.Set(&m.Name, "bob")
+	})
+	err := h.Apply(newMockApplier())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrApplySetClause))
+}
@@ -2,8 +2,13 @@ package gerpo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/query"
 	"github.com/insei/gerpo/sqlstmt"
@@ -18,22 +23,41 @@ type repository[TModel any] struct {
 	beforeInsert     func(ctx context.Context, model *TModel) error
 	beforeInsertMany func(ctx context.Context, models []*TModel) error
 	beforeUpdate     func(ctx context.Context, model *TModel) error
+	beforeUpdateMany func(ctx context.Context, models []*TModel) error
 	afterInsert      func(ctx context.Context, model *TModel) error
 	afterInsertMany  func(ctx context.Context, models []*TModel) error
 	afterUpdate      func(ctx context.Context, model *TModel) error
+	afterUpdateMany  func(ctx context.Context, models []*TModel) error
 	afterSelect      func(ctx context.Context, models []*TModel) error
 	errorTransformer func(err error) error
 
 	// Tracing — opt-in via WithTracer; nil means "no spans".
 	tracer Tracer
 
+	// iterateBatchSize is how many streamed models Iterate hands to
+	// afterSelect at once; see WithIterateBatchSize.
+	iterateBatchSize int
+
 	// Columns and fields
 	baseModel *TModel
 	table     string
 	columns   types.ColumnsStorage
+	// version is the optimistic-locking column (column.Version), nil if none.
+	version types.Column
+	// primaryKey holds the column.PrimaryKey columns in declaration order,
+	// empty if none.
+	primaryKey []types.Column
+	// dialect is the adapter's SQL dialect, nil for PostgreSQL.
+	dialect dialect.Dialect
+	// fieldsByColumn maps the columns of table to the struct paths of their
+	// fields, for the Field of constraint violations.
+	fieldsByColumn map[string]string
 
 	// SQL Query, execution and dependency
-	executor        executor.Executor[TModel]
+	executor executor.Executor[TModel]
+	// adapter is the writer adapter of executor, for the transactions the
+	// repository opens itself.
+	adapter         executor.Adapter
 	persistentQuery *query.Persistent[TModel]
 
 	deleteFn func(ctx context.Context, qFns ...func(m *TModel, h query.DeleteHelper[TModel])) (count int64, err error)
@@ -41,8 +65,12 @@ type repository[TModel any] struct {
 
 // startSpan opens a tracing span around a repository operation. When no Tracer
 // is configured, returns the original context and a no-op end function so the
-// callers stay branch-free.
+// callers stay branch-free. Every operation calls it first, so it also binds
+// a non-PostgreSQL dialect to the context for the statement renderers.
 func (r *repository[TModel]) startSpan(ctx context.Context, op string) (context.Context, SpanEnd) {
+	if r.dialect != nil {
+		ctx = dialect.WithContext(ctx, r.dialect)
+	}
 	if r.tracer == nil {
 		return ctx, noopSpanEnd
 	}
@@ -68,6 +96,12 @@ func replaceNilCallbacks[TModel any](repo *repository[TModel]) {
 	if repo.afterUpdate == nil {
 		repo.afterUpdate = func(_ context.Context, _ *TModel) error { return nil }
 	}
+	if repo.beforeUpdateMany == nil {
+		repo.beforeUpdateMany = func(_ context.Context, _ []*TModel) error { return nil }
+	}
+	if repo.afterUpdateMany == nil {
+		repo.afterUpdateMany = func(_ context.Context, _ []*TModel) error { return nil }
+	}
 	if repo.afterSelect == nil {
 		repo.afterSelect = func(_ context.Context, _ []*TModel) error { return nil }
 	}
@@ -96,13 +130,28 @@ func newRepository[TModel any](exec executor.Executor[TModel], table string, col
 		return nil, fmt.Errorf("failed to create repository with empty columns")
 	}
 	repo := &repository[TModel]{
-		columns:         columns,
-		executor:        exec,
-		table:           table,
-		baseModel:       model,
-		persistentQuery: query.NewPersistent(model),
+		columns:          columns,
+		executor:         exec,
+		table:            table,
+		baseModel:        model,
+		persistentQuery:  query.NewPersistent(model),
+		iterateBatchSize: defaultIterateBatchSize,
+		fieldsByColumn:   make(map[string]string),
 	}
 	repo.deleteFn = repo.delete
+	for _, col := range columns.AsSlice() {
+		name, hasName := col.Name()
+		colTable, _ := col.Table()
+		if hasName && colTable == table {
+			repo.fieldsByColumn[name] = col.GetField().GetStructPath()
+		}
+		if col.IsVersion() {
+			repo.version = col
+		}
+		if col.IsPrimaryKey() {
+			repo.primaryKey = append(repo.primaryKey, col)
+		}
+	}
 
 	for _, opt := range opts {
 		err := opt.apply(repo)
@@ -111,10 +160,81 @@ func newRepository[TModel any](exec executor.Executor[TModel], table string, col
 		}
 	}
 
+	if binder, ok := exec.(executor.CacheBinder); ok {
+		binder.BindCache(table, repo.cacheDependencies())
+	}
+
 	replaceNilCallbacks(repo)
+	transform := repo.errorTransformer
+	repo.errorTransformer = func(err error) error {
+		return transform(repo.violationField(err))
+	}
 	return repo, nil
 }
 
+// cacheDependencies returns the tables besides the repository table its reads
+// depend on: those of the persistent JOINs, of the columns bound to other
+// tables and of the virtual columns declaring DependsOn.
+func (r *repository[TModel]) cacheDependencies() []string {
+	var tables []string
+	add := func(table string) {
+		if table != "" && table != r.table && !slices.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+	for _, table := range r.persistentQuery.JoinTables() {
+		add(table)
+	}
+	for _, col := range r.columns.AsSlice() {
+		if table, ok := col.Table(); ok {
+			add(table)
+		}
+		if dependent, ok := col.(types.DependentColumn); ok {
+			for _, table := range dependent.Dependencies() {
+				add(table)
+			}
+		}
+	}
+	return tables
+}
+
+// violationField fills Field of a constraint violation on the table of the
+// repository with the fields its columns map to, so the error transformer and
+// the caller see it. Violations of other tables — raised by hooks writing
+// through other repositories — are left to those.
+func (r *repository[TModel]) violationField(err error) error {
+	var violation interface {
+		Violation() *executor.ConstraintViolation
+	}
+	if err == nil || !errors.As(err, &violation) {
+		return err
+	}
+	v := violation.Violation()
+	if v.Field != "" || v.Column == "" || !sameTable(r.table, v.Table) {
+		return err
+	}
+	columns := strings.Split(v.Column, ", ")
+	fields := make([]string, 0, len(columns))
+	for _, column := range columns {
+		field, ok := r.fieldsByColumn[column]
+		if !ok {
+			return err
+		}
+		fields = append(fields, field)
+	}
+	v.Field = strings.Join(fields, ", ")
+	return err
+}
+
+// sameTable reports whether the table a database error names is table, which
+// may be schema-qualified; databases report the bare name.
+func sameTable(table, reported string) bool {
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		table = table[i+1:]
+	}
+	return reported != "" && strings.EqualFold(table, reported)
+}
+
 func (r *repository[TModel]) GetColumns() types.ColumnsStorage {
 	return r.columns
 }
@@ -137,6 +257,38 @@ func (r *repository[TModel]) GetFirst(ctx context.Context, qFns ...func(m *TMode
 		return nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
 
+	model, err = r.executor.GetOne(q.CacheContext(ctx), stmt)
+	if err != nil {
+		return nil, r.errorTransformer(err)
+	}
+
+	if err = r.afterSelect(ctx, []*TModel{model}); err != nil {
+		return model, r.errorTransformer(err)
+	}
+	return model, nil
+}
+
+func (r *repository[TModel]) GetByID(ctx context.Context, key ...any) (model *TModel, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.GetByID")
+	defer func() { end(err) }()
+
+	if err = r.checkKey(key); err != nil {
+		return nil, r.errorTransformer(err)
+	}
+	stmt := sqlstmt.NewGetFirst(ctx, r.table, r.columns)
+	defer stmt.Release()
+	err = r.persistentQuery.Apply(stmt)
+	if err != nil {
+		return nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err))
+	}
+
+	q := query.NewGetFirst(r.baseModel)
+	whereKey(q.Where(), r.primaryKey, key)
+	err = q.Apply(stmt)
+	if err != nil {
+		return nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
+	}
+
 	model, err = r.executor.GetOne(ctx, stmt)
 	if err != nil {
 		return nil, r.errorTransformer(err)
@@ -148,6 +300,51 @@ func (r *repository[TModel]) GetFirst(ctx context.Context, qFns ...func(m *TMode
 	return model, nil
 }
 
+func (r *repository[TModel]) GetByIDs(ctx context.Context, keys ...any) (models []*TModel, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.GetByIDs")
+	defer func() { end(err) }()
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	split, err := r.splitKeys(keys)
+	if err != nil {
+		return nil, r.errorTransformer(err)
+	}
+
+	// One statement per chunk keeps the IN list (or OR chain) within the
+	// parameter limits of every database.
+	chunk := max(1, keyChunkParams/len(r.primaryKey))
+	for start := 0; start < len(split); start += chunk {
+		var found []*TModel
+		found, err = r.getByKeys(ctx, split[start:min(start+chunk, len(split))])
+		if err != nil {
+			return nil, r.errorTransformer(err)
+		}
+		models = append(models, found...)
+	}
+
+	if err = r.afterSelect(ctx, models); err != nil {
+		return models, r.errorTransformer(err)
+	}
+	return models, nil
+}
+
+func (r *repository[TModel]) getByKeys(ctx context.Context, keys [][]any) ([]*TModel, error) {
+	stmt := sqlstmt.NewGetList(ctx, r.table, r.columns)
+	defer stmt.Release()
+	if err := r.persistentQuery.Apply(stmt); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err)
+	}
+
+	q := query.NewGetList(r.baseModel)
+	whereKeys(q.Where(), r.primaryKey, keys)
+	if err := q.Apply(stmt); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrApplyQuery, err)
+	}
+	return r.executor.GetMultiple(ctx, stmt)
+}
+
 func (r *repository[TModel]) GetList(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) (models []*TModel, err error) {
 	ctx, end := r.startSpan(ctx, "gerpo.GetList")
 	defer func() { end(err) }()
@@ -166,10 +363,13 @@ func (r *repository[TModel]) GetList(ctx context.Context, qFns ...func(m *TModel
 		return nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
 
-	models, err = r.executor.GetMultiple(ctx, stmt)
+	models, err = r.executor.GetMultiple(q.CacheContext(ctx), stmt)
 	if err != nil {
 		return nil, r.errorTransformer(err)
 	}
+	if err = q.WriteNextCursor(models); err != nil {
+		return nil, r.errorTransformer(err)
+	}
 
 	if err = r.afterSelect(ctx, models); err != nil {
 		return models, r.errorTransformer(err)
@@ -177,6 +377,106 @@ func (r *repository[TModel]) GetList(ctx context.Context, qFns ...func(m *TModel
 	return models, nil
 }
 
+func (r *repository[TModel]) GetPage(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) (models []*TModel, total uint64, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.GetPage")
+	defer func() { end(err) }()
+
+	stmt := sqlstmt.NewGetList(ctx, r.table, r.columns)
+	defer stmt.Release()
+	err = r.persistentQuery.Apply(stmt)
+	if err != nil {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err))
+	}
+
+	q := query.NewGetList(r.baseModel)
+	q.HandleFn(qFns...)
+	err = q.Apply(stmt)
+	if err != nil {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
+	}
+	if q.HasCursor() {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: GetPage cannot continue from a keyset cursor, use GetList", ErrApplyQuery))
+	}
+
+	cacheCtx := q.CacheContext(ctx)
+	models, total, err = r.executor.GetPage(cacheCtx, stmt)
+	if err != nil {
+		return nil, 0, r.errorTransformer(err)
+	}
+	// A page past the end has no row to carry the window total: re-read the
+	// first row of the same filtered set to get it.
+	if len(models) == 0 && stmt.LimitOffset().GetOffset() > 0 {
+		stmt.LimitOffset().SetOffset(0)
+		stmt.LimitOffset().SetLimit(1)
+		if _, total, err = r.executor.GetPage(cacheCtx, stmt); err != nil {
+			return nil, 0, r.errorTransformer(err)
+		}
+	}
+	if err = q.WriteNextCursor(models); err != nil {
+		return nil, 0, r.errorTransformer(err)
+	}
+
+	if err = r.afterSelect(ctx, models); err != nil {
+		return models, total, r.errorTransformer(err)
+	}
+	return models, total, nil
+}
+
+func (r *repository[TModel]) Iterate(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) iter.Seq2[*TModel, error] {
+	return func(yield func(*TModel, error) bool) {
+		var err error
+		ctx, end := r.startSpan(ctx, "gerpo.Iterate")
+		defer func() { end(err) }()
+
+		stmt := sqlstmt.NewGetList(ctx, r.table, r.columns)
+		defer stmt.Release()
+		err = r.persistentQuery.Apply(stmt)
+		if err != nil {
+			yield(nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err)))
+			return
+		}
+
+		q := query.NewGetList(r.baseModel)
+		q.HandleFn(qFns...)
+		err = q.Apply(stmt)
+		if err != nil {
+			yield(nil, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err)))
+			return
+		}
+
+		// afterSelect sees the models in batches before the caller does, so
+		// hooks that fill fields behave as in GetList.
+		batch := make([]*TModel, 0, r.iterateBatchSize)
+		flush := func() bool {
+			if err = r.afterSelect(ctx, batch); err != nil {
+				yield(nil, r.errorTransformer(err))
+				return false
+			}
+			for _, m := range batch {
+				if !yield(m, nil) {
+					return false
+				}
+			}
+			batch = batch[:0]
+			return true
+		}
+		for model, iterErr := range r.executor.Iterate(ctx, stmt) {
+			if iterErr != nil {
+				err = iterErr
+				yield(nil, r.errorTransformer(err))
+				return
+			}
+			batch = append(batch, model)
+			if len(batch) == r.iterateBatchSize && !flush() {
+				return
+			}
+		}
+		if len(batch) > 0 {
+			flush()
+		}
+	}
+}
+
 func (r *repository[TModel]) Count(ctx context.Context, qFns ...func(m *TModel, h query.CountHelper[TModel])) (count uint64, err error) {
 	ctx, end := r.startSpan(ctx, "gerpo.Count")
 	defer func() { end(err) }()
@@ -195,7 +495,7 @@ func (r *repository[TModel]) Count(ctx context.Context, qFns ...func(m *TModel,
 		return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
 
-	count, err = r.executor.Count(ctx, stmt)
+	count, err = r.executor.Count(q.CacheContext(ctx), stmt)
 	if err != nil {
 		return 0, r.errorTransformer(err)
 	}
@@ -206,7 +506,16 @@ func (r *repository[TModel]) Count(ctx context.Context, qFns ...func(m *TModel,
 func (r *repository[TModel]) Insert(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.InsertHelper[TModel])) (err error) {
 	ctx, end := r.startSpan(ctx, "gerpo.Insert")
 	defer func() { end(err) }()
+	return r.insert(ctx, model, false, qFns...)
+}
+
+func (r *repository[TModel]) Upsert(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.InsertHelper[TModel])) (err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.Upsert")
+	defer func() { end(err) }()
+	return r.insert(ctx, model, true, qFns...)
+}
 
+func (r *repository[TModel]) insert(ctx context.Context, model *TModel, upsert bool, qFns ...func(m *TModel, h query.InsertHelper[TModel])) (err error) {
 	if err = r.beforeInsert(ctx, model); err != nil {
 		return r.errorTransformer(err)
 	}
@@ -222,6 +531,14 @@ func (r *repository[TModel]) Insert(ctx context.Context, model *TModel, qFns ...
 	if err != nil {
 		return r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
+	if upsert && !stmt.HasConflictClause() {
+		return r.errorTransformer(fmt.Errorf("%w: upsert requires an OnConflict spec", ErrApplyQuery))
+	}
+	if stmt.HasConflictClause() {
+		if err = r.checkOnConflict(stmt.ConflictConstraint()); err != nil {
+			return r.errorTransformer(err)
+		}
+	}
 
 	err = r.executor.InsertOne(ctx, stmt, model)
 	if err != nil {
@@ -252,6 +569,11 @@ func (r *repository[TModel]) InsertMany(ctx context.Context, models []*TModel, q
 	if err = q.Apply(stmt); err != nil {
 		return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
+	if stmt.HasConflictClause() {
+		if err = r.checkOnConflict(stmt.ConflictConstraint()); err != nil {
+			return 0, r.errorTransformer(err)
+		}
+	}
 
 	count, err = r.executor.InsertMany(ctx, stmt, models)
 	if err != nil {
@@ -264,6 +586,46 @@ func (r *repository[TModel]) InsertMany(ctx context.Context, models []*TModel, q
 	return count, nil
 }
 
+// checkOnConflict rejects an INSERT with an ON CONFLICT clause on a dialect
+// that has none, and one targeting the named constraint on a dialect whose
+// clause takes columns only.
+func (r *repository[TModel]) checkOnConflict(constraint string) error {
+	if r.dialect == nil {
+		return nil
+	}
+	if !r.dialect.SupportsOnConflict() {
+		return fmt.Errorf("%w: %s", ErrUpsertNotSupported, r.dialect.Name())
+	}
+	if constraint != "" && !r.dialect.SupportsConflictConstraint() {
+		return fmt.Errorf("%w: %s has no ON CONFLICT ON CONSTRAINT, target the columns of %q instead", ErrUpsertNotSupported, r.dialect.Name(), constraint)
+	}
+	return nil
+}
+
+func (r *repository[TModel]) BulkCopy(ctx context.Context, models []*TModel) (count int64, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.BulkCopy")
+	defer func() { end(err) }()
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	if err = r.beforeInsertMany(ctx, models); err != nil {
+		return 0, r.errorTransformer(err)
+	}
+
+	stmt := sqlstmt.NewCopy(ctx, r.table, r.columns)
+	count, err = r.executor.BulkCopy(ctx, stmt, models)
+	if err != nil {
+		return count, r.errorTransformer(err)
+	}
+
+	if err = r.afterInsertMany(ctx, models); err != nil {
+		return count, r.errorTransformer(err)
+	}
+	return count, nil
+}
+
 func (r *repository[TModel]) Update(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.UpdateHelper[TModel])) (count int64, err error) {
 	ctx, end := r.startSpan(ctx, "gerpo.Update")
 	defer func() { end(err) }()
@@ -279,10 +641,20 @@ func (r *repository[TModel]) Update(ctx context.Context, model *TModel, qFns ...
 
 	q := query.NewUpdate(r.baseModel)
 	q.HandleFn(qFns...)
+	if !q.HasWhere() && len(r.primaryKey) > 0 {
+		whereKey(q.Where(), r.primaryKey, r.modelKey(model))
+	}
 	err = q.Apply(stmt)
 	if err != nil {
 		return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
 	}
+	commitVersion := func() {}
+	if r.version != nil {
+		commitVersion, err = lockVersion(stmt, r.version, model)
+		if err != nil {
+			return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
+		}
+	}
 
 	updatedCount, err := r.executor.Update(ctx, stmt, model)
 	if err != nil {
@@ -290,14 +662,118 @@ func (r *repository[TModel]) Update(ctx context.Context, model *TModel, qFns ...
 	}
 
 	if updatedCount < 1 {
+		if r.version != nil {
+			return updatedCount, r.errorTransformer(ErrStaleVersion)
+		}
 		return updatedCount, r.errorTransformer(fmt.Errorf("nothing to update: %w", ErrNotFound))
 	}
+	commitVersion()
 	if err = r.afterUpdate(ctx, model); err != nil {
 		return updatedCount, r.errorTransformer(err)
 	}
 	return updatedCount, nil
 }
 
+func (r *repository[TModel]) UpdateWhere(ctx context.Context, qFns ...func(m *TModel, h query.UpdateHelper[TModel])) (models []*TModel, count int64, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.UpdateWhere")
+	defer func() { end(err) }()
+
+	stmt := sqlstmt.NewUpdate(ctx, r.columns, r.table)
+	err = r.persistentQuery.Apply(stmt)
+	if err != nil {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err))
+	}
+
+	q := query.NewUpdate(r.baseModel)
+	q.HandleFn(qFns...)
+	err = q.Apply(stmt)
+	if err != nil {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
+	}
+	if !stmt.HasAssignments() {
+		return nil, 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, ErrNoAssignments))
+	}
+	if r.version != nil {
+		bumpVersion(stmt, r.version)
+	}
+
+	models, count, err = r.executor.UpdateWhere(ctx, stmt)
+	if err != nil {
+		return models, count, r.errorTransformer(err)
+	}
+	if count < 1 {
+		return nil, count, r.errorTransformer(fmt.Errorf("nothing to update: %w", ErrNotFound))
+	}
+	return models, count, nil
+}
+
+func (r *repository[TModel]) UpdateMany(ctx context.Context, models []*TModel, qFns ...func(m *TModel, h query.UpdateManyHelper[TModel])) (count int64, err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.UpdateMany")
+	defer func() { end(err) }()
+
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	if err = r.beforeUpdateMany(ctx, models); err != nil {
+		return 0, r.errorTransformer(err)
+	}
+	stmt := sqlstmt.NewUpdateBatch(ctx, r.table, r.columns)
+	err = r.persistentQuery.Apply(stmt)
+	if err != nil {
+		return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyPersistentQuery, err))
+	}
+
+	stmt.SetKey(r.primaryKey)
+	q := query.NewUpdateMany(r.baseModel)
+	q.HandleFn(qFns...)
+	if err = q.Apply(stmt); err != nil {
+		return 0, r.errorTransformer(fmt.Errorf("%w: %w", ErrApplyQuery, err))
+	}
+	if r.version == nil {
+		count, err = r.executor.UpdateMany(ctx, stmt, models)
+		if err != nil {
+			return count, r.errorTransformer(err)
+		}
+	} else if count, err = r.updateManyVersioned(ctx, stmt, models); err != nil {
+		return count, r.errorTransformer(err)
+	}
+	if count < 1 {
+		return count, r.errorTransformer(fmt.Errorf("nothing to update: %w", ErrNotFound))
+	}
+	if err = r.afterUpdateMany(ctx, models); err != nil {
+		return count, r.errorTransformer(err)
+	}
+	return count, nil
+}
+
+// updateManyVersioned runs a versioned UpdateMany all or nothing: in a
+// transaction of its own, or a savepoint of the one ctx carries, rolled back
+// when any row turns out stale. The models then keep their versions and the
+// columns RETURNING wrote into them, so none of them is ahead of the
+// database.
+func (r *repository[TModel]) updateManyVersioned(ctx context.Context, stmt *sqlstmt.UpdateBatch, models []*TModel) (count int64, err error) {
+	commitVersions := lockVersions(stmt, r.version, models)
+	restore := snapshotColumns(stmt.ReturningColumns(), models)
+	err = RunInTx(ctx, r.adapter, func(ctx context.Context) error {
+		n, err := r.executor.UpdateMany(ctx, stmt, models)
+		if err != nil {
+			return err
+		}
+		if n < int64(len(models)) {
+			return ErrStaleVersion
+		}
+		count = n
+		return nil
+	})
+	if err != nil {
+		restore()
+		return 0, err
+	}
+	commitVersions()
+	return count, nil
+}
+
 func (r *repository[TModel]) delete(ctx context.Context, qFns ...func(m *TModel, h query.DeleteHelper[TModel])) (count int64, err error) {
 	stmt := sqlstmt.NewDelete(ctx, r.table, r.columns)
 	err = r.persistentQuery.Apply(stmt)
@@ -318,6 +794,9 @@ func (r *repository[TModel]) delete(ctx context.Context, qFns ...func(m *TModel,
 	}
 
 	if count < 1 {
+		if q.ExpectsVersion() {
+			return 0, r.errorTransformer(ErrStaleVersion)
+		}
 		return 0, r.errorTransformer(fmt.Errorf("nothing to delete: %w", ErrNotFound))
 	}
 	return count, r.errorTransformer(err)
@@ -329,3 +808,16 @@ func (r *repository[TModel]) Delete(ctx context.Context, qFns ...func(m *TModel,
 	count, err = r.deleteFn(ctx, qFns...)
 	return count, err
 }
+
+func (r *repository[TModel]) DeleteByID(ctx context.Context, key ...any) (err error) {
+	ctx, end := r.startSpan(ctx, "gerpo.DeleteByID")
+	defer func() { end(err) }()
+
+	if err = r.checkKey(key); err != nil {
+		return r.errorTransformer(err)
+	}
+	_, err = r.deleteFn(ctx, func(_ *TModel, h query.DeleteHelper[TModel]) {
+		whereKey(h.Where(), r.primaryKey, key)
+	})
+	return err
+}
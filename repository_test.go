@@ -3,8 +3,10 @@ package gerpo
 import (
 	"context"
 	"errors"
+	"iter"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/insei/gerpo/executor"
@@ -17,9 +19,13 @@ type MockExecutor[TModel any] struct {
 	UpdateFunc      func(ctx context.Context, stmt executor.Stmt, model *TModel) (int64, error)
 	InsertOneFunc   func(ctx context.Context, stmt executor.Stmt, model *TModel) error
 	InsertManyFunc  func(ctx context.Context, stmt executor.BatchStmt, models []*TModel) (int64, error)
+	UpdateManyFunc  func(ctx context.Context, stmt executor.KeyedBatchStmt, models []*TModel) (int64, error)
+	BulkCopyFunc    func(ctx context.Context, stmt executor.CopyStmt, models []*TModel) (int64, error)
 	CountFunc       func(ctx context.Context, stmt executor.CountStmt) (uint64, error)
 	GetMultipleFunc func(ctx context.Context, stmt executor.Stmt) ([]*TModel, error)
+	GetPageFunc     func(ctx context.Context, stmt executor.PageStmt) ([]*TModel, uint64, error)
 	GetOneFunc      func(ctx context.Context, stmt executor.Stmt) (*TModel, error)
+	IterateFunc     func(ctx context.Context, stmt executor.Stmt) iter.Seq2[*TModel, error]
 }
 
 func (m *MockExecutor[TModel]) Delete(ctx context.Context, stmt executor.CountStmt) (int64, error) {
@@ -38,6 +44,14 @@ func (m *MockExecutor[TModel]) InsertMany(ctx context.Context, stmt executor.Bat
 	return m.InsertManyFunc(ctx, stmt, models)
 }
 
+func (m *MockExecutor[TModel]) UpdateMany(ctx context.Context, stmt executor.KeyedBatchStmt, models []*TModel) (int64, error) {
+	return m.UpdateManyFunc(ctx, stmt, models)
+}
+
+func (m *MockExecutor[TModel]) BulkCopy(ctx context.Context, stmt executor.CopyStmt, models []*TModel) (int64, error) {
+	return m.BulkCopyFunc(ctx, stmt, models)
+}
+
 func (m *MockExecutor[TModel]) Count(ctx context.Context, stmt executor.CountStmt) (uint64, error) {
 	return m.CountFunc(ctx, stmt)
 }
@@ -46,6 +60,14 @@ func (m *MockExecutor[TModel]) GetMultiple(ctx context.Context, stmt executor.St
 	return m.GetMultipleFunc(ctx, stmt)
 }
 
+func (m *MockExecutor[TModel]) GetPage(ctx context.Context, stmt executor.PageStmt) ([]*TModel, uint64, error) {
+	return m.GetPageFunc(ctx, stmt)
+}
+
+func (m *MockExecutor[TModel]) Iterate(ctx context.Context, stmt executor.Stmt) iter.Seq2[*TModel, error] {
+	return m.IterateFunc(ctx, stmt)
+}
+
 func (m *MockExecutor[TModel]) GetOne(ctx context.Context, stmt executor.Stmt) (*TModel, error) {
 	return m.GetOneFunc(ctx, stmt)
 }
@@ -262,6 +284,195 @@ func TestRepository_GetList(t *testing.T) {
 	}
 }
 
+func TestRepository_GetPage(t *testing.T) {
+	type model struct {
+		ID   int
+		Name string
+	}
+	ErrTest := errors.New("test error")
+
+	tests := []struct {
+		name          string
+		qFn           func(m *model, h query.GetListHelper[model])
+		pages         [][]*model
+		total         uint64
+		execErr       error
+		expectedList  []*model
+		expectedTotal uint64
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "Page and total from one statement",
+			qFn:           func(m *model, h query.GetListHelper[model]) { h.Page(1).Size(2) },
+			pages:         [][]*model{{{ID: 1}, {ID: 2}}},
+			total:         5,
+			expectedList:  []*model{{ID: 1}, {ID: 2}},
+			expectedTotal: 5,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Empty first page needs no second round-trip",
+			qFn:           func(m *model, h query.GetListHelper[model]) { h.Size(2) },
+			pages:         [][]*model{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Page past the end re-reads the total",
+			qFn:           func(m *model, h query.GetListHelper[model]) { h.Page(9).Size(2) },
+			pages:         [][]*model{nil, {{ID: 1}}},
+			total:         3,
+			expectedTotal: 3,
+			expectedCalls: 2,
+		},
+		{
+			name:          "Executor error",
+			qFn:           func(m *model, h query.GetListHelper[model]) { h.Size(2) },
+			execErr:       ErrTest,
+			expectedCalls: 1,
+			expectedErr:   ErrTest,
+		},
+		{
+			name: "Keyset cursor rejected",
+			qFn: func(m *model, h query.GetListHelper[model]) {
+				h.OrderBy().Field(&m.ID).ASC()
+				h.Size(2).After("WzFd")
+			},
+			expectedErr: ErrApplyQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			exec := &MockExecutor[model]{
+				GetPageFunc: func(ctx context.Context, stmt executor.PageStmt) ([]*model, uint64, error) {
+					calls++
+					if tt.execErr != nil {
+						return nil, 0, tt.execErr
+					}
+					models := tt.pages[calls-1]
+					if len(models) == 0 {
+						return nil, 0, nil
+					}
+					return models, tt.total, nil
+				},
+			}
+			repo, err := newRepository[model](exec, "test_table", func(m *model, builder *ColumnBuilder[model]) {
+				builder.Field(&m.ID)
+				builder.Field(&m.Name)
+			})
+			require.NoError(t, err)
+
+			list, total, err := repo.GetPage(context.Background(), tt.qFn)
+			require.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedList, list)
+			require.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func TestRepository_Iterate(t *testing.T) {
+	type model struct {
+		ID   int
+		Name string
+	}
+	ErrTest := errors.New("test error")
+	source := func(n int, tailErr error) func(ctx context.Context, stmt executor.Stmt) iter.Seq2[*model, error] {
+		return func(ctx context.Context, stmt executor.Stmt) iter.Seq2[*model, error] {
+			return func(yield func(*model, error) bool) {
+				for i := 1; i <= n; i++ {
+					if !yield(&model{ID: i}, nil) {
+						return
+					}
+				}
+				if tailErr != nil {
+					yield(nil, tailErr)
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name          string
+		rows          int
+		tailErr       error
+		hookErr       error
+		breakAfter    int
+		expectedIDs   []int
+		expectedHooks []int
+		expectedErr   error
+	}{
+		{
+			name:          "Hook runs per batch and on the remainder",
+			rows:          5,
+			expectedIDs:   []int{1, 2, 3, 4, 5},
+			expectedHooks: []int{2, 2, 1},
+		},
+		{
+			name:          "Early break stops the stream",
+			rows:          5,
+			breakAfter:    3,
+			expectedIDs:   []int{1, 2, 3},
+			expectedHooks: []int{2, 2},
+		},
+		{
+			name:          "Executor error is yielded",
+			rows:          1,
+			tailErr:       ErrTest,
+			expectedErr:   ErrTest,
+			expectedHooks: nil,
+		},
+		{
+			name:          "Hook error is yielded before the batch",
+			rows:          3,
+			hookErr:       ErrTest,
+			expectedErr:   ErrTest,
+			expectedHooks: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hooks []int
+			repo, err := newRepository[model](&MockExecutor[model]{IterateFunc: source(tt.rows, tt.tailErr)}, "test_table",
+				func(m *model, builder *ColumnBuilder[model]) {
+					builder.Field(&m.ID)
+					builder.Field(&m.Name)
+				},
+				WithIterateBatchSize[model](2),
+				WithAfterSelect[model](func(ctx context.Context, models []*model) error {
+					hooks = append(hooks, len(models))
+					return tt.hookErr
+				}))
+			require.NoError(t, err)
+
+			var ids []int
+			var gotErr error
+			for m, err := range repo.Iterate(context.Background()) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				ids = append(ids, m.ID)
+				if tt.breakAfter > 0 && len(ids) == tt.breakAfter {
+					break
+				}
+			}
+			require.ErrorIs(t, gotErr, tt.expectedErr)
+			if tt.expectedErr == nil {
+				require.Equal(t, tt.expectedIDs, ids)
+			}
+			require.Equal(t, tt.expectedHooks, hooks)
+		})
+	}
+}
+
 func TestRepository_Count(t *testing.T) {
 	type model struct {
 		ID    int
@@ -592,3 +803,371 @@ func TestRepository_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_Upsert(t *testing.T) {
+	type model struct {
+		ID    int
+		Name  string
+		Email string
+	}
+
+	tests := []struct {
+		name        string
+		executor    executor.Executor[model]
+		qFns        []func(m *model, h query.InsertHelper[model])
+		expectedErr error
+		afterCalls  int
+	}{
+		{
+			name: "Conflict spec is required",
+			executor: &MockExecutor[model]{
+				InsertOneFunc: func(ctx context.Context, stmt executor.Stmt, model *model) error {
+					return errors.New("must not be called")
+				},
+			},
+			expectedErr: ErrApplyQuery,
+		},
+		{
+			name: "Row written",
+			executor: &MockExecutor[model]{
+				InsertOneFunc: func(ctx context.Context, stmt executor.Stmt, model *model) error {
+					return nil
+				},
+			},
+			qFns: []func(m *model, h query.InsertHelper[model]){
+				func(m *model, h query.InsertHelper[model]) {
+					h.OnConflict(&m.ID).DoUpdate(&m.Name)
+				},
+			},
+			afterCalls: 1,
+		},
+		{
+			name: "Row skipped by DO NOTHING",
+			executor: &MockExecutor[model]{
+				InsertOneFunc: func(ctx context.Context, stmt executor.Stmt, model *model) error {
+					return executor.ErrInsertConflict
+				},
+			},
+			qFns: []func(m *model, h query.InsertHelper[model]){
+				func(m *model, h query.InsertHelper[model]) {
+					h.OnConflict(&m.ID).DoNothing()
+				},
+			},
+			expectedErr: ErrInsertConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			afterCalls := 0
+			repo, err := newRepository[model](tt.executor, "test_table", func(m *model, builder *ColumnBuilder[model]) {
+				builder.Field(&m.ID)
+				builder.Field(&m.Name)
+				builder.Field(&m.Email)
+			}, WithAfterInsert[model](func(ctx context.Context, m *model) error {
+				afterCalls++
+				return nil
+			}))
+			require.NoError(t, err)
+
+			err = repo.Upsert(context.Background(), &model{ID: 1, Name: "Alice"}, tt.qFns...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			require.Equal(t, tt.afterCalls, afterCalls)
+		})
+	}
+}
+
+func TestRepository_BulkCopy(t *testing.T) {
+	type model struct {
+		ID   int
+		Name string
+	}
+	ErrTest := errors.New("test error")
+
+	tests := []struct {
+		name        string
+		models      []*model
+		copyErr     error
+		expectedErr error
+		copyCalls   int
+		afterCalls  int
+	}{
+		{name: "Empty slice is a no-op"},
+		{
+			name:       "Rows copied",
+			models:     []*model{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			copyCalls:  1,
+			afterCalls: 1,
+		},
+		{
+			name:        "Copy error skips after hook",
+			models:      []*model{{ID: 1, Name: "a"}},
+			copyErr:     ErrCopyNotSupported,
+			expectedErr: ErrCopyNotSupported,
+			copyCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copyCalls, afterCalls := 0, 0
+			exec := &MockExecutor[model]{
+				BulkCopyFunc: func(ctx context.Context, stmt executor.CopyStmt, models []*model) (int64, error) {
+					copyCalls++
+					names, err := stmt.ColumnNames()
+					require.NoError(t, err)
+					require.Equal(t, []string{"id", "name"}, names)
+					require.Equal(t, "test_table", stmt.Table())
+					if tt.copyErr != nil {
+						return 0, tt.copyErr
+					}
+					return int64(len(models)), nil
+				},
+			}
+			repo, err := newRepository[model](exec, "test_table", func(m *model, builder *ColumnBuilder[model]) {
+				builder.Field(&m.ID)
+				builder.Field(&m.Name)
+			}, WithAfterInsertMany[model](func(ctx context.Context, models []*model) error {
+				afterCalls++
+				return ErrTest
+			}))
+			require.NoError(t, err)
+
+			n, err := repo.BulkCopy(context.Background(), tt.models)
+			require.Equal(t, tt.copyCalls, copyCalls)
+			require.Equal(t, tt.afterCalls, afterCalls)
+			switch {
+			case tt.expectedErr != nil:
+				require.ErrorIs(t, err, tt.expectedErr)
+			case tt.afterCalls > 0:
+				require.ErrorIs(t, err, ErrTest, "after hook error must surface")
+				require.Equal(t, int64(len(tt.models)), n)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRepository_UpdateMany(t *testing.T) {
+	type model struct {
+		ID   int
+		Name string
+	}
+	ErrTest := errors.New("test error")
+	withKey := func(m *model, h query.UpdateManyHelper[model]) { h.Key(&m.ID) }
+
+	tests := []struct {
+		name        string
+		models      []*model
+		qFns        []func(m *model, h query.UpdateManyHelper[model])
+		updated     int64
+		beforeErr   error
+		expectedErr error
+		execCalls   int
+		afterCalls  int
+	}{
+		{name: "Empty slice is a no-op"},
+		{
+			name:       "Rows updated",
+			models:     []*model{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			qFns:       []func(m *model, h query.UpdateManyHelper[model]){withKey},
+			updated:    2,
+			execCalls:  1,
+			afterCalls: 1,
+		},
+		{
+			name:        "Before hook error skips the SQL",
+			models:      []*model{{ID: 1, Name: "a"}},
+			qFns:        []func(m *model, h query.UpdateManyHelper[model]){withKey},
+			beforeErr:   ErrTest,
+			expectedErr: ErrTest,
+		},
+		{
+			name:        "No matched rows is ErrNotFound",
+			models:      []*model{{ID: 1, Name: "a"}},
+			qFns:        []func(m *model, h query.UpdateManyHelper[model]){withKey},
+			expectedErr: ErrNotFound,
+			execCalls:   1,
+		},
+		{
+			name:   "Unknown key field fails on apply",
+			models: []*model{{ID: 1, Name: "a"}},
+			qFns: []func(m *model, h query.UpdateManyHelper[model]){func(m *model, h query.UpdateManyHelper[model]) {
+				h.Key(new(int))
+			}},
+			expectedErr: ErrApplyQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCalls, afterCalls := 0, 0
+			exec := &MockExecutor[model]{
+				UpdateManyFunc: func(ctx context.Context, stmt executor.KeyedBatchStmt, models []*model) (int64, error) {
+					execCalls++
+					require.Len(t, stmt.Key(), 1)
+					return tt.updated, nil
+				},
+			}
+			repo, err := newRepository[model](exec, "test_table", func(m *model, builder *ColumnBuilder[model]) {
+				builder.Field(&m.ID)
+				builder.Field(&m.Name)
+			}, WithBeforeUpdateMany[model](func(ctx context.Context, models []*model) error {
+				return tt.beforeErr
+			}), WithAfterUpdateMany[model](func(ctx context.Context, models []*model) error {
+				afterCalls++
+				return nil
+			}))
+			require.NoError(t, err)
+
+			n, err := repo.UpdateMany(context.Background(), tt.models, tt.qFns...)
+			require.Equal(t, tt.execCalls, execCalls)
+			require.Equal(t, tt.afterCalls, afterCalls)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.updated, n)
+		})
+	}
+}
+
+func TestRepository_GetByIDs(t *testing.T) {
+	type model struct {
+		ID   int
+		Name string
+	}
+
+	tests := []struct {
+		name       string
+		keys       []any
+		execCalls  int
+		afterCalls int
+	}{
+		{name: "No keys is a no-op"},
+		{name: "One chunk", keys: make([]any, keyChunkParams), execCalls: 1, afterCalls: 1},
+		{name: "Long lists are chunked", keys: make([]any, keyChunkParams+1), execCalls: 2, afterCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCalls, afterCalls := 0, 0
+			exec := &MockExecutor[model]{
+				GetMultipleFunc: func(ctx context.Context, stmt executor.Stmt) ([]*model, error) {
+					execCalls++
+					return []*model{{ID: execCalls}}, nil
+				},
+			}
+			repo, err := newRepository[model](exec, "test_table", func(m *model, builder *ColumnBuilder[model]) {
+				builder.Field(&m.ID).PrimaryKey()
+				builder.Field(&m.Name)
+			}, WithAfterSelect[model](func(ctx context.Context, models []*model) error {
+				afterCalls++
+				require.Len(t, models, execCalls)
+				return nil
+			}))
+			require.NoError(t, err)
+
+			models, err := repo.GetByIDs(context.Background(), tt.keys...)
+			require.NoError(t, err)
+			require.Len(t, models, tt.execCalls)
+			require.Equal(t, tt.execCalls, execCalls)
+			require.Equal(t, tt.afterCalls, afterCalls)
+		})
+	}
+}
+
+func TestRepository_ConstraintViolation(t *testing.T) {
+	type model struct {
+		ID       int
+		TenantID int
+		Email    string
+	}
+	driverErr := errors.New("duplicate key")
+
+	newRepo := func(t *testing.T, insertErr error, transform func(err error) error) Repository[model] {
+		t.Helper()
+		var opts []Option[model]
+		if transform != nil {
+			opts = append(opts, WithErrorTransformer[model](transform))
+		}
+		repo, err := newRepository[model](&MockExecutor[model]{
+			InsertOneFunc: func(ctx context.Context, stmt executor.Stmt, m *model) error { return insertErr },
+		}, "app.users", func(m *model, builder *ColumnBuilder[model]) {
+			builder.Field(&m.ID)
+			builder.Field(&m.TenantID)
+			builder.Field(&m.Email)
+		}, opts...)
+		require.NoError(t, err)
+		return repo
+	}
+	insert := func(repo Repository[model]) error {
+		return repo.Insert(context.Background(), &model{})
+	}
+
+	t.Run("Field is filled before the error transformer runs", func(t *testing.T) {
+		var seen *ErrUniqueViolation
+		repo := newRepo(t, &ErrUniqueViolation{ConstraintViolation: ConstraintViolation{Table: "users", Column: "tenant_id, email", Err: driverErr}},
+			func(err error) error {
+				require.ErrorAs(t, err, &seen)
+				return err
+			})
+		err := insert(repo)
+		var uv *ErrUniqueViolation
+		require.ErrorAs(t, err, &uv)
+		assert.Equal(t, "TenantID, Email", uv.Field)
+		assert.Equal(t, "TenantID, Email", seen.Field)
+		assert.ErrorIs(t, err, driverErr)
+	})
+
+	t.Run("Violations of other tables are left alone", func(t *testing.T) {
+		err := insert(newRepo(t, &ErrForeignKeyViolation{ConstraintViolation: ConstraintViolation{Table: "orders", Column: "email", Err: driverErr}}, nil))
+		var fkv *ErrForeignKeyViolation
+		require.ErrorAs(t, err, &fkv)
+		assert.Empty(t, fkv.Field)
+	})
+
+	t.Run("A column without a field leaves Field empty", func(t *testing.T) {
+		err := insert(newRepo(t, &ErrNotNullViolation{ConstraintViolation: ConstraintViolation{Table: "users", Column: "created_at", Err: driverErr}}, nil))
+		var nnv *ErrNotNullViolation
+		require.ErrorAs(t, err, &nnv)
+		assert.Empty(t, nnv.Field)
+	})
+}
+
+// bindingExecutor records the BindCache call of the repository.
+type bindingExecutor[TModel any] struct {
+	MockExecutor[TModel]
+	table        string
+	dependencies []string
+}
+
+func (e *bindingExecutor[TModel]) BindCache(table string, dependencies []string) {
+	e.table, e.dependencies = table, dependencies
+}
+
+func TestRepository_CacheDependencies(t *testing.T) {
+	type model struct {
+		ID         int
+		AuthorName string
+		Comments   int
+	}
+	exec := &bindingExecutor[model]{}
+	_, err := newRepository[model](exec, "posts", func(m *model, builder *ColumnBuilder[model]) {
+		builder.Field(&m.ID)
+		builder.Field(&m.AuthorName).WithTable("users").WithColumnName("name")
+		builder.Field(&m.Comments).AsVirtual().
+			Compute("SELECT count(*) FROM comments WHERE comments.post_id = posts.id").
+			DependsOn("comments", "users")
+	}, WithQuery[model](func(m *model, h query.PersistentHelper[model]) {
+		h.LeftJoinOn("users", "users.id = posts.user_id")
+		h.InnerJoinOn("tags AS t", "t.post_id = posts.id")
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "posts", exec.table)
+	assert.Equal(t, []string{"users", "tags", "comments"}, exec.dependencies)
+}
@@ -111,6 +111,9 @@ func (b *SoftDeletionBuilder[TModel]) apply(repo *repository[TModel]) error {
 		if err != nil {
 			return 0, repo.errorTransformer(fmt.Errorf("soft delete: %w: %w", ErrApplyQuery, err))
 		}
+		if repo.version != nil {
+			bumpVersion(stmt, repo.version)
+		}
 
 		// Create new model and set soft deletion fields
 		model := new(TModel)
@@ -125,6 +128,9 @@ func (b *SoftDeletionBuilder[TModel]) apply(repo *repository[TModel]) error {
 		}
 
 		if updatedCount < 1 {
+			if q.ExpectsVersion() {
+				return updatedCount, repo.errorTransformer(ErrStaleVersion)
+			}
 			return updatedCount, repo.errorTransformer(fmt.Errorf("nothing to delete: %w", ErrNotFound))
 		}
 		return updatedCount, nil
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
 	extypes "github.com/insei/gerpo/executor/types"
 	"github.com/insei/gerpo/query"
@@ -29,6 +30,7 @@ func (nopAdapter) QueryContext(context.Context, string, ...any) (extypes.Rows, e
 	return nil, nil
 }
 func (nopAdapter) BeginTx(context.Context) (extypes.Tx, error) { return nil, nil }
+func (nopAdapter) Dialect() dialect.Dialect                    { return dialect.PostgreSQL }
 
 func newSoftRepoBuilder() ColumnsAppender[softModel] {
 	return New[softModel]().Adapter(executor.Adapter(nopAdapter{})).Table("soft_users")
@@ -140,6 +142,7 @@ func (a *softMockAdapter) QueryContext(context.Context, string, ...any) (extypes
 	return nil, nil
 }
 func (a *softMockAdapter) BeginTx(context.Context) (extypes.Tx, error) { return nil, nil }
+func (a *softMockAdapter) Dialect() dialect.Dialect                    { return dialect.PostgreSQL }
 
 // TestWithSoftDeletion_Delete_ExecutesUpdate — exercises the Delete path on a
 // repo with soft deletion configured. The mock adapter records the SQL, so we
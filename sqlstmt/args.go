@@ -1,8 +1,10 @@
 package sqlstmt
 
 import (
+	"context"
 	"strings"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/types"
 )
 
@@ -51,27 +53,45 @@ func collectReturning(storage types.ColumnsStorage, action types.SQLAction) []ty
 	return out
 }
 
-// appendReturning writes ` RETURNING name1, name2` to sb when cols is non-empty.
-// Columns whose Name() is unset (e.g. virtual) are skipped — RETURNING needs
-// real column names, not expressions.
-func appendReturning(sb *strings.Builder, cols []types.Column) {
+// ident renders a table or column name for the ctx dialect, quoting it only
+// when it needs quoting (see dialect.Ident).
+func ident(ctx context.Context, name string) string {
+	return dialect.Ident(dialect.FromContext(ctx), name)
+}
+
+// appendReturning writes the ctx dialect's RETURNING clause for cols to sb,
+// prefixing every name with qualifier. Dialects without one get nothing; the
+// executor reads the columns back itself.
+func appendReturning(ctx context.Context, sb *strings.Builder, qualifier string, cols []types.Column) {
+	if len(cols) == 0 {
+		return
+	}
+	sb.WriteString(dialect.FromContext(ctx).Returning(returnedNames(ctx, qualifier, cols)))
+}
+
+// appendOutput writes the ctx dialect's OUTPUT clause for cols to sb — the
+// mid-statement counterpart of appendReturning. The dialect qualifies the
+// names itself.
+func appendOutput(ctx context.Context, sb *strings.Builder, cols []types.Column) {
 	if len(cols) == 0 {
 		return
 	}
-	first := true
+	sb.WriteString(dialect.FromContext(ctx).Output(returnedNames(ctx, "", cols)))
+}
+
+// returnedNames renders the names of cols, each prefixed with qualifier.
+// Columns whose Name() is unset (e.g. virtual) are skipped — the written rows
+// hand back real columns, not expressions.
+func returnedNames(ctx context.Context, qualifier string, cols []types.Column) []string {
+	names := make([]string, 0, len(cols))
 	for _, c := range cols {
 		name, ok := c.Name()
 		if !ok || name == "" {
 			continue
 		}
-		if first {
-			sb.WriteString(" RETURNING ")
-			first = false
-		} else {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(name)
+		names = append(names, qualifier+ident(ctx, name))
 	}
+	return names
 }
 
 // collectSelectArgs walks the columns in their SELECT order and accumulates
@@ -0,0 +1,51 @@
+package sqlstmt
+
+import (
+	"context"
+
+	"github.com/insei/gerpo/types"
+)
+
+// Copy describes a COPY FROM load: the target table and the INSERT column set
+// streamed for every model. It renders no SQL — the adapter drives COPY
+// through the driver's native API — and has no RETURNING.
+type Copy struct {
+	ctx     context.Context
+	table   string
+	columns types.ExecutionColumns
+	storage types.ColumnsStorage
+}
+
+func NewCopy(ctx context.Context, table string, colStorage types.ColumnsStorage) *Copy {
+	return &Copy{
+		ctx:     ctx,
+		table:   table,
+		columns: colStorage.NewExecutionColumns(ctx, types.SQLActionInsert),
+		storage: colStorage,
+	}
+}
+
+func (c *Copy) Table() string                        { return c.table }
+func (c *Copy) Columns() types.ExecutionColumns      { return c.columns }
+func (c *Copy) ColumnsStorage() types.ColumnsStorage { return c.storage }
+
+// ColumnNames returns the names of the columns COPY writes, in the order
+// Columns().GetModelValues yields their values.
+func (c *Copy) ColumnNames() ([]string, error) {
+	if c.table == "" {
+		return nil, ErrTableIsNoSet
+	}
+	cols := c.columns.GetAll()
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		name, ok := col.Name()
+		if !ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, ErrEmptyColumnsInExecutionSet
+	}
+	return names, nil
+}
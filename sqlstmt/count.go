@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/types"
 )
 
@@ -40,11 +41,15 @@ func (c *Count) SQL(_ ...Option) (string, []any, error) {
 	}
 	sb := strings.Builder{}
 	sb.Grow(96)
-	sb.WriteString("SELECT count(*) over() AS count FROM ")
-	sb.WriteString(c.table)
+	// The window aggregate reports the size of the whole filtered set on
+	// every row; GetList appends the same column when the total is requested
+	// (see GetList.WithTotal).
+	d := dialect.FromContext(c.ctx)
+	sb.WriteString("SELECT " + d.CountOver() + " AS count FROM ")
+	sb.WriteString(dialect.Ident(d, c.table))
 	sb.WriteString(c.join.SQL())
 	sb.WriteString(c.where.SQL())
 	sb.WriteString(c.group.SQL())
-	sb.WriteString(" LIMIT 1")
+	sb.WriteString(d.LimitOffset(1, 0, false))
 	return sb.String(), mergeArgs(c.join.Values(), c.where.Values()), nil
 }
@@ -51,7 +51,7 @@ func (d *Delete) SQL(_ ...Option) (string, []any, error) {
 	sb := strings.Builder{}
 	sb.Grow(96)
 	sb.WriteString("DELETE FROM ")
-	sb.WriteString(d.table)
+	sb.WriteString(ident(d.ctx, d.table))
 	sb.WriteString(d.join.SQL())
 	sb.WriteString(d.where.SQL())
 	return sb.String(), mergeArgs(d.join.Values(), d.where.Values()), nil
@@ -5,4 +5,7 @@ import "fmt"
 var (
 	ErrEmptyColumnsInExecutionSet = fmt.Errorf("empty columns in execution columns set")
 	ErrTableIsNoSet               = fmt.Errorf("table is not set")
+
+	ErrReturningNeedsConflictColumns = fmt.Errorf("multi-row RETURNING with ON CONFLICT requires a column conflict target")
+	ErrUpdateBatchNeedsKey           = fmt.Errorf("batched UPDATE requires key columns to match rows on")
 )
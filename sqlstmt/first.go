@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/types"
 )
 
@@ -63,11 +64,12 @@ func (f *GetFirst) SQL(_ ...Option) (string, []any, error) {
 		sb.WriteString(col.ToSQL(f.ctx))
 	}
 	sb.WriteString(" FROM ")
-	sb.WriteString(f.table)
+	sb.WriteString(ident(f.ctx, f.table))
 	sb.WriteString(f.join.SQL())
 	sb.WriteString(f.where.SQL())
 	sb.WriteString(f.group.SQL())
-	sb.WriteString(f.order.SQL())
-	sb.WriteString(" LIMIT 1")
+	order := f.order.SQL()
+	sb.WriteString(order)
+	sb.WriteString(dialect.FromContext(f.ctx).LimitOffset(1, 0, order != ""))
 	return sb.String(), mergeArgs(collectSelectArgs(columns), f.join.Values(), f.where.Values()), nil
 }
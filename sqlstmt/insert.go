@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 )
 
@@ -14,6 +15,7 @@ type Insert struct {
 	columns   types.ExecutionColumns
 	storage   types.ColumnsStorage
 	returning []types.Column
+	conflict  *sqlpart.ConflictBuilder
 
 	vals *values
 }
@@ -25,6 +27,7 @@ func NewInsert(ctx context.Context, table string, colStorage types.ColumnsStorag
 		columns:   columns,
 		storage:   colStorage,
 		returning: collectReturning(colStorage, types.SQLActionInsert),
+		conflict:  sqlpart.NewConflictBuilder(ctx),
 
 		vals:  newValues(columns),
 		table: table,
@@ -39,6 +42,10 @@ func (i *Insert) ColumnsStorage() types.ColumnsStorage {
 	return i.storage
 }
 
+func (i *Insert) Table() string {
+	return i.table
+}
+
 // ReturningColumns reports the columns that should be scanned back from a
 // RETURNING clause. Empty slice means the executor takes the plain ExecContext
 // path (no rows returned).
@@ -53,6 +60,28 @@ func (i *Insert) SetReturning(cols []types.Column) {
 	i.returning = cols
 }
 
+// Conflict exposes the ON CONFLICT clause — used by the per-request
+// query.InsertHelper.OnConflict(...) spec.
+func (i *Insert) Conflict() sqlpart.Conflict {
+	return i.conflict
+}
+
+// HasConflictClause reports whether the statement renders ON CONFLICT. The
+// executor uses it to tell a row skipped by the conflict rule apart from an
+// INSERT that wrote nothing for another reason.
+func (i *Insert) HasConflictClause() bool {
+	return i.conflict != nil && i.conflict.IsSet()
+}
+
+// ConflictConstraint returns the named constraint of the ON CONFLICT clause,
+// "" when it has none.
+func (i *Insert) ConflictConstraint() string {
+	if i.conflict == nil {
+		return ""
+	}
+	return i.conflict.Constraint()
+}
+
 func (i *Insert) SQL(opts ...Option) (string, []any, error) {
 	for _, opt := range opts {
 		opt(i.vals)
@@ -67,7 +96,7 @@ func (i *Insert) SQL(opts ...Option) (string, []any, error) {
 	sb := strings.Builder{}
 	sb.Grow(128)
 	sb.WriteString("INSERT INTO ")
-	sb.WriteString(i.table)
+	sb.WriteString(ident(i.ctx, i.table))
 	sb.WriteString(" (")
 	lenAtStart := sb.Len()
 	valuesCount := 0
@@ -79,12 +108,21 @@ func (i *Insert) SQL(opts ...Option) (string, []any, error) {
 		if sb.Len() > lenAtStart {
 			sb.WriteString(", ")
 		}
-		sb.WriteString(colName)
+		sb.WriteString(ident(i.ctx, colName))
 		valuesCount++
 	}
-	sb.WriteString(") VALUES (")
+	sb.WriteString(")")
+	appendOutput(i.ctx, &sb, i.returning)
+	sb.WriteString(" VALUES (")
 	valuesSQLTemplate := strings.Repeat("?,", valuesCount)
 	sb.WriteString(valuesSQLTemplate[:len(valuesSQLTemplate)-1] + ")")
-	appendReturning(&sb, i.returning)
-	return sb.String(), i.vals.values, nil
+	vals := i.vals.values
+	if i.conflict != nil {
+		sb.WriteString(i.conflict.SQL())
+		if conflictVals := i.conflict.Values(); len(conflictVals) > 0 {
+			vals = mergeArgs(vals, conflictVals)
+		}
+	}
+	appendReturning(i.ctx, &sb, "", i.returning)
+	return sb.String(), vals, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 )
 
@@ -17,6 +18,7 @@ type InsertBatch struct {
 	columns   types.ExecutionColumns
 	storage   types.ColumnsStorage
 	returning []types.Column
+	conflict  *sqlpart.ConflictBuilder
 	models    []any
 }
 
@@ -28,20 +30,55 @@ func NewInsertBatch(ctx context.Context, table string, colStorage types.ColumnsS
 		columns:   columns,
 		storage:   colStorage,
 		returning: collectReturning(colStorage, types.SQLActionInsert),
+		conflict:  sqlpart.NewConflictBuilder(ctx),
 	}
 }
 
 func (b *InsertBatch) Columns() types.ExecutionColumns      { return b.columns }
 func (b *InsertBatch) ColumnsStorage() types.ColumnsStorage { return b.storage }
 
+func (b *InsertBatch) Table() string { return b.table }
+
 // ReturningColumns reports the columns that should appear in RETURNING. Empty
 // means the executor stays on the plain ExecContext path for this batch.
-func (b *InsertBatch) ReturningColumns() []types.Column { return b.returning }
+//
+// With an ON CONFLICT clause the conflict target columns are appended when the
+// caller did not ask for them: rows skipped by DO NOTHING are missing from the
+// result, so the executor matches returned rows back to models by that key
+// instead of by position.
+func (b *InsertBatch) ReturningColumns() []types.Column {
+	if len(b.returning) == 0 || !b.HasConflictClause() {
+		return b.returning
+	}
+	cols := b.returning
+	for _, target := range b.conflict.TargetColumns() {
+		if !containsColumn(cols, target) {
+			cols = append(cols[:len(cols):len(cols)], target)
+		}
+	}
+	return cols
+}
 
 // SetReturning replaces the returning column set — used by the per-request
 // query.InsertManyHelper.Returning(...) override.
 func (b *InsertBatch) SetReturning(cols []types.Column) { b.returning = cols }
 
+// Conflict exposes the ON CONFLICT clause — used by the per-request
+// query.InsertManyHelper.OnConflict(...) spec. The clause is shared by every
+// chunk the executor renders.
+func (b *InsertBatch) Conflict() sqlpart.Conflict { return b.conflict }
+
+// HasConflictClause reports whether the statement renders ON CONFLICT.
+func (b *InsertBatch) HasConflictClause() bool { return b.conflict.IsSet() }
+
+// ConflictTarget returns the columns of the ON CONFLICT target — the key the
+// executor uses to pair RETURNING rows with the models that produced them.
+func (b *InsertBatch) ConflictTarget() []types.Column { return b.conflict.TargetColumns() }
+
+// ConflictConstraint returns the named constraint of the ON CONFLICT clause,
+// "" when it has none.
+func (b *InsertBatch) ConflictConstraint() string { return b.conflict.Constraint() }
+
 // SetModels gives the stmt the rows for the next SQL() call. The executor
 // calls it once per chunk and then calls SQL() to emit that chunk's statement.
 func (b *InsertBatch) SetModels(models []any) { b.models = models }
@@ -59,6 +96,9 @@ func (b *InsertBatch) SQL(_ ...Option) (string, []any, error) {
 	if len(b.models) == 0 {
 		return "", nil, nil
 	}
+	if b.HasConflictClause() && len(b.returning) > 0 && len(b.conflict.TargetColumns()) == 0 {
+		return "", nil, ErrReturningNeedsConflictColumns
+	}
 
 	// Column names for INSERT (...) clause. Virtual columns skip (no Name()).
 	var names []string
@@ -67,7 +107,7 @@ func (b *InsertBatch) SQL(_ ...Option) (string, []any, error) {
 		if !ok {
 			continue
 		}
-		names = append(names, name)
+		names = append(names, ident(b.ctx, name))
 	}
 	valsPerRow := len(names)
 	if valsPerRow == 0 {
@@ -79,10 +119,12 @@ func (b *InsertBatch) SQL(_ ...Option) (string, []any, error) {
 	sb := strings.Builder{}
 	sb.Grow(64 + len(names)*8 + len(b.models)*(valsPerRow*3+4))
 	sb.WriteString("INSERT INTO ")
-	sb.WriteString(b.table)
+	sb.WriteString(ident(b.ctx, b.table))
 	sb.WriteString(" (")
 	sb.WriteString(strings.Join(names, ", "))
-	sb.WriteString(") VALUES ")
+	sb.WriteString(")")
+	appendOutput(b.ctx, &sb, b.ReturningColumns())
+	sb.WriteString(" VALUES ")
 
 	allValues := make([]any, 0, len(b.models)*valsPerRow)
 	for i, m := range b.models {
@@ -92,6 +134,19 @@ func (b *InsertBatch) SQL(_ ...Option) (string, []any, error) {
 		sb.WriteString(rowTemplate)
 		allValues = append(allValues, b.columns.GetModelValues(m)...)
 	}
-	appendReturning(&sb, b.returning)
+	sb.WriteString(b.conflict.SQL())
+	if conflictVals := b.conflict.Values(); len(conflictVals) > 0 {
+		allValues = append(allValues, conflictVals...)
+	}
+	appendReturning(b.ctx, &sb, "", b.ReturningColumns())
 	return sb.String(), allValues, nil
 }
+
+func containsColumn(cols []types.Column, col types.Column) bool {
+	for _, c := range cols {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
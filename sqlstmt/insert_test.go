@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -140,3 +141,67 @@ func TestInsert_SQL(t *testing.T) {
 		})
 	}
 }
+
+func TestInsert_SQL_OnConflict(t *testing.T) {
+	id := &mockColumn{name: "id", hasName: true, allowedAction: true}
+	name := &mockColumn{name: "name", hasName: true, allowedAction: true}
+
+	testCases := []struct {
+		name           string
+		setup          func(insert *Insert)
+		expectedSQL    string
+		expectedValues []any
+		expectConflict bool
+	}{
+		{
+			name:        "Without conflict spec",
+			setup:       func(insert *Insert) {},
+			expectedSQL: "INSERT INTO products (id, name) VALUES (?,?)",
+		},
+		{
+			name: "DO NOTHING",
+			setup: func(insert *Insert) {
+				insert.Conflict().OnColumns(id)
+				insert.Conflict().DoNothing()
+			},
+			expectedSQL:    "INSERT INTO products (id, name) VALUES (?,?) ON CONFLICT (id) DO NOTHING",
+			expectConflict: true,
+		},
+		{
+			name: "DO UPDATE with WHERE and RETURNING",
+			setup: func(insert *Insert) {
+				insert.Conflict().OnColumns(id)
+				insert.Conflict().DoUpdate(name)
+				insert.Conflict().Where().AppendSQLWithValues("products.name != ?", true, "locked")
+				insert.SetReturning([]types.Column{id})
+			},
+			expectedSQL:    "INSERT INTO products (id, name) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name WHERE products.name != ? RETURNING id",
+			expectedValues: []any{1, "chair", "locked"},
+			expectConflict: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			columns := newMockExecutionColumns([]types.Column{id, name})
+			columns.modelValues = []any{1, "chair"}
+			insert := &Insert{
+				ctx:      ctx,
+				table:    "products",
+				columns:  columns,
+				conflict: sqlpart.NewConflictBuilder(ctx),
+				vals:     newValues(columns),
+			}
+			tc.setup(insert)
+
+			sql, values, err := insert.SQL(WithModelValues(struct{}{}))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			if tc.expectedValues != nil {
+				assert.Equal(t, tc.expectedValues, values)
+			}
+			assert.Equal(t, tc.expectConflict, insert.HasConflictClause())
+		})
+	}
+}
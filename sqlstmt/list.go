@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 )
@@ -16,6 +17,7 @@ type GetList struct {
 	table       string
 	columns     types.ExecutionColumns
 	limitOffset *sqlpart.LimitOffsetBuilder
+	withTotal   bool
 }
 
 var getListPool = sync.Pool{
@@ -32,8 +34,8 @@ func NewGetList(ctx context.Context, table string, colStorage types.ColumnsStora
 	f.ctx = ctx
 	f.table = table
 	f.columns = colStorage.NewExecutionColumns(ctx, types.SQLActionSelect)
-	f.limitOffset.SetLimit(0)
-	f.limitOffset.SetOffset(0)
+	f.limitOffset.Reset(ctx)
+	f.withTotal = false
 	f.sqlselect.reset(ctx, colStorage)
 	return f
 }
@@ -55,6 +57,13 @@ func (f *GetList) LimitOffset() sqlpart.LimitOffset {
 	return f.limitOffset
 }
 
+// WithTotal appends the count(*) over() window column after the model
+// columns, so every row also carries the size of the whole filtered set —
+// the page and its total come from one statement and one snapshot.
+func (f *GetList) WithTotal() {
+	f.withTotal = true
+}
+
 func (f *GetList) SQL(_ ...Option) (string, []any, error) {
 	if f.table == "" {
 		return "", nil, ErrTableIsNoSet
@@ -72,12 +81,16 @@ func (f *GetList) SQL(_ ...Option) (string, []any, error) {
 		}
 		sb.WriteString(col.ToSQL(f.ctx))
 	}
+	if f.withTotal {
+		sb.WriteString(", " + dialect.FromContext(f.ctx).CountOver() + " AS count")
+	}
 	sb.WriteString(" FROM ")
-	sb.WriteString(f.table)
+	sb.WriteString(ident(f.ctx, f.table))
 	sb.WriteString(f.join.SQL())
 	sb.WriteString(f.where.SQL())
 	sb.WriteString(f.group.SQL())
-	sb.WriteString(f.order.SQL())
-	sb.WriteString(f.limitOffset.SQL())
+	order := f.order.SQL()
+	sb.WriteString(order)
+	sb.WriteString(f.limitOffset.SQL(order != ""))
 	return sb.String(), mergeArgs(collectSelectArgs(columns), f.join.Values(), f.where.Values()), nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -107,3 +108,38 @@ func TestGetList_SQL(t *testing.T) {
 		})
 	}
 }
+
+func TestGetList_SQL_WithTotal(t *testing.T) {
+	storage := newMockStorage([]types.Column{
+		&mockColumn{name: "id", hasName: true},
+		&mockColumn{name: "name", hasName: true},
+	})
+	gl := NewGetList(context.Background(), "users", storage)
+	gl.WithTotal()
+	gl.LimitOffset().SetLimit(10)
+	gl.LimitOffset().SetOffset(20)
+
+	sql, _, err := gl.SQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, count(*) over() AS count FROM users LIMIT 10 OFFSET 20", sql)
+
+	gl.Release()
+	gl = NewGetList(context.Background(), "users", storage)
+	sql, _, err = gl.SQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users", sql, "WithTotal must not survive the pool")
+}
+
+func TestGetList_SQL_Dialect(t *testing.T) {
+	storage := newMockStorage([]types.Column{
+		&mockColumn{name: "id", hasName: true},
+	})
+	ctx := dialect.WithContext(context.Background(), dialect.MySQL)
+	gl := NewGetList(ctx, "order", storage)
+	defer gl.Release()
+	gl.LimitOffset().SetOffset(20)
+
+	sql, _, err := gl.SQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM `order` LIMIT 18446744073709551615 OFFSET 20", sql)
+}
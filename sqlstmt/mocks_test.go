@@ -44,6 +44,17 @@ func (m *mockExecutionColumns) GetAll() []types.Column {
 	return m.columns
 }
 
+func (m *mockExecutionColumns) Exclude(cols ...types.Column) {
+	for _, c := range cols {
+		for i, col := range m.columns {
+			if col == c {
+				m.columns = append(m.columns[:i:i], m.columns[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
 func (m *mockExecutionColumns) GetModelValues(model any) []any {
 	return m.modelValues
 }
@@ -0,0 +1,162 @@
+package sqlpart
+
+import (
+	"context"
+	"strings"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/types"
+)
+
+// Conflict is the interface gerpo's query layer talks to when turning an
+// INSERT into an upsert via an ON CONFLICT clause.
+type Conflict interface {
+	// OnColumns sets the conflict target to the given columns — rendered as
+	// ON CONFLICT (a, b). Calling it with no columns leaves the target empty,
+	// which PostgreSQL only accepts together with DO NOTHING.
+	OnColumns(cols ...types.Column)
+	// OnConstraint sets the conflict target to a named constraint — rendered
+	// as ON CONFLICT ON CONSTRAINT name.
+	OnConstraint(name string)
+	// DoNothing skips the conflicting row.
+	DoNothing()
+	// DoUpdate overwrites the listed columns of the conflicting row with the
+	// values proposed for insertion (EXCLUDED.col).
+	DoUpdate(cols ...types.Column)
+	// Where narrows DO UPDATE to rows matching the condition; rows that do
+	// not match are left untouched and reported as not affected.
+	Where() Where
+}
+
+type conflictAction uint8
+
+const (
+	conflictActionNone conflictAction = iota
+	conflictActionNothing
+	conflictActionUpdate
+)
+
+type ConflictBuilder struct {
+	ctx        context.Context
+	enabled    bool
+	targetCols []types.Column
+	target     []string
+	constraint string
+	action     conflictAction
+	set        []string
+	where      *WhereBuilder
+}
+
+func NewConflictBuilder(ctx context.Context) *ConflictBuilder {
+	return &ConflictBuilder{
+		ctx:   ctx,
+		where: NewWhereBuilder(ctx),
+	}
+}
+
+func (b *ConflictBuilder) OnColumns(cols ...types.Column) {
+	b.enabled = true
+	b.constraint = ""
+	b.targetCols = b.targetCols[:0]
+	b.target = b.target[:0]
+	for _, col := range cols {
+		name, ok := col.Name()
+		if !ok || name == "" {
+			continue
+		}
+		b.targetCols = append(b.targetCols, col)
+		b.target = append(b.target, dialect.Ident(dialect.FromContext(b.ctx), name))
+	}
+}
+
+func (b *ConflictBuilder) OnConstraint(name string) {
+	b.enabled = true
+	b.targetCols = b.targetCols[:0]
+	b.target = b.target[:0]
+	b.constraint = strings.TrimSpace(name)
+}
+
+func (b *ConflictBuilder) DoNothing() {
+	b.action = conflictActionNothing
+	b.set = b.set[:0]
+}
+
+func (b *ConflictBuilder) DoUpdate(cols ...types.Column) {
+	b.action = conflictActionUpdate
+	b.set = b.set[:0]
+	for _, col := range cols {
+		name, ok := col.Name()
+		if !ok || name == "" {
+			continue
+		}
+		b.set = append(b.set, dialect.Ident(dialect.FromContext(b.ctx), name))
+	}
+}
+
+func (b *ConflictBuilder) Where() Where {
+	return b.where
+}
+
+// IsSet reports whether an ON CONFLICT clause was configured.
+func (b *ConflictBuilder) IsSet() bool {
+	return b.enabled && b.action != conflictActionNone
+}
+
+// TargetColumns returns the column conflict target. It is empty when the
+// clause targets a named constraint or has no target at all.
+func (b *ConflictBuilder) TargetColumns() []types.Column {
+	if !b.IsSet() {
+		return nil
+	}
+	return b.targetCols
+}
+
+// Constraint returns the named constraint the clause targets, "" when it
+// targets columns or nothing.
+func (b *ConflictBuilder) Constraint() string {
+	if !b.IsSet() {
+		return ""
+	}
+	return b.constraint
+}
+
+// Values returns the bound arguments of the DO UPDATE ... WHERE condition.
+// They follow the VALUES arguments in the final []any.
+func (b *ConflictBuilder) Values() []any {
+	if !b.IsSet() || b.action != conflictActionUpdate || len(b.set) == 0 {
+		return nil
+	}
+	return b.where.Values()
+}
+
+func (b *ConflictBuilder) SQL() string {
+	if !b.IsSet() {
+		return ""
+	}
+	sb := strings.Builder{}
+	sb.WriteString(" ON CONFLICT")
+	switch {
+	case b.constraint != "":
+		sb.WriteString(" ON CONSTRAINT ")
+		sb.WriteString(dialect.Ident(dialect.FromContext(b.ctx), b.constraint))
+	case len(b.target) > 0:
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(b.target, ", "))
+		sb.WriteString(")")
+	}
+	if b.action == conflictActionNothing || len(b.set) == 0 {
+		sb.WriteString(" DO NOTHING")
+		return sb.String()
+	}
+	sb.WriteString(" DO UPDATE SET ")
+	for i, name := range b.set {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name)
+		sb.WriteString(" = EXCLUDED.")
+		sb.WriteString(name)
+	}
+	sb.WriteString(b.where.SQL())
+	return sb.String()
+}
@@ -0,0 +1,107 @@
+package sqlpart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/insei/gerpo/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func (m *MockColumn) Name() (string, bool) { return m.name, m.name != "" }
+
+func TestConflictBuilder_SQL(t *testing.T) {
+	id := &MockColumn{name: "id"}
+	email := &MockColumn{name: "email"}
+	name := &MockColumn{name: "name"}
+
+	testCases := []struct {
+		name         string
+		setup        func(b *ConflictBuilder)
+		expectedSQL  string
+		expectedVals []any
+	}{
+		{
+			name:        "Not configured",
+			setup:       func(b *ConflictBuilder) {},
+			expectedSQL: "",
+		},
+		{
+			name:        "Target without action renders nothing",
+			setup:       func(b *ConflictBuilder) { b.OnColumns(id) },
+			expectedSQL: "",
+		},
+		{
+			name: "DO NOTHING without target",
+			setup: func(b *ConflictBuilder) {
+				b.OnColumns()
+				b.DoNothing()
+			},
+			expectedSQL: " ON CONFLICT DO NOTHING",
+		},
+		{
+			name: "DO NOTHING on columns",
+			setup: func(b *ConflictBuilder) {
+				b.OnColumns(id, email)
+				b.DoNothing()
+			},
+			expectedSQL: " ON CONFLICT (id, email) DO NOTHING",
+		},
+		{
+			name: "DO UPDATE on constraint",
+			setup: func(b *ConflictBuilder) {
+				b.OnConstraint("users_email_key")
+				b.DoUpdate(name)
+			},
+			expectedSQL: " ON CONFLICT ON CONSTRAINT users_email_key DO UPDATE SET name = EXCLUDED.name",
+		},
+		{
+			name: "DO UPDATE with WHERE",
+			setup: func(b *ConflictBuilder) {
+				b.OnColumns(id)
+				b.DoUpdate(email, name)
+				b.Where().AppendSQLWithValues("users.name != ?", true, "root")
+			},
+			expectedSQL:  " ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, name = EXCLUDED.name WHERE users.name != ?",
+			expectedVals: []any{"root"},
+		},
+		{
+			name: "Last target wins",
+			setup: func(b *ConflictBuilder) {
+				b.OnConstraint("users_pkey")
+				b.OnColumns(email)
+				b.DoNothing()
+			},
+			expectedSQL: " ON CONFLICT (email) DO NOTHING",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewConflictBuilder(context.Background())
+			tc.setup(b)
+			assert.Equal(t, tc.expectedSQL, b.SQL())
+			assert.Equal(t, tc.expectedVals, b.Values())
+		})
+	}
+}
+
+func TestConflictBuilder_IsSet(t *testing.T) {
+	b := NewConflictBuilder(context.Background())
+	assert.False(t, b.IsSet())
+	b.OnColumns(&MockColumn{name: "id"})
+	assert.False(t, b.IsSet())
+	b.DoUpdate([]types.Column{&MockColumn{name: "name"}}...)
+	assert.True(t, b.IsSet())
+}
+
+func TestConflictBuilder_TargetColumns(t *testing.T) {
+	id := &MockColumn{name: "id"}
+	b := NewConflictBuilder(context.Background())
+	b.OnColumns(id)
+	assert.Nil(t, b.TargetColumns())
+	b.DoNothing()
+	assert.Equal(t, []types.Column{id}, b.TargetColumns())
+	b.OnConstraint("users_pkey")
+	assert.Empty(t, b.TargetColumns())
+}
@@ -0,0 +1,108 @@
+package sqlpart
+
+import (
+	"fmt"
+
+	"github.com/insei/gerpo/types"
+)
+
+// AppendKeysetCondition appends, as its own group, the predicate selecting the
+// rows that sort strictly after values under the ORDER BY described by cols
+// and directions. A uniform direction renders the row-value comparison
+// (a, b) > (?, ?); mixed ASC/DESC expands it into
+// (a > ? OR (a = ? AND b < ?)).
+func (b *WhereBuilder) AppendKeysetCondition(cols []types.Column, directions []types.OrderDirection, values []any) error {
+	if len(cols) == 0 || len(cols) != len(directions) || len(cols) != len(values) {
+		return fmt.Errorf("keyset condition: %d columns, %d directions and %d values", len(cols), len(directions), len(values))
+	}
+	exprs := make([]string, len(cols))
+	for i, col := range cols {
+		exprs[i] = col.ToSQL(b.ctx)
+		if exprs[i] == "" {
+			return fmt.Errorf("keyset condition: column %s has no SQL expression", col.GetField().GetStructPath())
+		}
+	}
+	b.StartGroup()
+	if len(cols) == 1 || uniformDirection(directions) {
+		op := keysetOperator(directions[0])
+		if len(cols) == 1 {
+			b.sql = append(b.sql, exprs[0]...)
+		} else {
+			b.sql = append(b.sql, '(')
+			for i, e := range exprs {
+				if i > 0 {
+					b.sql = append(b.sql, ", "...)
+				}
+				b.sql = append(b.sql, e...)
+			}
+			b.sql = append(b.sql, ')')
+		}
+		b.sql = append(b.sql, ' ')
+		b.sql = append(b.sql, op...)
+		b.sql = append(b.sql, ' ')
+		if len(cols) == 1 {
+			b.sql = append(b.sql, '?')
+		} else {
+			b.sql = append(b.sql, '(')
+			for i := range cols {
+				if i > 0 {
+					b.sql = append(b.sql, ", "...)
+				}
+				b.sql = append(b.sql, '?')
+			}
+			b.sql = append(b.sql, ')')
+		}
+		for _, col := range cols {
+			b.appendColumnSQLArgs(col)
+		}
+		for _, v := range values {
+			b.values = append(b.values, v)
+		}
+		b.EndGroup()
+		return nil
+	}
+	for i := range cols {
+		if i > 0 {
+			b.OR()
+		}
+		b.sql = append(b.sql, '(')
+		for j := 0; j < i; j++ {
+			b.sql = append(b.sql, exprs[j]...)
+			b.sql = append(b.sql, " = ? AND "...)
+			b.appendColumnSQLArgs(cols[j])
+			b.values = append(b.values, values[j])
+		}
+		b.sql = append(b.sql, exprs[i]...)
+		b.sql = append(b.sql, ' ')
+		b.sql = append(b.sql, keysetOperator(directions[i])...)
+		b.sql = append(b.sql, " ?)"...)
+		b.appendColumnSQLArgs(cols[i])
+		b.values = append(b.values, values[i])
+	}
+	b.EndGroup()
+	return nil
+}
+
+func (b *WhereBuilder) appendColumnSQLArgs(col types.Column) {
+	if ap, ok := col.(columnSQLArgsProvider); ok {
+		b.values = append(b.values, ap.SQLArgs()...)
+	}
+}
+
+func uniformDirection(directions []types.OrderDirection) bool {
+	for _, d := range directions[1:] {
+		if d != directions[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// keysetOperator picks the comparison that moves past the cursor row: greater
+// for ascending order, less for descending.
+func keysetOperator(direction types.OrderDirection) string {
+	if direction == types.OrderDirectionDESC {
+		return "<"
+	}
+	return ">"
+}
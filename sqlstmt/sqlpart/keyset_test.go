@@ -0,0 +1,81 @@
+package sqlpart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insei/gerpo/types"
+)
+
+func TestWhereBuilder_AppendKeysetCondition(t *testing.T) {
+	a := &MockColumn{name: "a", allowedAction: true}
+	b := &MockColumn{name: "b", allowedAction: true}
+	c := &MockColumn{name: "c", allowedAction: true}
+	asc, desc := types.OrderDirectionASC, types.OrderDirectionDESC
+
+	testCases := []struct {
+		name           string
+		prefix         string
+		cols           []types.Column
+		directions     []types.OrderDirection
+		values         []any
+		expectedSQL    string
+		expectedValues []any
+	}{
+		{
+			name:           "Single ASC column",
+			cols:           []types.Column{a},
+			directions:     []types.OrderDirection{asc},
+			values:         []any{1},
+			expectedSQL:    " WHERE (a > ?)",
+			expectedValues: []any{1},
+		},
+		{
+			name:           "Uniform DESC uses row-value comparison",
+			cols:           []types.Column{a, b},
+			directions:     []types.OrderDirection{desc, desc},
+			values:         []any{1, "x"},
+			expectedSQL:    " WHERE ((a, b) < (?, ?))",
+			expectedValues: []any{1, "x"},
+		},
+		{
+			name:           "Mixed directions expand into OR chain",
+			cols:           []types.Column{a, b, c},
+			directions:     []types.OrderDirection{desc, asc, asc},
+			values:         []any{1, 2, 3},
+			expectedSQL:    " WHERE ((a < ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND c > ?))",
+			expectedValues: []any{1, 1, 2, 1, 2, 3},
+		},
+		{
+			name:           "ANDed after existing conditions",
+			prefix:         "(x = ?)",
+			cols:           []types.Column{a},
+			directions:     []types.OrderDirection{desc},
+			values:         []any{1},
+			expectedSQL:    " WHERE (x = ?) AND (a < ?)",
+			expectedValues: []any{0, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := NewWhereBuilder(context.Background())
+			if tc.prefix != "" {
+				builder.AppendSQLWithValues(tc.prefix, true, 0)
+			}
+			require.NoError(t, builder.AppendKeysetCondition(tc.cols, tc.directions, tc.values))
+			assert.Equal(t, tc.expectedSQL, builder.SQL())
+			assert.Equal(t, tc.expectedValues, builder.Values())
+		})
+	}
+}
+
+func TestWhereBuilder_AppendKeysetCondition_MismatchedInput(t *testing.T) {
+	builder := NewWhereBuilder(context.Background())
+	err := builder.AppendKeysetCondition([]types.Column{&MockColumn{name: "a"}}, []types.OrderDirection{types.OrderDirectionASC}, nil)
+	assert.Error(t, err)
+	assert.Empty(t, builder.SQL())
+}
@@ -1,8 +1,10 @@
 package sqlpart
 
 import (
+	"context"
 	"strconv"
-	"strings"
+
+	"github.com/insei/gerpo/dialect"
 )
 
 type LimitOffset interface {
@@ -13,6 +15,7 @@ type LimitOffset interface {
 }
 
 type LimitOffsetBuilder struct {
+	ctx    context.Context
 	offset uint64
 	limit  uint64
 }
@@ -21,6 +24,14 @@ func NewLimitOffsetBuilder() *LimitOffsetBuilder {
 	return &LimitOffsetBuilder{}
 }
 
+// Reset clears the limit and offset and binds the builder to ctx, whose
+// dialect renders the clause.
+func (p *LimitOffsetBuilder) Reset(ctx context.Context) {
+	p.ctx = ctx
+	p.offset = 0
+	p.limit = 0
+}
+
 func (p *LimitOffsetBuilder) GetOffset() uint64 {
 	return p.offset
 }
@@ -51,15 +62,8 @@ func (p *LimitOffsetBuilder) getLimitStr() string {
 	return strconv.FormatUint(p.GetLimit(), 10)
 }
 
-func (p *LimitOffsetBuilder) SQL() string {
-	sb := strings.Builder{}
-	limitNumStr := p.getLimitStr()
-	if strings.TrimSpace(limitNumStr) != "" {
-		sb.WriteString(" LIMIT " + limitNumStr)
-	}
-	offsetNumStr := p.getOffsetStr()
-	if strings.TrimSpace(offsetNumStr) != "" {
-		sb.WriteString(" OFFSET " + offsetNumStr)
-	}
-	return sb.String()
+// SQL renders the clause in the dialect of the context passed to Reset;
+// ordered reports whether the statement has an ORDER BY already.
+func (p *LimitOffsetBuilder) SQL(ordered bool) string {
+	return dialect.FromContext(p.ctx).LimitOffset(p.limit, p.offset, ordered)
 }
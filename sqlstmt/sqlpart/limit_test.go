@@ -116,7 +116,7 @@ func TestLimitOffsetBuilder_SQL(t *testing.T) {
 			builder.SetLimit(tt.limit)
 			builder.SetOffset(tt.offset)
 
-			result := builder.SQL()
+			result := builder.SQL(false)
 			if result != tt.expect {
 				t.Errorf("SQL() = %v, want %v", result, tt.expect)
 			}
@@ -15,6 +15,9 @@ type Where interface {
 	OR()
 	AppendSQLWithValues(sql string, appendValue bool, value any)
 	AppendCondition(cl types.Column, operation types.Operation, val any) error
+	// AppendKeysetCondition appends the keyset pagination predicate "the row
+	// sorts strictly after values" for the given ORDER BY columns.
+	AppendKeysetCondition(cols []types.Column, directions []types.OrderDirection, values []any) error
 }
 
 type WhereBuilder struct {
@@ -19,6 +19,16 @@ type Update struct {
 	columns     types.ExecutionColumns
 	returning   []types.Column
 	where       *sqlpart.WhereBuilder
+
+	assignments []assignment
+	version     *assignment
+}
+
+// assignment is one explicit `name = expr` entry of the SET clause.
+type assignment struct {
+	col  types.Column
+	expr string
+	args []any
 }
 
 func NewUpdate(ctx context.Context, colStorage types.ColumnsStorage, table string) *Update {
@@ -49,10 +59,19 @@ func (u *Update) SetReturning(cols []types.Column) {
 	u.returning = cols
 }
 
+// Ctx returns the request-scoped context the statement was created with.
+func (u *Update) Ctx() context.Context {
+	return u.ctx
+}
+
 func (u *Update) ColumnsStorage() types.ColumnsStorage {
 	return u.colsStorage
 }
 
+func (u *Update) Table() string {
+	return u.table
+}
+
 func (u *Update) Columns() types.ExecutionColumns {
 	return u.columns
 }
@@ -61,10 +80,42 @@ func (u *Update) Where() sqlpart.Where {
 	return u.where
 }
 
+// AppendAssignment adds `name = expr` to the SET clause, expr being the
+// right-hand side with ? placeholders for args. A second assignment to the
+// same column replaces the first one. Once any assignment is set, the SET
+// clause is made of the assignments only — model values are not bound.
+func (u *Update) AppendAssignment(col types.Column, expr string, args ...any) {
+	for i := range u.assignments {
+		if u.assignments[i].col == col {
+			u.assignments[i] = assignment{col: col, expr: expr, args: args}
+			return
+		}
+	}
+	u.assignments = append(u.assignments, assignment{col: col, expr: expr, args: args})
+}
+
+// SetVersion makes col the optimistic-locking version column of the statement:
+// it is taken out of the model-bound column set and always rendered last in
+// the SET clause as `name = expr`. The caller adds the matching WHERE
+// condition for the current version.
+func (u *Update) SetVersion(col types.Column, expr string, args ...any) {
+	u.columns.Exclude(col)
+	u.version = &assignment{col: col, expr: expr, args: args}
+}
+
+// HasAssignments reports whether the SET clause comes from AppendAssignment
+// rather than from the model.
+func (u *Update) HasAssignments() bool {
+	return len(u.assignments) > 0
+}
+
 func (u *Update) SQL(opts ...Option) (string, []any, error) {
 	if u.table == "" {
 		return "", nil, ErrTableIsNoSet
 	}
+	if len(u.assignments) > 0 {
+		return u.assignmentsSQL()
+	}
 	cols := u.columns.GetAll()
 	if len(cols) < 1 {
 		return "", nil, ErrEmptyColumnsInExecutionSet
@@ -72,7 +123,7 @@ func (u *Update) SQL(opts ...Option) (string, []any, error) {
 	sb := strings.Builder{}
 	sb.Grow(128)
 	sb.WriteString("UPDATE ")
-	sb.WriteString(u.table)
+	sb.WriteString(ident(u.ctx, u.table))
 	sb.WriteString(" SET ")
 	lenAtStart := sb.Len()
 	for _, col := range cols {
@@ -83,16 +134,65 @@ func (u *Update) SQL(opts ...Option) (string, []any, error) {
 		if sb.Len() > lenAtStart {
 			sb.WriteString(", ")
 		}
-		sb.WriteString(colName + " = ?")
+		sb.WriteString(ident(u.ctx, colName) + " = ?")
+	}
+	if u.version != nil {
+		name, _ := u.version.col.Name()
+		if sb.Len() > lenAtStart {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(ident(u.ctx, name) + " = " + u.version.expr)
 	}
 	if sb.Len() == lenAtStart {
 		return "", nil, fmt.Errorf("columns set is not empty, but no one column is not allowed to set")
 	}
+	appendOutput(u.ctx, &sb, u.returning)
 	sb.WriteString(u.where.SQL())
-	appendReturning(&sb, u.returning)
+	appendReturning(u.ctx, &sb, "", u.returning)
 	for _, opt := range opts {
 		opt(u.vals)
 	}
-	vals := append(u.vals.values, u.where.Values()...)
+	vals := u.vals.values
+	if u.version != nil {
+		vals = append(vals, u.version.args...)
+	}
+	vals = append(vals, u.where.Values()...)
 	return sb.String(), vals, nil
 }
+
+// assignmentsSQL renders the UPDATE from the explicit assignments. Their args
+// come first, the WHERE args after them.
+func (u *Update) assignmentsSQL() (string, []any, error) {
+	sb := strings.Builder{}
+	sb.Grow(128)
+	sb.WriteString("UPDATE ")
+	sb.WriteString(ident(u.ctx, u.table))
+	sb.WriteString(" SET ")
+	var vals []any
+	assignments := u.assignments
+	if u.version != nil {
+		// The version bump wins over an explicit assignment to the same column.
+		assignments = make([]assignment, 0, len(u.assignments)+1)
+		for _, a := range u.assignments {
+			if a.col != u.version.col {
+				assignments = append(assignments, a)
+			}
+		}
+		assignments = append(assignments, *u.version)
+	}
+	for i, a := range assignments {
+		colName, ok := a.col.Name()
+		if !ok {
+			return "", nil, fmt.Errorf("column is not allowed to set")
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(ident(u.ctx, colName) + " = " + a.expr)
+		vals = append(vals, a.args...)
+	}
+	appendOutput(u.ctx, &sb, u.returning)
+	sb.WriteString(u.where.SQL())
+	appendReturning(u.ctx, &sb, "", u.returning)
+	return sb.String(), append(vals, u.where.Values()...), nil
+}
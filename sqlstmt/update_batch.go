@@ -0,0 +1,230 @@
+package sqlstmt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/sqlstmt/sqlpart"
+	"github.com/insei/gerpo/types"
+)
+
+// UpdateBatch emits one UPDATE for a chunk of models, joining the table with
+// the chunk rows on the key columns:
+//
+//	UPDATE t SET a = v.a, b = v.b
+//	FROM (SELECT id, a, b FROM t WHERE false UNION ALL SELECT ?, ?, ? UNION ALL ...) AS v
+//	WHERE t.id = v.id [AND ...] [RETURNING ...]
+//
+// The rows travel as a UNION ALL behind an empty SELECT from the table rather
+// than as a bare VALUES list: bind parameters in VALUES have no type context
+// and PostgreSQL resolves them to text, while the leading SELECT types every
+// column after the table itself.
+//
+// Dialects without UPDATE ... FROM (MySQL) get the same rows joined in with
+// UPDATE t JOIN (...) AS v ON t.id = v.id SET t.a = v.a [WHERE ...].
+//
+// With a version column (SetVersion) each row also matches on the version of
+// its model, t.version = v.version, and SETs version = v.version + 1.
+//
+// As with InsertBatch, the executor feeds chunks through SetModels and caps
+// them at the placeholder limit; this stmt only renders what it was given.
+type UpdateBatch struct {
+	ctx       context.Context
+	table     string
+	columns   types.ExecutionColumns
+	storage   types.ColumnsStorage
+	returning []types.Column
+	key       []types.Column
+	version   types.Column
+	where     *sqlpart.WhereBuilder
+	models    []any
+}
+
+func NewUpdateBatch(ctx context.Context, table string, colStorage types.ColumnsStorage) *UpdateBatch {
+	return &UpdateBatch{
+		ctx:       ctx,
+		table:     table,
+		columns:   colStorage.NewExecutionColumns(ctx, types.SQLActionUpdate),
+		storage:   colStorage,
+		returning: collectReturning(colStorage, types.SQLActionUpdate),
+		where:     sqlpart.NewWhereBuilder(ctx),
+	}
+}
+
+func (b *UpdateBatch) Columns() types.ExecutionColumns      { return b.columns }
+func (b *UpdateBatch) ColumnsStorage() types.ColumnsStorage { return b.storage }
+
+func (b *UpdateBatch) Table() string        { return b.table }
+func (b *UpdateBatch) Where() sqlpart.Where { return b.where }
+
+// SetKey sets the columns rows are matched on — used by the per-request
+// query.UpdateManyHelper.Key(...) spec. Key columns are never SET.
+func (b *UpdateBatch) SetKey(cols []types.Column) { b.key = cols }
+
+// SetVersion makes col the optimistic-locking version column of the
+// statement: every row matches on the version its model holds and SETs the
+// next one. col always travels with the rows, whatever the column set.
+func (b *UpdateBatch) SetVersion(col types.Column) {
+	b.columns.Exclude(col)
+	cols := b.columns.GetAll()
+	b.columns.Only(append(cols[:len(cols):len(cols)], col)...)
+	b.version = col
+}
+
+// Key returns the columns rows are matched on. The executor pairs RETURNING
+// rows with models by their values, since the database returns the updated
+// rows in no particular order and skips models whose row does not exist.
+func (b *UpdateBatch) Key() []types.Column { return b.key }
+
+// ReturningColumns reports the columns that should appear in RETURNING, with
+// the key columns appended when the caller did not ask for them. Empty means
+// the executor stays on the plain ExecContext path for this batch.
+func (b *UpdateBatch) ReturningColumns() []types.Column {
+	if len(b.returning) == 0 {
+		return nil
+	}
+	cols := b.returning
+	for _, k := range b.key {
+		if !containsColumn(cols, k) {
+			cols = append(cols[:len(cols):len(cols)], k)
+		}
+	}
+	return cols
+}
+
+// SetReturning replaces the returning column set — used by the per-request
+// query.UpdateManyHelper.Returning(...) override.
+func (b *UpdateBatch) SetReturning(cols []types.Column) { b.returning = cols }
+
+// SetModels gives the stmt the rows for the next SQL() call. The executor
+// calls it once per chunk and then calls SQL() to emit that chunk's statement.
+func (b *UpdateBatch) SetModels(models []any) { b.models = models }
+
+// SQL builds the UPDATE for the models currently held by SetModels.
+func (b *UpdateBatch) SQL(_ ...Option) (string, []any, error) {
+	if b.table == "" {
+		return "", nil, ErrTableIsNoSet
+	}
+	if len(b.key) == 0 {
+		return "", nil, ErrUpdateBatchNeedsKey
+	}
+	if len(b.models) == 0 {
+		return "", nil, nil
+	}
+
+	keyNames := make([]string, 0, len(b.key))
+	for _, k := range b.key {
+		name, ok := k.Name()
+		if !ok || name == "" {
+			return "", nil, fmt.Errorf("%w: key column must be a table column", ErrUpdateBatchNeedsKey)
+		}
+		keyNames = append(keyNames, ident(b.ctx, name))
+	}
+	// Key columns stay as they are; virtual columns have nothing to SET.
+	var setCols []types.Column
+	var setNames []string
+	for _, col := range b.columns.GetAll() {
+		name, ok := col.Name()
+		if !ok || containsColumn(b.key, col) || col == b.version {
+			continue
+		}
+		setCols = append(setCols, col)
+		setNames = append(setNames, ident(b.ctx, name))
+	}
+	if len(setCols) == 0 {
+		return "", nil, ErrEmptyColumnsInExecutionSet
+	}
+
+	rowCols := append(b.key[:len(b.key):len(b.key)], setCols...)
+	rowNames := append(keyNames[:len(keyNames):len(keyNames)], setNames...)
+	var versionName string
+	if b.version != nil {
+		name, _ := b.version.Name()
+		versionName = ident(b.ctx, name)
+		rowCols = append(rowCols, b.version)
+		rowNames = append(rowNames, versionName)
+	}
+	valsPerRow := len(rowCols)
+	rowTemplate := " UNION ALL SELECT " + strings.TrimRight(strings.Repeat("?, ", valsPerRow), ", ")
+
+	d := dialect.FromContext(b.ctx)
+	updateFrom := d.SupportsUpdateFrom()
+	table := dialect.Ident(d, b.table)
+	sb := strings.Builder{}
+	sb.Grow(128 + len(setNames)*24 + len(b.models)*(len(rowTemplate)))
+	sb.WriteString("UPDATE ")
+	sb.WriteString(table)
+	if updateFrom {
+		b.writeSet(&sb, setNames, versionName)
+		appendOutput(b.ctx, &sb, b.ReturningColumns())
+		sb.WriteString(" FROM")
+	} else {
+		sb.WriteString(" JOIN")
+	}
+	sb.WriteString(" (SELECT ")
+	sb.WriteString(strings.Join(rowNames, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(table)
+	sb.WriteString(" WHERE " + d.BoolLiteral(false))
+
+	allValues := make([]any, 0, len(b.models)*valsPerRow+len(b.where.Values()))
+	for _, m := range b.models {
+		sb.WriteString(rowTemplate)
+		for _, col := range rowCols {
+			allValues = append(allValues, col.GetField().Get(m))
+		}
+	}
+	if updateFrom {
+		sb.WriteString(") AS v WHERE ")
+	} else {
+		sb.WriteString(") AS v ON ")
+	}
+	for i, name := range keyNames {
+		if i > 0 {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString(table + "." + name + " = v." + name)
+	}
+	if versionName != "" {
+		sb.WriteString(" AND " + table + "." + versionName + " = v." + versionName)
+	}
+	if !updateFrom {
+		b.writeSet(&sb, setNames, versionName)
+	}
+	if where := b.where.SQL(); where != "" {
+		if updateFrom {
+			sb.WriteString(" AND (")
+		} else {
+			sb.WriteString(" WHERE (")
+		}
+		sb.WriteString(strings.TrimPrefix(where, " WHERE "))
+		sb.WriteString(")")
+		allValues = append(allValues, b.where.Values()...)
+	}
+	// The joined rows carry the same column names, so the returned ones are
+	// table-qualified.
+	appendReturning(b.ctx, &sb, table+".", b.ReturningColumns())
+	return sb.String(), allValues, nil
+}
+
+// writeSet writes the SET clause, the bump of versionName last when set. In
+// the JOIN form both sides carry the same column names, so the target is
+// table-qualified there.
+func (b *UpdateBatch) writeSet(sb *strings.Builder, setNames []string, versionName string) {
+	target := ""
+	if !dialect.FromContext(b.ctx).SupportsUpdateFrom() {
+		target = ident(b.ctx, b.table) + "."
+	}
+	sb.WriteString(" SET ")
+	for i, name := range setNames {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(target + name + " = v." + name)
+	}
+	if versionName != "" {
+		sb.WriteString(", " + target + versionName + " = v." + versionName + " + 1")
+	}
+}
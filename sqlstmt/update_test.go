@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/sqlstmt/sqlpart"
 	"github.com/insei/gerpo/types"
 	"github.com/stretchr/testify/assert"
@@ -111,3 +112,61 @@ func TestUpdate_SQL(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate_SQL_Assignments(t *testing.T) {
+	name := &mockColumn{name: "name", hasName: true, allowedAction: true}
+	hits := &mockColumn{name: "hits", hasName: true, allowedAction: true}
+	ctx := context.Background()
+	u := NewUpdate(ctx, newMockStorage([]types.Column{name, hits}), "users")
+	u.AppendAssignment(name, "?", "bob")
+	u.AppendAssignment(hits, "hits + ?", 1)
+	u.AppendAssignment(name, "upper(?)", "alice") // replaces the first one
+	u.where.AppendSQLWithValues("id = ?", true, 7)
+
+	sqlStr, vals, err := u.SQL(WithModelValues(struct{}{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = upper(?), hits = hits + ? WHERE id = ?", sqlStr)
+	assert.Equal(t, []any{"alice", 1, 7}, vals)
+}
+
+func TestUpdate_SQL_Version(t *testing.T) {
+	name := &mockColumn{name: "name", hasName: true, allowedAction: true}
+	version := &mockColumn{name: "version", hasName: true, allowedAction: true}
+	ctx := context.Background()
+
+	u := NewUpdate(ctx, newMockStorage([]types.Column{name, version}), "users")
+	u.SetVersion(version, "?", 4)
+	u.where.AppendSQLWithValues("version = ?", true, 3)
+	sqlStr, vals, err := u.SQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, version = ? WHERE version = ?", sqlStr)
+	assert.Equal(t, []any{4, 3}, vals)
+
+	// Assignments: the version bump replaces an explicit assignment to the column.
+	u = NewUpdate(ctx, newMockStorage([]types.Column{name, version}), "users")
+	u.AppendAssignment(version, "?", 100)
+	u.AppendAssignment(name, "?", "bob")
+	u.SetVersion(version, "version + 1")
+	sqlStr, vals, err = u.SQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, version = version + 1", sqlStr)
+	assert.Equal(t, []any{"bob"}, vals)
+}
+
+func TestUpdate_SQL_QuotesReservedNames(t *testing.T) {
+	key := &mockColumn{name: "key", hasName: true, allowedAction: true}
+	ctx := dialect.WithContext(context.Background(), dialect.MySQL)
+	u := NewUpdate(ctx, newMockStorage([]types.Column{key}), "user")
+	u.SetReturning([]types.Column{key})
+
+	sqlStr, vals, err := u.SQL(WithModelValues(struct{}{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `user` SET `key` = ?", sqlStr, "MySQL has no RETURNING")
+	assert.Empty(t, vals)
+
+	u = NewUpdate(context.Background(), newMockStorage([]types.Column{key}), "user")
+	u.SetReturning([]types.Column{key})
+	sqlStr, _, err = u.SQL(WithModelValues(struct{}{}))
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "user" SET "key" = ? RETURNING "key"`, sqlStr)
+}
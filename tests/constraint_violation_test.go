@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	pgconnv4 "github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintViolation_DatabaseSQL(t *testing.T) {
+	type User struct {
+		ID    int
+		Email string
+	}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "pgx v5 stdlib",
+			err:  &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", TableName: "users", Detail: "Key (email)=(a@b.c) already exists."},
+		},
+		{
+			name: "pgx v4 stdlib",
+			err:  &pgconnv4.PgError{Code: "23505", ConstraintName: "users_email_key", TableName: "users", Detail: "Key (email)=(a@b.c) already exists."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mockDB, err := sqlmock.New()
+			require.NoError(t, err)
+			repo, err := gerpo.New[User]().
+				Adapter(databasesql.NewAdapter(db)).
+				Table("users").
+				Columns(func(m *User, columns *gerpo.ColumnBuilder[User]) {
+					columns.Field(&m.ID)
+					columns.Field(&m.Email)
+				}).
+				Build()
+			require.NoError(t, err)
+			mockDB.ExpectExec(`INSERT INTO users`).WillReturnError(tt.err)
+
+			err = repo.Insert(context.Background(), &User{ID: 1, Email: "a@b.c"})
+			var uv *gerpo.ErrUniqueViolation
+			require.ErrorAs(t, err, &uv)
+			assert.Equal(t, "users_email_key", uv.Constraint)
+			assert.Equal(t, "users", uv.Table)
+			assert.Equal(t, "email", uv.Column)
+			assert.Equal(t, "Email", uv.Field)
+			assert.ErrorIs(t, err, tt.err)
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
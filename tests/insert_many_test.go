@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertMany_Upsert(t *testing.T) {
+	type Item struct {
+		SKU     string
+		Name    string
+		Version int
+	}
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Item]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("items").
+		Columns(func(m *Item, columns *gerpo.ColumnBuilder[Item]) {
+			columns.Field(&m.SKU)
+			columns.Field(&m.Name)
+			columns.Field(&m.Version).OmitOnInsert().OmitOnUpdate()
+		}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("RETURNING rows are matched by conflict target, not position", func(t *testing.T) {
+		items := []*Item{{SKU: "a", Name: "A"}, {SKU: "b", Name: "B"}, {SKU: "c", Name: "C"}}
+		// "b" is skipped by DO NOTHING and the remaining rows come back reversed.
+		mockDB.ExpectQuery(`INSERT INTO items \(sku, name\) VALUES \(\?,\?\), \(\?,\?\), \(\?,\?\) ON CONFLICT \(sku\) DO NOTHING RETURNING version, sku`).
+			WithArgs("a", "A", "b", "B", "c", "C").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "sku"}).AddRow(3, "c").AddRow(1, "a"))
+
+		n, err := repo.InsertMany(context.Background(), items, func(m *Item, h query.InsertManyHelper[Item]) {
+			h.OnConflict(&m.SKU).DoNothing()
+			h.Returning(&m.Version)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, 1, items[0].Version)
+		assert.Equal(t, 0, items[1].Version)
+		assert.Equal(t, 3, items[2].Version)
+	})
+
+	t.Run("RETURNING rows with a folded key are paired by position when none is skipped", func(t *testing.T) {
+		items := []*Item{{SKU: "a", Name: "A"}, {SKU: "b", Name: "B"}}
+		// A case-insensitive column hands "B" back for "b".
+		mockDB.ExpectQuery(`INSERT INTO items \(sku, name\) VALUES \(\?,\?\), \(\?,\?\) ON CONFLICT \(sku\) DO NOTHING RETURNING version, sku`).
+			WithArgs("a", "A", "b", "B").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "sku"}).AddRow(1, "a").AddRow(2, "B"))
+
+		n, err := repo.InsertMany(context.Background(), items, func(m *Item, h query.InsertManyHelper[Item]) {
+			h.OnConflict(&m.SKU).DoNothing()
+			h.Returning(&m.Version)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, 1, items[0].Version)
+		assert.Equal(t, 2, items[1].Version)
+		assert.Equal(t, "B", items[1].SKU)
+	})
+
+	t.Run("RETURNING rows with a folded key fail when a row is skipped", func(t *testing.T) {
+		items := []*Item{{SKU: "a", Name: "A"}, {SKU: "b", Name: "B"}}
+		mockDB.ExpectQuery(`INSERT INTO items \(sku, name\) VALUES \(\?,\?\), \(\?,\?\) ON CONFLICT \(sku\) DO NOTHING RETURNING version, sku`).
+			WithArgs("a", "A", "b", "B").
+			WillReturnRows(sqlmock.NewRows([]string{"version", "sku"}).AddRow(2, "B"))
+
+		_, err := repo.InsertMany(context.Background(), items, func(m *Item, h query.InsertManyHelper[Item]) {
+			h.OnConflict(&m.SKU).DoNothing()
+			h.Returning(&m.Version)
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("DO UPDATE with WHERE binds its args after the rows", func(t *testing.T) {
+		items := []*Item{{SKU: "a", Name: "A"}, {SKU: "b", Name: "B"}}
+		mockDB.ExpectExec(`INSERT INTO items \(sku, name\) VALUES \(\?,\?\), \(\?,\?\) ON CONFLICT \(sku\) DO UPDATE SET name = EXCLUDED.name WHERE \(items.name != \?\)`).
+			WithArgs("a", "A", "b", "B", "locked").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		n, err := repo.InsertMany(context.Background(), items, func(m *Item, h query.InsertManyHelper[Item]) {
+			h.OnConflict(&m.SKU).DoUpdate().Where().Field(&m.Name).NotEQ("locked")
+			h.Returning()
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	})
+
+	t.Run("RETURNING needs a column conflict target", func(t *testing.T) {
+		items := []*Item{{SKU: "a", Name: "A"}}
+		_, err := repo.InsertMany(context.Background(), items, func(m *Item, h query.InsertManyHelper[Item]) {
+			h.OnConflictConstraint("items_sku_key").DoNothing()
+			h.Returning(&m.Version)
+		})
+		assert.Error(t, err)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -118,6 +118,36 @@ func TestInsert(t *testing.T) {
 				})
 			},
 		},
+		{
+			name: "Upsert DO UPDATE overwrites updatable columns outside the target",
+			model: &User{
+				Name: "UpsertTest",
+			},
+			setupDb: func(mockDB sqlmock.Sqlmock, dateAt time.Time, m *User) {
+				mockDB.ExpectExec(`INSERT INTO users \(id, created_at, name\) VALUES \(\?,\?,\?\) ON CONFLICT \(id\) DO UPDATE SET name = EXCLUDED.name`).
+					WithArgs(sqlmock.AnyArg(), &dateAt, m.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			repoInsertFn: func(repo gerpo.Repository[User], user *User) error {
+				return repo.Upsert(context.Background(), user, func(m *User, h query.InsertHelper[User]) {
+					h.OnConflict(&m.ID).DoUpdate()
+				})
+			},
+		},
+		{
+			name: "Upsert DO UPDATE with WHERE",
+			model: &User{
+				Name: "UpsertTest",
+			},
+			setupDb: func(mockDB sqlmock.Sqlmock, dateAt time.Time, m *User) {
+				mockDB.ExpectExec(`INSERT INTO users \(id, created_at, name\) VALUES \(\?,\?,\?\) ON CONFLICT \(id\) DO UPDATE SET name = EXCLUDED.name WHERE \(users.deleted_at IS NULL\)`).
+					WithArgs(sqlmock.AnyArg(), &dateAt, m.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			repoInsertFn: func(repo gerpo.Repository[User], user *User) error {
+				return repo.Upsert(context.Background(), user, func(m *User, h query.InsertHelper[User]) {
+					h.OnConflict(&m.ID).DoUpdate(&m.Name).Where().Field(&m.DeletedAt).EQ(nil)
+				})
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -3,6 +3,7 @@
 package integration
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/insei/gerpo/executor"
@@ -10,6 +11,7 @@ import (
 	"github.com/insei/gerpo/executor/adapters/pgx4"
 	"github.com/insei/gerpo/executor/adapters/pgx5"
 	"github.com/insei/gerpo/executor/adapters/placeholder"
+	"github.com/insei/gerpo/executor/adapters/sqlite"
 )
 
 // adapterBundle содержит один из поддерживаемых gerpo Adapter и его имя.
@@ -17,25 +19,38 @@ import (
 type adapterBundle struct {
 	name    string
 	adapter executor.Adapter
+	// db — прямой доступ к той же БД в обход gerpo: сиды, проверки состояния,
+	// DDL тестовых таблиц. Плейсхолдеры `$1, $2, …` понимают обе БД.
+	db *sql.DB
+	// copy — адаптер умеет BulkCopy (COPY FROM есть только у pgx).
+	copy bool
 }
 
-// allAdapters возвращает конструкторы для всех трёх поддерживаемых адаптеров.
-// Коннекты уже открыты в TestMain — здесь только оборачиваем их.
+// allAdapters возвращает конструкторы для всех поддерживаемых адаптеров.
+// Коннекты уже открыты в TestMain — здесь только оборачиваем их. PostgreSQL-
+// адаптеры участвуют, только если задан GERPO_INTEGRATION_DB_URL.
 func allAdapters() []func() adapterBundle {
-	return []func() adapterBundle{
-		func() adapterBundle {
-			return adapterBundle{name: "pgx5", adapter: pgx5.NewPoolAdapter(pgx5Pool)}
-		},
-		func() adapterBundle {
-			return adapterBundle{name: "pgx4", adapter: pgx4.NewPoolAdapter(pgx4Pool)}
-		},
-		func() adapterBundle {
-			return adapterBundle{
-				name:    "databasesql",
-				adapter: databasesql.NewAdapter(stdlibDB, databasesql.WithPlaceholder(placeholder.Dollar)),
-			}
-		},
+	var adapters []func() adapterBundle
+	if dsn != "" {
+		adapters = append(adapters,
+			func() adapterBundle {
+				return adapterBundle{name: "pgx5", adapter: pgx5.NewPoolAdapter(pgx5Pool), db: stdlibDB, copy: true}
+			},
+			func() adapterBundle {
+				return adapterBundle{name: "pgx4", adapter: pgx4.NewPoolAdapter(pgx4Pool), db: stdlibDB, copy: true}
+			},
+			func() adapterBundle {
+				return adapterBundle{
+					name:    "databasesql",
+					adapter: databasesql.NewAdapter(stdlibDB, databasesql.WithPlaceholder(placeholder.Dollar)),
+					db:      stdlibDB,
+				}
+			},
+		)
 	}
+	return append(adapters, func() adapterBundle {
+		return adapterBundle{name: "sqlite", adapter: sqlite.NewAdapter(sqliteDB), db: sqliteDB}
+	})
 }
 
 // forEachAdapter запускает fn как sub-test для каждого адаптера gerpo.
@@ -46,7 +61,7 @@ func forEachAdapter(t *testing.T, fn func(t *testing.T, ab adapterBundle)) {
 	for _, make := range allAdapters() {
 		ab := make()
 		t.Run(ab.name, func(t *testing.T) {
-			truncateAll(t)
+			truncateAll(t, ab)
 			fn(t, ab)
 		})
 	}
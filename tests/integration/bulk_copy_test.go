@@ -0,0 +1,81 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBulkCopy_RoundTrip — COPY FROM на pgx-адаптерах записывает все строки,
+// database/sql и sqlite честно отвечают ErrCopyNotSupported.
+func TestBulkCopy_RoundTrip(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		author := seed.users[0].ID
+		batch := make([]*Post, 0, 500)
+		for i := 0; i < cap(batch); i++ {
+			batch = append(batch, &Post{
+				ID:        uuid.New(),
+				UserID:    author,
+				Title:     fmt.Sprintf("copy-%d", i),
+				Content:   "c",
+				CreatedAt: nowUTC(),
+			})
+		}
+
+		n, err := repo.BulkCopy(ctx, batch)
+		if !ab.copy {
+			require.ErrorIs(t, err, gerpo.ErrCopyNotSupported)
+			return
+		}
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(batch)), n)
+
+		got, err := repo.GetFirst(ctx, func(p *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&p.ID).EQ(batch[42].ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "copy-42", got.Title)
+	})
+}
+
+// TestBulkCopy_InTx — COPY внутри gerpo.RunInTx идёт через транзакцию и
+// откатывается вместе с ней.
+func TestBulkCopy_InTx(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		if !ab.copy {
+			t.Skipf("%s adapter has no COPY support", ab.name)
+		}
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		m := &Post{ID: uuid.New(), UserID: seed.users[0].ID, Title: "copy-tx", Content: "c", CreatedAt: nowUTC()}
+		rollback := fmt.Errorf("rollback")
+		err := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			if _, err := repo.BulkCopy(ctx, []*Post{m}); err != nil {
+				return err
+			}
+			return rollback
+		})
+		require.ErrorIs(t, err, rollback)
+
+		_, err = repo.GetFirst(ctx, func(p *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&p.ID).EQ(m.ID)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrNotFound)
+	})
+}
@@ -10,16 +10,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/insei/gerpo"
 	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/cache"
 	cachectx "github.com/insei/gerpo/executor/cache/ctx"
+	"github.com/insei/gerpo/executor/cache/lru"
 	"github.com/insei/gerpo/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// newCachedPostRepo собирает Post-репозиторий, подключённый к Cache.
-func newCachedPostRepo(t *testing.T, ab adapterBundle, cache *cachectx.Cache) gerpo.Repository[Post] {
+// newCachedPostRepo собирает Post-репозиторий, подключённый к cache, с
+// persistent-запросами queryFns.
+func newCachedPostRepo(t *testing.T, ab adapterBundle, cache cache.Storage, queryFns ...func(m *Post, h query.PersistentHelper[Post])) gerpo.Repository[Post] {
 	t.Helper()
-	repo, err := gerpo.New[Post]().
+	b := gerpo.New[Post]().
 		Adapter(ab.adapter, executor.WithCacheStorage(cache)).
 		Table("posts").
 		Columns(func(m *Post, c *gerpo.ColumnBuilder[Post]) {
@@ -30,17 +33,25 @@ func newCachedPostRepo(t *testing.T, ab adapterBundle, cache *cachectx.Cache) ge
 			c.Field(&m.Published)
 			c.Field(&m.PublishedAt)
 			c.Field(&m.CreatedAt).OmitOnUpdate()
-		}).
-		Build()
+		})
+	for _, fn := range queryFns {
+		b.WithQuery(fn)
+	}
+	repo, err := b.Build()
 	require.NoError(t, err)
 	return repo
 }
 
+// joinAuthors делает posts-репо зависимым от users.
+func joinAuthors(_ *Post, h query.PersistentHelper[Post]) {
+	h.LeftJoinOn("users", "users.id = posts.user_id")
+}
+
 // TestCache_HitReturnsStaleValue — повторный GetFirst в том же контексте
 // возвращает закешированное значение, даже если БД изменилась извне.
 func TestCache_HitReturnsStaleValue(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		cache := cachectx.New()
 		repo := newCachedPostRepo(t, ab, cache)
 
@@ -57,7 +68,7 @@ func TestCache_HitReturnsStaleValue(t *testing.T) {
 		assert.Equal(t, target.Title, first.Title)
 
 		// Меняем заголовок напрямую в БД — кеш не должен заметить.
-		_, err = pgx5Pool.Exec(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "external-update", target.ID)
+		_, err = ab.db.ExecContext(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "external-update", target.ID)
 		require.NoError(t, err)
 
 		second, err := repo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
@@ -72,7 +83,7 @@ func TestCache_HitReturnsStaleValue(t *testing.T) {
 // видит актуальные данные.
 func TestCache_InvalidatedOnInsert(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		cache := cachectx.New()
 		repo := newCachedPostRepo(t, ab, cache)
 
@@ -88,7 +99,7 @@ func TestCache_InvalidatedOnInsert(t *testing.T) {
 		require.NoError(t, err)
 
 		// Внешнее изменение в БД.
-		_, err = pgx5Pool.Exec(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "after-insert-cleans", target.ID)
+		_, err = ab.db.ExecContext(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "after-insert-cleans", target.ID)
 		require.NoError(t, err)
 
 		// Insert нового поста через репо — чистит кеш.
@@ -114,7 +125,7 @@ func TestCache_InvalidatedOnInsert(t *testing.T) {
 // просто не срабатывает, ошибки наружу не просачиваются.
 func TestCache_WithoutMiddleware_WorksAsMiss(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		cache := cachectx.New()
 		repo := newCachedPostRepo(t, ab, cache)
 
@@ -131,16 +142,139 @@ func TestCache_WithoutMiddleware_WorksAsMiss(t *testing.T) {
 	})
 }
 
-// TestCache_WriteOneRepoInvalidatesOther — Update через users-репо должен
-// инвалидировать кеш posts-репо, который привязан к тому же ctx. Раньше это
-// было не так (per-repo Clean), см. gerpo-review.txt пункт 2.2.
+// TestCache_WriteOneRepoInvalidatesOther — Update через users-репо
+// инвалидирует кеш posts-репо в том же ctx, только если posts от users зависит:
+// через JOIN или явный WithUndeclaredDependencies. Иначе кеш posts остаётся.
 func TestCache_WriteOneRepoInvalidatesOther(t *testing.T) {
+	cases := []struct {
+		name        string
+		postCache   func(shared *cachectx.Cache) cache.Storage
+		queryFns    []func(m *Post, h query.PersistentHelper[Post])
+		invalidated bool
+	}{
+		{
+			name:        "join",
+			postCache:   func(shared *cachectx.Cache) cache.Storage { return shared },
+			queryFns:    []func(m *Post, h query.PersistentHelper[Post]){joinAuthors},
+			invalidated: true,
+		},
+		{
+			name:        "no dependency",
+			postCache:   func(shared *cachectx.Cache) cache.Storage { return shared },
+			invalidated: false,
+		},
+		{
+			name: "undeclared dependencies",
+			postCache: func(*cachectx.Cache) cache.Storage {
+				return cachectx.New(cachectx.WithUndeclaredDependencies())
+			},
+			invalidated: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+				seed := defaultSeed(t, ab)
+				shared := cachectx.New()
+
+				userRepo, err := gerpo.New[User]().
+					Adapter(ab.adapter, executor.WithCacheStorage(shared)).
+					Table("users").
+					Columns(func(m *User, c *gerpo.ColumnBuilder[User]) {
+						c.Field(&m.ID).OmitOnUpdate()
+						c.Field(&m.Name)
+						c.Field(&m.Email)
+						c.Field(&m.Age)
+						c.Field(&m.CreatedAt).OmitOnUpdate()
+						c.Field(&m.UpdatedAt).OmitOnInsert()
+						c.Field(&m.DeletedAt).OmitOnInsert()
+					}).
+					Build()
+				require.NoError(t, err)
+
+				postRepo := newCachedPostRepo(t, ab, tc.postCache(shared), tc.queryFns...)
+
+				baseCtx, cancel := testCtx(t)
+				defer cancel()
+				ctx := cachectx.WrapContext(baseCtx)
+
+				targetUser := seed.users[0]
+				targetPost := seed.posts[0]
+
+				// Прогреваем кеш posts-репо.
+				_, err = postRepo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+					h.Where().Field(&m.ID).EQ(targetPost.ID)
+				})
+				require.NoError(t, err)
+
+				// Меняем пост снаружи, чтобы кеш и реальность разошлись.
+				_, err = ab.db.ExecContext(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "after-cross-invalidation", targetPost.ID)
+				require.NoError(t, err)
+
+				updated := targetUser
+				updated.Age = targetUser.Age + 1
+				_, err = userRepo.Update(ctx, &updated, func(m *User, h query.UpdateHelper[User]) {
+					h.Where().Field(&m.ID).EQ(updated.ID)
+				})
+				require.NoError(t, err)
+
+				got, err := postRepo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+					h.Where().Field(&m.ID).EQ(targetPost.ID)
+				})
+				require.NoError(t, err)
+				if tc.invalidated {
+					assert.Equal(t, "after-cross-invalidation", got.Title,
+						"write through userRepo must invalidate postRepo's cache depending on users")
+				} else {
+					assert.Equal(t, targetPost.Title, got.Title,
+						"write through userRepo must keep the cache of postRepo not depending on users")
+				}
+			})
+		})
+	}
+}
+
+// TestCache_DifferentContextsDoNotShare — разные контексты имеют независимый кеш.
+func TestCache_DifferentContextsDoNotShare(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
-		shared := cachectx.New()
+		seed := defaultSeed(t, ab)
+		cache := cachectx.New()
+		repo := newCachedPostRepo(t, ab, cache)
+
+		base, cancel := testCtx(t)
+		defer cancel()
+		ctx1 := cachectx.WrapContext(base)
+		ctx2 := cachectx.WrapContext(base)
+
+		target := seed.posts[0]
+		_, err := repo.GetFirst(ctx1, func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(target.ID)
+		})
+		require.NoError(t, err)
+
+		// Изменяем название извне.
+		_, err = ab.db.ExecContext(context.Background(), `UPDATE posts SET title = 'ctx2-sees' WHERE id = $1`, target.ID)
+		require.NoError(t, err)
+
+		// Запрос во втором контексте — кеш чистый, должен увидеть новое значение.
+		got, err := repo.GetFirst(ctx2, func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(target.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ctx2-sees", got.Title, "independent cache in the second context")
+	})
+}
+
+// TestCache_LRU_SharedAcrossContexts — lru.Store переживает контекст запроса:
+// второй запрос получает закешированное значение, а запись через users-репо,
+// от которого posts-репо объявил зависимость, инвалидирует его кеш.
+func TestCache_LRU_SharedAcrossContexts(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		store := lru.NewStore()
 
 		userRepo, err := gerpo.New[User]().
-			Adapter(ab.adapter, executor.WithCacheStorage(shared)).
+			Adapter(ab.adapter, executor.WithCacheStorage(store.Cache("users"))).
 			Table("users").
 			Columns(func(m *User, c *gerpo.ColumnBuilder[User]) {
 				c.Field(&m.ID).OmitOnUpdate()
@@ -153,28 +287,28 @@ func TestCache_WriteOneRepoInvalidatesOther(t *testing.T) {
 			}).
 			Build()
 		require.NoError(t, err)
+		postRepo := newCachedPostRepo(t, ab, store.Cache("posts", lru.WithDependencies("users")))
 
-		postRepo := newCachedPostRepo(t, ab, shared)
-
-		baseCtx, cancel := testCtx(t)
+		ctx, cancel := testCtx(t)
 		defer cancel()
-		ctx := cachectx.WrapContext(baseCtx)
 
 		targetUser := seed.users[0]
 		targetPost := seed.posts[0]
+		getPost := func(ctx context.Context) *Post {
+			got, err := postRepo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+				h.Where().Field(&m.ID).EQ(targetPost.ID)
+			})
+			require.NoError(t, err)
+			return got
+		}
 
-		// Прогреваем кеш обоих репо.
-		_, err = postRepo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
-			h.Where().Field(&m.ID).EQ(targetPost.ID)
-		})
+		getPost(context.WithoutCancel(ctx))
+		_, err = ab.db.ExecContext(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "after-lru-invalidation", targetPost.ID)
 		require.NoError(t, err)
 
-		// Меняем пост снаружи, чтобы кеш и реальность разошлись.
-		_, err = pgx5Pool.Exec(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "after-cross-invalidation", targetPost.ID)
-		require.NoError(t, err)
+		// Другой контекст — тот же кеш.
+		assert.Equal(t, targetPost.Title, getPost(ctx).Title, "cached result must outlive the request context")
 
-		// Update через users-репо — должен очистить весь per-ctx кеш, в том
-		// числе postRepo's.
 		updated := targetUser
 		updated.Age = targetUser.Age + 1
 		_, err = userRepo.Update(ctx, &updated, func(m *User, h query.UpdateHelper[User]) {
@@ -182,44 +316,80 @@ func TestCache_WriteOneRepoInvalidatesOther(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		// Следующий GetFirst по posts должен увидеть внешнее изменение —
-		// кеш очищен записью через другой репо в том же контексте.
-		got, err := postRepo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
-			h.Where().Field(&m.ID).EQ(targetPost.ID)
-		})
-		require.NoError(t, err)
-		assert.Equal(t, "after-cross-invalidation", got.Title,
-			"write through userRepo must invalidate postRepo's cache in the shared context")
+		assert.Equal(t, "after-lru-invalidation", getPost(ctx).Title,
+			"write through userRepo must invalidate the posts depending on users")
 	})
 }
 
-// TestCache_DifferentContextsDoNotShare — разные контексты имеют независимый кеш.
-func TestCache_DifferentContextsDoNotShare(t *testing.T) {
+// TestCache_NoCache — h.NoCache() обходит кеш: запрос видит внешнее изменение
+// и не перезаписывает закешированное значение.
+func TestCache_NoCache(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
-		cache := cachectx.New()
-		repo := newCachedPostRepo(t, ab, cache)
+		seed := defaultSeed(t, ab)
+		repo := newCachedPostRepo(t, ab, cachectx.New())
 
-		base, cancel := testCtx(t)
+		baseCtx, cancel := testCtx(t)
 		defer cancel()
-		ctx1 := cachectx.WrapContext(base)
-		ctx2 := cachectx.WrapContext(base)
+		ctx := cachectx.WrapContext(baseCtx)
 
 		target := seed.posts[0]
-		_, err := repo.GetFirst(ctx1, func(m *Post, h query.GetFirstHelper[Post]) {
-			h.Where().Field(&m.ID).EQ(target.ID)
-		})
-		require.NoError(t, err)
+		getPost := func(noCache bool) *Post {
+			got, err := repo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+				h.Where().Field(&m.ID).EQ(target.ID)
+				if noCache {
+					h.NoCache()
+				}
+			})
+			require.NoError(t, err)
+			return got
+		}
 
-		// Изменяем название извне.
-		_, err = pgx5Pool.Exec(context.Background(), `UPDATE posts SET title = 'ctx2-sees' WHERE id = $1`, target.ID)
+		getPost(false)
+		_, err := ab.db.ExecContext(ctx, `UPDATE posts SET title = $1 WHERE id = $2`, "no-cache", target.ID)
 		require.NoError(t, err)
 
-		// Запрос во втором контексте — кеш чистый, должен увидеть новое значение.
-		got, err := repo.GetFirst(ctx2, func(m *Post, h query.GetFirstHelper[Post]) {
-			h.Where().Field(&m.ID).EQ(target.ID)
-		})
+		assert.Equal(t, "no-cache", getPost(true).Title, "NoCache must read the database")
+		assert.Equal(t, target.Title, getPost(false).Title, "NoCache must not fill the cache")
+	})
+}
+
+// TestCache_CopyOnRead — с executor.WithCopyOnRead изменение результата
+// GetList не затрагивает кеш и следующие чтения.
+func TestCache_CopyOnRead(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		defaultSeed(t, ab)
+		repo, err := gerpo.New[Post]().
+			Adapter(ab.adapter, executor.WithCacheStorage(lru.NewStore().Cache("posts")), executor.WithCopyOnRead()).
+			Table("posts").
+			Columns(func(m *Post, c *gerpo.ColumnBuilder[Post]) {
+				c.Field(&m.ID).OmitOnUpdate()
+				c.Field(&m.UserID)
+				c.Field(&m.Title)
+				c.Field(&m.Content)
+				c.Field(&m.Published)
+				c.Field(&m.PublishedAt)
+				c.Field(&m.CreatedAt).OmitOnUpdate()
+			}).
+			Build()
 		require.NoError(t, err)
-		assert.Equal(t, "ctx2-sees", got.Title, "independent cache in the second context")
+
+		ctx, cancel := testCtx(t)
+		defer cancel()
+		list := func() []*Post {
+			got, err := repo.GetList(ctx, func(m *Post, h query.GetListHelper[Post]) {
+				h.OrderBy().Field(&m.Title).ASC()
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, got)
+			return got
+		}
+
+		first := list()
+		title := first[0].Title
+		first[0].Title = "mutated"
+
+		second := list()
+		assert.NotSame(t, first[0], second[0])
+		assert.Equal(t, title, second[0].Title, "a mutated result must not leak into the cache")
 	})
 }
@@ -0,0 +1,102 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConstraintViolation_Unique — повторный первичный ключ приходит как
+// ErrUniqueViolation с колонкой и полем модели, драйверная ошибка доступна
+// через Unwrap.
+func TestConstraintViolation_Unique(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		dup := seed.posts[0]
+		err := repo.Insert(ctx, &dup)
+		var uv *gerpo.ErrUniqueViolation
+		require.ErrorAs(t, err, &uv)
+		assert.Equal(t, "posts", uv.Table)
+		assert.Equal(t, "id", uv.Column)
+		assert.Equal(t, "ID", uv.Field)
+		assert.NotNil(t, errors.Unwrap(uv))
+		if ab.adapter.Dialect() == dialect.PostgreSQL {
+			assert.Equal(t, "posts_pkey", uv.Constraint)
+		}
+	})
+}
+
+// TestConstraintViolation_ForeignKey — ссылка на несуществующего автора.
+// SQLite не называет ни таблицу, ни колонку.
+func TestConstraintViolation_ForeignKey(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		p := Post{ID: uuid.New(), UserID: uuid.New(), Title: "orphan", Content: "c", CreatedAt: time.Now().UTC()}
+		err := repo.Insert(ctx, &p)
+		var fkv *gerpo.ErrForeignKeyViolation
+		require.ErrorAs(t, err, &fkv)
+		if ab.adapter.Dialect() == dialect.PostgreSQL {
+			assert.Equal(t, "posts_user_id_fkey", fkv.Constraint)
+			assert.Equal(t, "UserID", fkv.Field)
+		}
+	})
+}
+
+// TestConstraintViolation_NotNull — INSERT без title нарушает NOT NULL.
+func TestConstraintViolation_NotNull(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		p := Post{ID: uuid.New(), UserID: seed.users[0].ID, Content: "c", CreatedAt: time.Now().UTC()}
+		err := repo.Insert(ctx, &p, func(m *Post, h query.InsertHelper[Post]) {
+			h.Exclude(&m.Title)
+		})
+		var nnv *gerpo.ErrNotNullViolation
+		require.ErrorAs(t, err, &nnv)
+		assert.Equal(t, "posts", nnv.Table)
+		assert.Equal(t, "title", nnv.Column)
+		assert.Equal(t, "Title", nnv.Field)
+	})
+}
+
+// TestConstraintViolation_InTx — нарушение внутри RunInTx (и внутри pgx-строк
+// INSERT ... RETURNING) распознаётся так же.
+func TestConstraintViolation_InTx(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		dup := seed.posts[0]
+		err := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			return repo.Insert(ctx, &dup, func(m *Post, h query.InsertHelper[Post]) {
+				h.Returning(&m.CreatedAt)
+			})
+		})
+		var uv *gerpo.ErrUniqueViolation
+		require.ErrorAs(t, err, &uv)
+		assert.Equal(t, "ID", uv.Field)
+	})
+}
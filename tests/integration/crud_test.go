@@ -17,7 +17,7 @@ import (
 // TestGetFirst_ByID: GetFirst с точечным фильтром возвращает ожидаемую запись.
 func TestGetFirst_ByID(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -36,7 +36,7 @@ func TestGetFirst_ByID(t *testing.T) {
 // TestGetFirst_NotFound: GetFirst по несуществующему ID возвращает gerpo.ErrNotFound.
 func TestGetFirst_NotFound(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -52,7 +52,7 @@ func TestGetFirst_NotFound(t *testing.T) {
 // TestGetList_All: GetList без фильтров возвращает все записи.
 func TestGetList_All(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -80,7 +80,7 @@ func TestGetList_Empty(t *testing.T) {
 // TestCount_All: Count без фильтра возвращает общее количество.
 func TestCount_All(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -94,7 +94,7 @@ func TestCount_All(t *testing.T) {
 // TestCount_WithFilter: Count с WHERE возвращает только подходящие записи.
 func TestCount_WithFilter(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -117,7 +117,7 @@ func TestCount_WithFilter(t *testing.T) {
 // TestInsert_Happy: Insert добавляет запись, которую затем можно прочитать GetFirst'ом.
 func TestInsert_Happy(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -146,7 +146,7 @@ func TestInsert_Happy(t *testing.T) {
 // колоночный DEFAULT. Проверяем на published_at — NULL при исключении.
 func TestInsert_WithExclude(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -177,7 +177,7 @@ func TestInsert_WithExclude(t *testing.T) {
 // TestUpdate_Happy: Update меняет поле по WHERE и возвращает количество затронутых строк.
 func TestUpdate_Happy(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -201,7 +201,7 @@ func TestUpdate_Happy(t *testing.T) {
 // TestUpdate_NothingToUpdate: Update по несуществующему ID возвращает ErrNotFound.
 func TestUpdate_NothingToUpdate(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -224,7 +224,7 @@ func TestUpdate_NothingToUpdate(t *testing.T) {
 // TestDelete_Happy: Delete удаляет запись; последующий GetFirst возвращает ErrNotFound.
 func TestDelete_Happy(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -246,7 +246,7 @@ func TestDelete_Happy(t *testing.T) {
 // TestDelete_NothingToDelete: Delete по несуществующему ID возвращает ErrNotFound.
 func TestDelete_NothingToDelete(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
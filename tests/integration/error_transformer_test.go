@@ -5,6 +5,7 @@ package integration
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,7 +48,7 @@ func newPostRepoWithErrorTransformer(t *testing.T, ab adapterBundle) gerpo.Repos
 // TestErrorTransformer_GetFirst — GetFirst на несуществующей записи возвращает доменную ошибку.
 func TestErrorTransformer_GetFirst(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepoWithErrorTransformer(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -64,7 +65,7 @@ func TestErrorTransformer_GetFirst(t *testing.T) {
 // TestErrorTransformer_Update — Update ничего не обновивший тоже проходит через transformer.
 func TestErrorTransformer_Update(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepoWithErrorTransformer(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -87,7 +88,7 @@ func TestErrorTransformer_Update(t *testing.T) {
 // TestErrorTransformer_Delete — Delete ничего не удаливший возвращает доменную ошибку.
 func TestErrorTransformer_Delete(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepoWithErrorTransformer(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -103,7 +104,7 @@ func TestErrorTransformer_Delete(t *testing.T) {
 // TestErrorTransformer_PassthroughOnHappyPath — при успехе transformer не искажает результат.
 func TestErrorTransformer_PassthroughOnHappyPath(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepoWithErrorTransformer(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -120,7 +121,7 @@ func TestErrorTransformer_PassthroughOnHappyPath(t *testing.T) {
 // но не трогает те, что не ErrNotFound. Проверяется через Insert с конфликтом FK.
 func TestErrorTransformer_DoesNotSwallowOtherErrors(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepoWithErrorTransformer(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -136,7 +137,8 @@ func TestErrorTransformer_DoesNotSwallowOtherErrors(t *testing.T) {
 		err := repo.Insert(ctx, &p)
 		require.Error(t, err)
 		assert.NotErrorIs(t, err, errDomainPostNotFound, "non-NotFound errors must not be mapped to domain")
-		// Сама ошибка должна упоминать FK / нарушение.
-		assert.Contains(t, fmt.Sprintf("%v", err), "violates", "expected FK violation message")
+		// Сама ошибка должна упоминать FK: "violates foreign key constraint" в
+		// PostgreSQL, "FOREIGN KEY constraint failed" в SQLite.
+		assert.Contains(t, strings.ToLower(fmt.Sprintf("%v", err)), "foreign key", "expected FK violation message")
 	})
 }
@@ -14,7 +14,7 @@ import (
 // TestExclude_GetFirst — Exclude исключает поле из SELECT, оно приходит в zero-state.
 func TestExclude_GetFirst(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -34,7 +34,7 @@ func TestExclude_GetFirst(t *testing.T) {
 // TestOnly_GetFirst — Only оставляет в SELECT только указанные поля.
 func TestOnly_GetFirst(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -54,7 +54,7 @@ func TestOnly_GetFirst(t *testing.T) {
 // TestExclude_GetList_ZeroCreatedAt — поле, исключённое из SELECT, остаётся нулевым.
 func TestExclude_GetList(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -74,7 +74,7 @@ func TestExclude_GetList(t *testing.T) {
 // TestUpdate_Only — Update с Only обновляет только указанное поле, остальные не трогаются.
 func TestUpdate_Only(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -105,7 +105,7 @@ func TestUpdate_Only(t *testing.T) {
 // TestUpdate_Exclude — Update с Exclude не трогает указанное поле.
 func TestUpdate_Exclude(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/filters"
 	"github.com/insei/gerpo/query"
 	"github.com/insei/gerpo/types"
@@ -41,18 +42,32 @@ CREATE TABLE IF NOT EXISTS filter_registry_demo (
 );
 `
 
+// statusSchemaUpSQLite — the same table for SQLite, which has no UUID type:
+// the default is 32 random hex digits, a form uuid.Parse accepts.
+const statusSchemaUpSQLite = `
+CREATE TABLE IF NOT EXISTS filter_registry_demo (
+    id     TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+    status TEXT NOT NULL,
+    note   TEXT NOT NULL DEFAULT ''
+);
+`
+
 const statusSchemaDown = `DROP TABLE IF EXISTS filter_registry_demo;`
 
-func setupStatusSchema(t *testing.T) {
+func setupStatusSchema(t *testing.T, ab adapterBundle) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := pgx5Pool.Exec(ctx, statusSchemaUp)
+	up := statusSchemaUp
+	if ab.adapter.Dialect() == dialect.SQLite {
+		up = statusSchemaUpSQLite
+	}
+	_, err := ab.db.ExecContext(ctx, up)
 	require.NoError(t, err, "create filter_registry_demo table")
 	t.Cleanup(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		_, _ = pgx5Pool.Exec(ctx, statusSchemaDown)
+		_, _ = ab.db.ExecContext(ctx, statusSchemaDown)
 	})
 }
 
@@ -70,7 +85,7 @@ func TestFilterRegistry_StringAliasFilters_RoundTrip(t *testing.T) {
 		Allow(types.OperationEQ, types.OperationIn)
 
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupStatusSchema(t)
+		setupStatusSchema(t, ab)
 
 		repo, err := gerpo.New[statusModel]().
 			Adapter(ab.adapter).
@@ -121,13 +136,15 @@ func TestFilterRegistry_TimeOverride_AppliesCustomSQL(t *testing.T) {
 	restore := filters.Snapshot()
 	t.Cleanup(restore)
 
-	// Compare only the date — ignore the time-of-day portion.
-	filters.Registry.Time.Override(types.OperationEQ, filters.Bound{
-		SQL: "DATE_TRUNC('day', created_at) = DATE_TRUNC('day', CAST(? AS timestamptz))",
-	})
-
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		// Compare only the date — ignore the time-of-day portion.
+		dateEQ := "DATE_TRUNC('day', created_at) = DATE_TRUNC('day', CAST(? AS timestamptz))"
+		if ab.adapter.Dialect() == dialect.SQLite {
+			dateEQ = "date(created_at) = date(?)"
+		}
+		filters.Registry.Time.Override(types.OperationEQ, filters.Bound{SQL: dateEQ})
+
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
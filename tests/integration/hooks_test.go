@@ -70,7 +70,7 @@ func newPostRepoWithHooks(t *testing.T, ab adapterBundle, c *hookCounters) gerpo
 // из BeforeInsert попала в базу.
 func TestHooks_Insert_BeforeAfter(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		c := &hookCounters{}
 		repo := newPostRepoWithHooks(t, ab, c)
 		ctx, cancel := testCtx(t)
@@ -98,7 +98,7 @@ func TestHooks_Insert_BeforeAfter(t *testing.T) {
 // TestHooks_Update_BeforeAfter — хуки UPDATE зовутся ровно один раз.
 func TestHooks_Update_BeforeAfter(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		c := &hookCounters{}
 		repo := newPostRepoWithHooks(t, ab, c)
 		ctx, cancel := testCtx(t)
@@ -118,7 +118,7 @@ func TestHooks_Update_BeforeAfter(t *testing.T) {
 // TestHooks_AfterSelect_GetFirst — afterSelect получает срез из одной записи.
 func TestHooks_AfterSelect_GetFirst(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		c := &hookCounters{}
 		repo := newPostRepoWithHooks(t, ab, c)
 		ctx, cancel := testCtx(t)
@@ -135,7 +135,7 @@ func TestHooks_AfterSelect_GetFirst(t *testing.T) {
 // TestHooks_AfterSelect_GetList — afterSelect получает срез со всеми записями.
 func TestHooks_AfterSelect_GetList(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		c := &hookCounters{}
 		repo := newPostRepoWithHooks(t, ab, c)
 		ctx, cancel := testCtx(t)
@@ -151,7 +151,7 @@ func TestHooks_AfterSelect_GetList(t *testing.T) {
 // INSERT не выполняется, ошибка выходит из repo.Insert.
 func TestHooks_BeforeInsert_ErrorAbortsSQL(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -182,7 +182,7 @@ func TestHooks_BeforeInsert_ErrorAbortsSQL(t *testing.T) {
 
 		var exists bool
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, id).Scan(&exists))
+			ab.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, id).Scan(&exists))
 		assert.False(t, exists, "aborted before SQL — row must not exist")
 	})
 }
@@ -191,7 +191,7 @@ func TestHooks_BeforeInsert_ErrorAbortsSQL(t *testing.T) {
 // уже прошёл (запись в БД есть), но ошибка возвращается пользователю.
 func TestHooks_AfterInsert_ErrorSurfaces(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -222,7 +222,7 @@ func TestHooks_AfterInsert_ErrorSurfaces(t *testing.T) {
 
 		var exists bool
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, id).Scan(&exists))
+			ab.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, id).Scan(&exists))
 		assert.True(t, exists, "SQL runs before AfterInsert; without tx rollback row persists")
 	})
 }
@@ -231,7 +231,7 @@ func TestHooks_AfterInsert_ErrorSurfaces(t *testing.T) {
 // вставка родителя + каскад детей в AfterInsert через тот же ctx-tx.
 func TestHooks_AfterInsert_CascadeInTx(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -273,12 +273,12 @@ func TestHooks_AfterInsert_CascadeInTx(t *testing.T) {
 
 		var postExists bool
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, postID).Scan(&postExists))
+			ab.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, postID).Scan(&postExists))
 		assert.True(t, postExists)
 
 		var commentCount int
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT count(*) FROM comments WHERE post_id = $1`, postID).Scan(&commentCount))
+			ab.db.QueryRowContext(ctx, `SELECT count(*) FROM comments WHERE post_id = $1`, postID).Scan(&commentCount))
 		assert.Equal(t, 3, commentCount, "cascade must have persisted 3 comments")
 	})
 }
@@ -287,7 +287,7 @@ func TestHooks_AfterInsert_CascadeInTx(t *testing.T) {
 // tx откатывается: родитель и уже сохранённый ребёнок не остаются в БД.
 func TestHooks_AfterInsert_CascadeRollback(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -329,12 +329,12 @@ func TestHooks_AfterInsert_CascadeRollback(t *testing.T) {
 
 		var anything bool
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, postID).Scan(&anything))
+			ab.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, postID).Scan(&anything))
 		assert.False(t, anything, "cascade failure must roll back the parent row")
 
 		var orphanComments int
 		require.NoError(t,
-			pgx5Pool.QueryRow(ctx, `SELECT count(*) FROM comments WHERE post_id = $1`, postID).Scan(&orphanComments))
+			ab.db.QueryRowContext(ctx, `SELECT count(*) FROM comments WHERE post_id = $1`, postID).Scan(&orphanComments))
 		assert.Zero(t, orphanComments, "successful child row must have rolled back too")
 	})
 }
@@ -342,7 +342,7 @@ func TestHooks_AfterInsert_CascadeRollback(t *testing.T) {
 // TestHooks_Stacking — несколько WithBeforeInsert складываются: оба хука зовутся.
 func TestHooks_Stacking(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -18,7 +18,7 @@ import (
 // count равен длине среза, данные попадают в таблицу.
 func TestInsertMany_BasicRoundTrip(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -48,7 +48,7 @@ func TestInsertMany_BasicRoundTrip(t *testing.T) {
 // TestInsertMany_Empty — пустой срез не делает запроса и не вызывает хуки.
 func TestInsertMany_Empty(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		var beforeCalls, afterCalls int
 		repo, err := gerpo.New[Post]().
 			Adapter(ab.adapter).
@@ -88,7 +88,7 @@ func TestInsertMany_Empty(t *testing.T) {
 // серверные значения обратно по позиции.
 func TestInsertMany_Returning(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		repo := newReturningRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -117,7 +117,7 @@ func TestInsertMany_Returning(t *testing.T) {
 // из Before отменяет SQL, ошибка из After возвращается после записи.
 func TestInsertMany_Hooks(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -163,7 +163,7 @@ func TestInsertMany_Hooks(t *testing.T) {
 // достаточно строк при разумном весе.
 func TestInsertMany_LargeBatch_ChunksTransparently(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		// На returning_demo в INSERT идут только (id, title, created_at) —
 		// active-cols ≤ 3, значит chunkSize ≥ 21000. Достаточно прислать
 		// чуть больше, чтобы стабильно уйти на второй чанк.
@@ -202,3 +202,104 @@ func TestInsertMany_LargeBatch_ChunksTransparently(t *testing.T) {
 		}
 	})
 }
+
+// TestInsertMany_UpsertDoNothing — конфликтующая строка пропускается, счётчик
+// учитывает только записанные, RETURNING не сдвигается на пропущенную модель.
+func TestInsertMany_UpsertDoNothing(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		existing := seed.posts[0]
+		author := seed.users[0].ID
+		batch := []*Post{
+			{ID: uuid.New(), UserID: author, Title: "upsert-1", Content: "c1", CreatedAt: nowUTC()},
+			{ID: existing.ID, UserID: author, Title: "upsert-dup", Content: "dup", CreatedAt: nowUTC()},
+			{ID: uuid.New(), UserID: author, Title: "upsert-3", Content: "c3", CreatedAt: nowUTC()},
+		}
+
+		n, err := repo.InsertMany(ctx, batch, func(m *Post, h query.InsertManyHelper[Post]) {
+			h.OnConflict(&m.ID).DoNothing()
+			h.Returning(&m.Title)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, "upsert-1", batch[0].Title)
+		assert.Equal(t, "upsert-dup", batch[1].Title, "skipped model must stay untouched")
+		assert.Equal(t, "upsert-3", batch[2].Title)
+
+		got, err := repo.GetFirst(ctx, func(p *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&p.ID).EQ(existing.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, existing.Title, got.Title)
+	})
+}
+
+// TestInsertMany_UpsertDoUpdate — существующая строка обновляется, а значения
+// из RETURNING попадают в ту модель, которая её породила.
+func TestInsertMany_UpsertDoUpdate(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		existing := seed.posts[0]
+		author := seed.users[0].ID
+		fresh := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		batch := []*Post{
+			{ID: uuid.New(), UserID: author, Title: "upsert-new", Content: "c1", CreatedAt: fresh},
+			{ID: existing.ID, UserID: author, Title: "upsert-changed", Content: "changed", CreatedAt: fresh},
+		}
+
+		n, err := repo.InsertMany(ctx, batch, func(m *Post, h query.InsertManyHelper[Post]) {
+			h.OnConflict(&m.ID).DoUpdate()
+			h.Returning(&m.CreatedAt)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.True(t, batch[0].CreatedAt.Equal(fresh))
+		assert.True(t, batch[1].CreatedAt.Equal(existing.CreatedAt), "created_at is OmitOnUpdate, the stored value must come back")
+
+		got, err := repo.GetFirst(ctx, func(p *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&p.ID).EQ(existing.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "upsert-changed", got.Title)
+	})
+}
+
+// TestInsertMany_UpsertConstraint — цель по имени ограничения есть только в
+// PostgreSQL; остальные диалекты отвергают её до отправки SQL.
+func TestInsertMany_UpsertConstraint(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		existing := seed.posts[0]
+		batch := []*Post{
+			{ID: uuid.New(), UserID: seed.users[0].ID, Title: "upsert-constraint", Content: "c", CreatedAt: nowUTC()},
+			{ID: existing.ID, UserID: seed.users[0].ID, Title: "upsert-dup", Content: "dup", CreatedAt: nowUTC()},
+		}
+		n, err := repo.InsertMany(ctx, batch, func(m *Post, h query.InsertManyHelper[Post]) {
+			h.OnConflictConstraint("posts_pkey").DoNothing()
+			h.Returning()
+		})
+		if ab.adapter.Dialect().SupportsConflictConstraint() {
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), n)
+			return
+		}
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+		count, err := repo.Count(ctx, func(m *Post, h query.CountHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(batch[0].ID)
+		})
+		require.NoError(t, err)
+		assert.Zero(t, count, "nothing reaches the database")
+	})
+}
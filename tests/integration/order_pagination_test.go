@@ -5,6 +5,7 @@ package integration
 import (
 	"testing"
 
+	"github.com/insei/gerpo"
 	"github.com/insei/gerpo/query"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,7 +14,7 @@ import (
 // TestOrder_ASC сортирует по возрастанию: наименьший age → пользователь с index 0.
 func TestOrder_ASC(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -31,7 +32,7 @@ func TestOrder_ASC(t *testing.T) {
 // TestOrder_DESC — обратный порядок.
 func TestOrder_DESC(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -52,7 +53,7 @@ func TestOrder_DESC(t *testing.T) {
 // Первая запись должна быть Post 0 (published, самая ранняя).
 func TestOrder_MultipleFields(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -76,7 +77,7 @@ func TestOrder_MultipleFields(t *testing.T) {
 // TestPagination_PageSize — разбиение по страницам.
 func TestPagination_PageSize(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -104,7 +105,7 @@ func TestPagination_PageSize(t *testing.T) {
 // TestPagination_EmptyPage — страница за пределами набора пуста.
 func TestPagination_EmptyPage(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -120,7 +121,7 @@ func TestPagination_EmptyPage(t *testing.T) {
 // TestPagination_SizeOnly — только LIMIT, без OFFSET.
 func TestPagination_SizeOnly(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -138,7 +139,7 @@ func TestPagination_SizeOnly(t *testing.T) {
 // TestPagination_PageWithoutSize — Page без Size должен вернуть ошибку.
 func TestPagination_PageWithoutSize(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -149,3 +150,121 @@ func TestPagination_PageWithoutSize(t *testing.T) {
 		require.Error(t, err, "Page without Size is invalid")
 	})
 }
+
+// TestKeyset_WalksAllPages — keyset-пагинация со смешанным ASC/DESC обходит
+// все посты без пропусков и повторов, последняя страница не отдаёт курсор.
+func TestKeyset_WalksAllPages(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		order := func(m *Post, h query.GetListHelper[Post]) {
+			h.OrderBy().Field(&m.Published).DESC()
+			h.OrderBy().Field(&m.CreatedAt).ASC()
+			h.OrderBy().Field(&m.ID).ASC()
+		}
+		want, err := repo.GetList(ctx, order)
+		require.NoError(t, err)
+
+		var got []*Post
+		cursor := ""
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, len(seed.posts), "keyset walk does not terminate")
+			var next string
+			page, err := repo.GetList(ctx, order, func(m *Post, h query.GetListHelper[Post]) {
+				h.Size(7).After(cursor).NextCursor(&next)
+			})
+			require.NoError(t, err)
+			got = append(got, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		require.Len(t, got, len(want))
+		for i := range want {
+			assert.Equal(t, want[i].ID, got[i].ID, "row %d", i)
+		}
+	})
+}
+
+// TestKeyset_InvalidCursor — мусорный курсор → gerpo.ErrInvalidCursor.
+func TestKeyset_InvalidCursor(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		defaultSeed(t, ab)
+		repo := newUserRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		_, err := repo.GetList(ctx, func(m *User, h query.GetListHelper[User]) {
+			h.OrderBy().Field(&m.ID).ASC()
+			h.Size(3).After("not-a-cursor")
+		})
+		require.ErrorIs(t, err, gerpo.ErrInvalidCursor)
+	})
+}
+
+// TestGetPage_TotalAndPage — GetPage отдаёт страницу и общее число строк по
+// фильтру; страница за концом данных пустая, но total всё равно верный.
+func TestGetPage_TotalAndPage(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newUserRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		got, total, err := repo.GetPage(ctx, func(m *User, h query.GetListHelper[User]) {
+			h.OrderBy().Field(&m.Age).ASC()
+			h.Page(2).Size(3)
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		assert.Equal(t, uint64(len(seed.users)), total)
+		assert.Equal(t, seed.users[3].ID, got[0].ID)
+
+		got, total, err = repo.GetPage(ctx, func(m *User, h query.GetListHelper[User]) {
+			h.Page(5).Size(3)
+		})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Equal(t, uint64(len(seed.users)), total)
+	})
+}
+
+// TestIterate_StreamsAndBreaks — Iterate отдаёт все строки по одной в порядке
+// ORDER BY, а break посреди цикла закрывает rows без ошибок.
+func TestIterate_StreamsAndBreaks(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newUserRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		order := func(m *User, h query.GetListHelper[User]) {
+			h.OrderBy().Field(&m.Age).ASC()
+		}
+		var got []*User
+		for u, err := range repo.Iterate(ctx, order) {
+			require.NoError(t, err)
+			got = append(got, u)
+		}
+		require.Len(t, got, len(seed.users))
+		assert.Equal(t, seed.users[0].ID, got[0].ID)
+
+		n := 0
+		for _, err := range repo.Iterate(ctx, order) {
+			require.NoError(t, err)
+			n++
+			if n == 3 {
+				break
+			}
+		}
+		assert.Equal(t, 3, n)
+
+		// Соединение вернулось в пул — следующий запрос проходит.
+		_, err := repo.Count(ctx)
+		require.NoError(t, err)
+	})
+}
@@ -22,7 +22,7 @@ import (
 //	"must appear in the GROUP BY clause or be used in an aggregate function".
 func TestPersistent_AutoGroupBy_AggregateVirtual(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -64,7 +64,7 @@ func TestPersistent_AutoGroupBy_AggregateVirtual(t *testing.T) {
 // Для каждого пользователя в seed'е по 3 поста, значит post_count=3 для всех.
 func TestPersistent_LeftJoin_VirtualColumn(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -84,14 +84,14 @@ func TestPersistent_LeftJoin_VirtualColumn(t *testing.T) {
 // должен скрывать soft-deleted записи от GetList, GetFirst и Count.
 func TestPersistent_Where_HidesSoftDeleted(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
 		// Помечаем user[0] как удалённого напрямую через sql, минуя репо.
 		now := nowUTC()
-		_, err := pgx5Pool.Exec(ctx, `UPDATE users SET deleted_at = $1 WHERE id = $2`, now, seed.users[0].ID)
+		_, err := ab.db.ExecContext(ctx, `UPDATE users SET deleted_at = $1 WHERE id = $2`, now, seed.users[0].ID)
 		require.NoError(t, err)
 
 		count, err := repo.Count(ctx)
@@ -116,14 +116,14 @@ func TestPersistent_Where_HidesSoftDeleted(t *testing.T) {
 // с per-request WHERE через AND.
 func TestPersistent_Where_CombinesWithRequestWhere(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
 		// Пометим user[0] (age=20) как soft-deleted. Per-request фильтр Age<23
 		// в обычной ситуации дал бы 3 записи (age 20,21,22). С soft delete — 2.
-		_, err := pgx5Pool.Exec(ctx, `UPDATE users SET deleted_at = NOW() WHERE id = $1`, seed.users[0].ID)
+		_, err := ab.db.ExecContext(ctx, `UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1`, seed.users[0].ID)
 		require.NoError(t, err)
 
 		got, err := repo.Count(ctx, func(m *User, h query.CountHelper[User]) {
@@ -139,7 +139,7 @@ func TestPersistent_Where_CombinesWithRequestWhere(t *testing.T) {
 // постов и убедимся, что он не попадает в выборку.
 func TestPersistent_InnerJoin(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -150,7 +150,7 @@ func TestPersistent_InnerJoin(t *testing.T) {
 			Age:       99,
 			CreatedAt: nowUTC(),
 		}
-		_, err := pgx5Pool.Exec(ctx, `INSERT INTO users (id, name, age, created_at) VALUES ($1,$2,$3,$4)`,
+		_, err := ab.db.ExecContext(ctx, `INSERT INTO users (id, name, age, created_at) VALUES ($1,$2,$3,$4)`,
 			lonelyUser.ID, lonelyUser.Name, lonelyUser.Age, lonelyUser.CreatedAt)
 		require.NoError(t, err)
 
@@ -188,7 +188,7 @@ func TestPersistent_InnerJoin(t *testing.T) {
 // post_count then reflects only that user's posts.
 func TestPersistent_LeftJoinOn_BindsArgs(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -248,7 +248,7 @@ func TestPersistent_LeftJoinOn_BindsArgs(t *testing.T) {
 // (UUID не приведётся к int и наоборот).
 func TestPersistent_LeftJoinOn_ArgOrder_HoldsAcrossWhereAndCount(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -326,7 +326,7 @@ func TestPersistent_LeftJoinOn_ArgOrder_HoldsAcrossWhereAndCount(t *testing.T) {
 // users matching the bound condition appear in the result.
 func TestPersistent_InnerJoinOn_FiltersByBoundArg(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -375,7 +375,7 @@ type tenantCtxKey struct{}
 // JOIN binds a different value accordingly.
 func TestPersistent_LeftJoinOn_ResolverReadsCtx(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		baseCtx, cancel := testCtx(t)
 		defer cancel()
 
@@ -442,7 +442,7 @@ func TestPersistent_LeftJoinOn_ResolverReadsCtx(t *testing.T) {
 // surfaces the error wrapped in ErrApplyJoinClause.
 func TestPersistent_LeftJoinOn_ResolverError(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
 
@@ -0,0 +1,76 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUserRepoWithPK — users с объявленным первичным ключом и soft delete,
+// без JOIN на posts.
+func newUserRepoWithPK(t *testing.T, ab adapterBundle) gerpo.Repository[User] {
+	t.Helper()
+	repo, err := gerpo.New[User]().
+		Adapter(ab.adapter).
+		Table("users").
+		Columns(func(m *User, c *gerpo.ColumnBuilder[User]) {
+			c.Field(&m.ID).PrimaryKey().OmitOnUpdate()
+			c.Field(&m.Name)
+			c.Field(&m.Email)
+			c.Field(&m.Age)
+			c.Field(&m.CreatedAt).OmitOnUpdate()
+			c.Field(&m.UpdatedAt).OmitOnInsert()
+			c.Field(&m.DeletedAt).OmitOnInsert()
+		}).
+		WithSoftDeletion(func(m *User, b *gerpo.SoftDeletionBuilder[User]) {
+			b.Field(&m.DeletedAt).SetValueFn(func(ctx context.Context) any {
+				t := nowUTC()
+				return &t
+			})
+		}).
+		Build()
+	require.NoError(t, err)
+	return repo
+}
+
+// TestPrimaryKey_ByID — GetByID / GetByIDs / DeleteByID и Update без WHERE,
+// который трогает только строку модели.
+func TestPrimaryKey_ByID(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newUserRepoWithPK(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		target := seed.users[0]
+		got, err := repo.GetByID(ctx, target.ID)
+		require.NoError(t, err)
+		assert.Equal(t, target.Name, got.Name)
+
+		_, err = repo.GetByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, gerpo.ErrNotFound)
+
+		list, err := repo.GetByIDs(ctx, seed.users[0].ID, seed.users[1].ID, uuid.New())
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		got.Name = "renamed"
+		n, err := repo.Update(ctx, got)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		other, err := repo.GetByID(ctx, seed.users[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, seed.users[1].Name, other.Name)
+
+		require.NoError(t, repo.DeleteByID(ctx, target.ID))
+		deleted, err := repo.GetByID(ctx, target.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, deleted.DeletedAt)
+	})
+}
@@ -4,11 +4,13 @@ package integration
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/query"
 	"github.com/stretchr/testify/assert"
@@ -53,22 +55,39 @@ CREATE TRIGGER returning_demo_touch
     FOR EACH ROW EXECUTE PROCEDURE returning_demo_touch_updated_at();
 `
 
+// returningSchemaUpSQLite — the same table for SQLite. There is no trigger:
+// SQLite's RETURNING does not report changes made by triggers.
+const returningSchemaUpSQLite = `
+CREATE TABLE IF NOT EXISTS returning_demo (
+    id          TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+    title       TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP
+);
+`
+
+const returningSchemaDownSQLite = `DROP TABLE IF EXISTS returning_demo;`
+
 const returningSchemaDown = `
 DROP TRIGGER IF EXISTS returning_demo_touch ON returning_demo;
 DROP FUNCTION IF EXISTS returning_demo_touch_updated_at();
 DROP TABLE IF EXISTS returning_demo;
 `
 
-func setupReturningSchema(t *testing.T) {
+func setupReturningSchema(t *testing.T, ab adapterBundle) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	_, err := pgx5Pool.Exec(ctx, returningSchemaUp)
+	up, down := returningSchemaUp, returningSchemaDown
+	if ab.adapter.Dialect() == dialect.SQLite {
+		up, down = returningSchemaUpSQLite, returningSchemaDownSQLite
+	}
+	_, err := ab.db.ExecContext(ctx, up)
 	require.NoError(t, err, "create returning_demo table")
 	t.Cleanup(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		_, _ = pgx5Pool.Exec(ctx, returningSchemaDown)
+		_, _ = ab.db.ExecContext(ctx, down)
 	})
 }
 
@@ -92,7 +111,7 @@ func newReturningRepo(t *testing.T, ab adapterBundle) gerpo.Repository[returning
 // back from RETURNING and land in the model in-place.
 func TestReturning_Insert_FillsServerGenerated(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		repo := newReturningRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -110,7 +129,8 @@ func TestReturning_Insert_FillsServerGenerated(t *testing.T) {
 // post-update value must arrive back into the model.
 func TestReturning_Update_FillsTriggerColumn(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		requirePostgres(t, ab)
+		setupReturningSchema(t, ab)
 		repo := newReturningRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -135,7 +155,7 @@ func TestReturning_Update_FillsTriggerColumn(t *testing.T) {
 // despite being marked ReturnedOnInsert at the repo.
 func TestReturning_PerRequest_NarrowsList(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		repo := newReturningRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -159,7 +179,7 @@ func TestReturning_PerRequest_NarrowsList(t *testing.T) {
 // than QueryContext, so the executor used to mask the failure as "no rows".
 func TestReturning_Insert_UniqueViolation_SurfacesDriverError(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		// Tweak the repo so the test owns the ID column (no DB DEFAULT path):
 		// keeping ID app-supplied lets us deliberately collide on the PK.
 		repo, err := gerpo.New[returningModel]().
@@ -186,7 +206,7 @@ func TestReturning_Insert_UniqueViolation_SurfacesDriverError(t *testing.T) {
 		require.Error(t, err, "expected unique violation, got nil")
 		assert.NotErrorIs(t, err, executor.ErrNoInsertedRows,
 			"executor must surface the driver's unique_violation, not mask it as ErrNoInsertedRows")
-		assert.Contains(t, err.Error(), "unique",
+		assert.Contains(t, strings.ToLower(err.Error()), "unique",
 			"expected unique-violation message from driver, got: %v", err)
 	})
 }
@@ -195,7 +215,7 @@ func TestReturning_Insert_UniqueViolation_SurfacesDriverError(t *testing.T) {
 // as above for the multi-row path.
 func TestReturning_InsertMany_UniqueViolation_SurfacesDriverError(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		repo, err := gerpo.New[returningModel]().
 			Adapter(ab.adapter).
 			Table("returning_demo").
@@ -223,7 +243,7 @@ func TestReturning_InsertMany_UniqueViolation_SurfacesDriverError(t *testing.T)
 		require.Error(t, err, "expected unique violation from InsertMany, got nil")
 		assert.NotErrorIs(t, err, executor.ErrNoInsertedRows,
 			"executor must surface the driver's unique_violation, not mask it as ErrNoInsertedRows")
-		assert.Contains(t, err.Error(), "unique",
+		assert.Contains(t, strings.ToLower(err.Error()), "unique",
 			"expected unique-violation message from driver, got: %v", err)
 	})
 }
@@ -232,7 +252,7 @@ func TestReturning_InsertMany_UniqueViolation_SurfacesDriverError(t *testing.T)
 // RETURNING off for the call.
 func TestReturning_PerRequest_DisablesEntirely(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		setupReturningSchema(t)
+		setupReturningSchema(t, ab)
 		repo := newReturningRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
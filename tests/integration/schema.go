@@ -8,3 +8,6 @@ import (
 
 //go:embed schema.sql
 var schemaSQL string
+
+//go:embed schema_sqlite.sql
+var schemaSQLiteSQL string
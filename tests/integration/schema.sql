@@ -19,7 +19,8 @@ CREATE TABLE posts (
     content       TEXT NOT NULL,
     published     BOOLEAN NOT NULL DEFAULT FALSE,
     published_at  TIMESTAMPTZ,
-    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version       INT NOT NULL DEFAULT 0
 );
 
 CREATE TABLE comments (
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
 // fixedSeed — детерминированный набор данных для тестов. UUID'ы фиксированные,
@@ -23,7 +22,7 @@ type fixedSeed struct {
 // defaultSeed вставляет standard набор: 10 пользователей, 30 постов (по 3 на юзера),
 // 50 комментариев (распределены неравномерно между постами и авторами).
 // Вызывать после truncateAll.
-func defaultSeed(t *testing.T) fixedSeed {
+func defaultSeed(t *testing.T, ab adapterBundle) fixedSeed {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -74,40 +73,40 @@ func defaultSeed(t *testing.T) fixedSeed {
 		})
 	}
 
-	insertSeed(ctx, t, seed)
+	insertSeed(ctx, t, ab, seed)
 	return seed
 }
 
-func insertSeed(ctx context.Context, t *testing.T, seed fixedSeed) {
+func insertSeed(ctx context.Context, t *testing.T, ab adapterBundle, seed fixedSeed) {
 	t.Helper()
-	tx, err := pgx5Pool.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := ab.db.BeginTx(ctx, nil)
 	if err != nil {
 		t.Fatalf("seed: begin tx: %v", err)
 	}
-	defer func() { _ = tx.Rollback(ctx) }()
+	defer func() { _ = tx.Rollback() }()
 
 	for _, u := range seed.users {
-		_, err := tx.Exec(ctx, `INSERT INTO users (id, name, email, age, created_at) VALUES ($1,$2,$3,$4,$5)`,
+		_, err := tx.ExecContext(ctx, `INSERT INTO users (id, name, email, age, created_at) VALUES ($1,$2,$3,$4,$5)`,
 			u.ID, u.Name, u.Email, u.Age, u.CreatedAt)
 		if err != nil {
 			t.Fatalf("seed user: %v", err)
 		}
 	}
 	for _, p := range seed.posts {
-		_, err := tx.Exec(ctx, `INSERT INTO posts (id, user_id, title, content, published, published_at, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+		_, err := tx.ExecContext(ctx, `INSERT INTO posts (id, user_id, title, content, published, published_at, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
 			p.ID, p.UserID, p.Title, p.Content, p.Published, p.PublishedAt, p.CreatedAt)
 		if err != nil {
 			t.Fatalf("seed post: %v", err)
 		}
 	}
 	for _, c := range seed.comments {
-		_, err := tx.Exec(ctx, `INSERT INTO comments (id, post_id, user_id, body, created_at) VALUES ($1,$2,$3,$4,$5)`,
+		_, err := tx.ExecContext(ctx, `INSERT INTO comments (id, post_id, user_id, body, created_at) VALUES ($1,$2,$3,$4,$5)`,
 			c.ID, c.PostID, c.UserID, c.Body, c.CreatedAt)
 		if err != nil {
 			t.Fatalf("seed comment: %v", err)
 		}
 	}
-	if err := tx.Commit(ctx); err != nil {
+	if err := tx.Commit(); err != nil {
 		t.Fatalf("seed: commit: %v", err)
 	}
 }
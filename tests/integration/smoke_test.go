@@ -13,7 +13,7 @@ import (
 // фикстуры и GetFirst возвращает ожидаемую запись для каждого адаптера.
 func TestSmoke(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 
 		ctx, cancel := testCtx(t)
@@ -17,7 +17,7 @@ import (
 // выставляет deleted_at, а не удаляет строку физически.
 func TestSoftDelete_UpdatesInsteadOfDeleting(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -31,7 +31,7 @@ func TestSoftDelete_UpdatesInsteadOfDeleting(t *testing.T) {
 
 		// Row is still in the table, but deleted_at is not NULL.
 		var deletedAt *time.Time
-		err = pgx5Pool.QueryRow(ctx, `SELECT deleted_at FROM users WHERE id = $1`, target.ID).Scan(&deletedAt)
+		err = ab.db.QueryRowContext(ctx, `SELECT deleted_at FROM users WHERE id = $1`, target.ID).Scan(&deletedAt)
 		require.NoError(t, err, "row must still physically exist")
 		require.NotNil(t, deletedAt, "deleted_at must be populated")
 	})
@@ -41,7 +41,7 @@ func TestSoftDelete_UpdatesInsteadOfDeleting(t *testing.T) {
 // (persistent Where исключает её).
 func TestSoftDelete_HiddenFromRepo(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -64,7 +64,7 @@ func TestSoftDelete_HiddenFromRepo(t *testing.T) {
 // возвращает ErrNotFound, т.к. persistent Where отсекает её от UPDATE.
 func TestSoftDelete_NothingToDelete(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -88,7 +88,7 @@ func TestSoftDelete_NothingToDelete(t *testing.T) {
 // после чего она снова видна репо.
 func TestSoftDelete_Restore(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -99,7 +99,7 @@ func TestSoftDelete_Restore(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		_, err = pgx5Pool.Exec(ctx, `UPDATE users SET deleted_at = NULL WHERE id = $1`, target.ID)
+		_, err = ab.db.ExecContext(ctx, `UPDATE users SET deleted_at = NULL WHERE id = $1`, target.ID)
 		require.NoError(t, err)
 
 		got, err := repo.GetFirst(ctx, func(m *User, h query.GetFirstHelper[User]) {
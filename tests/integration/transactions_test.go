@@ -3,6 +3,7 @@
 package integration
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -17,7 +18,7 @@ import (
 // TestTx_Commit — изменения, сделанные в транзакции и закоммиченные, видны извне.
 func TestTx_Commit(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -47,7 +48,7 @@ func TestTx_Commit(t *testing.T) {
 // TestTx_Rollback — после Rollback изменения не применяются.
 func TestTx_Rollback(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -78,7 +79,7 @@ func TestTx_Rollback(t *testing.T) {
 // (PostgreSQL default isolation = Read Committed).
 func TestTx_Isolation(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -97,9 +98,9 @@ func TestTx_Isolation(t *testing.T) {
 		}
 		require.NoError(t, repo.Insert(txCtx, &p))
 
-		// Чтение через отдельный pool (pgx5Pool) — другой коннект, не в транзакции.
+		// Чтение через отдельный пул (ab.db) — другой коннект, не в транзакции.
 		var exists bool
-		err = pgx5Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, p.ID).Scan(&exists)
+		err = ab.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`, p.ID).Scan(&exists)
 		require.NoError(t, err)
 		assert.False(t, exists, "uncommitted row must not be visible to other connections")
 
@@ -116,7 +117,7 @@ func TestTx_Isolation(t *testing.T) {
 // RollbackUnlessCommitted должен быть no-op (не возвращать ошибку).
 func TestTx_RollbackUnlessCommitted_AfterCommit(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -148,7 +149,7 @@ func TestTx_RollbackUnlessCommitted_AfterCommit(t *testing.T) {
 // TestTx_RollbackUnlessCommitted_WithoutCommit — без Commit() метод откатывает.
 func TestTx_RollbackUnlessCommitted_WithoutCommit(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -173,3 +174,148 @@ func TestTx_RollbackUnlessCommitted_WithoutCommit(t *testing.T) {
 		assert.True(t, errors.Is(err, gerpo.ErrNotFound))
 	})
 }
+
+// TestTx_Savepoint — ошибка внутри вложенного RunInTx откатывает только его
+// работу: внешняя транзакция остаётся рабочей и коммитится.
+func TestTx_Savepoint(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		newPost := func(userID uuid.UUID, title string) *Post {
+			return &Post{ID: uuid.New(), UserID: userID, Title: title, Content: "c", CreatedAt: time.Now().UTC()}
+		}
+		kept := newPost(seed.users[0].ID, "sp-kept")
+		undone := newPost(seed.users[0].ID, "sp-undone")
+		err := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			if err := repo.Insert(ctx, kept); err != nil {
+				return err
+			}
+			innerErr := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+				if err := repo.Insert(ctx, undone); err != nil {
+					return err
+				}
+				// FK violation: на PostgreSQL ломает транзакцию до ROLLBACK TO.
+				return repo.Insert(ctx, newPost(uuid.New(), "sp-orphan"))
+			})
+			require.Error(t, innerErr)
+			return gerpo.RunInSavepoint(ctx, func(ctx context.Context) error {
+				kept.Title = "sp-kept-updated"
+				_, err := repo.Update(ctx, kept, func(m *Post, h query.UpdateHelper[Post]) {
+					h.Where().Field(&m.ID).EQ(kept.ID)
+				})
+				return err
+			})
+		})
+		require.NoError(t, err)
+
+		got, err := repo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(kept.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "sp-kept-updated", got.Title)
+
+		_, err = repo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(undone.ID)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrNotFound, "the savepoint rollback must undo the inner insert")
+	})
+}
+
+// TestTx_Options — RunInTx с TxOptions открывает транзакцию на уровне
+// SERIALIZABLE; запись коммитится как обычно.
+func TestTx_Options(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		p := Post{ID: uuid.New(), UserID: seed.users[0].ID, Title: "tx-serializable", Content: "c", CreatedAt: time.Now().UTC()}
+		err := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			return repo.Insert(ctx, &p)
+		}, gerpo.TxOptions{Isolation: gerpo.LevelSerializable})
+		require.NoError(t, err)
+
+		got, err := repo.GetFirst(ctx, func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(p.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "tx-serializable", got.Title)
+	})
+}
+
+// TestTx_ReadOnly — READ ONLY транзакция отклоняет запись. SQLite режим
+// доступа транзакции не поддерживает.
+func TestTx_ReadOnly(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		requirePostgres(t, ab)
+		seed := defaultSeed(t, ab)
+		repo := newPostRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		p := Post{ID: uuid.New(), UserID: seed.users[0].ID, Title: "tx-read-only", Content: "c", CreatedAt: time.Now().UTC()}
+		err := gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			if _, err := repo.Count(ctx); err != nil {
+				return err
+			}
+			return repo.Insert(ctx, &p)
+		}, gerpo.TxOptions{ReadOnly: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read-only")
+	})
+}
+
+// TestTx_OnCommit — AfterInsert-хук публикует событие через gerpo.OnCommit:
+// событие уходит только после коммита, откаченная вставка его не порождает.
+func TestTx_OnCommit(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		var published []string
+		repo, err := gerpo.New[Post]().
+			Adapter(ab.adapter).
+			Table("posts").
+			Columns(func(m *Post, c *gerpo.ColumnBuilder[Post]) {
+				c.Field(&m.ID).OmitOnUpdate()
+				c.Field(&m.UserID)
+				c.Field(&m.Title)
+				c.Field(&m.Content)
+				c.Field(&m.Published)
+				c.Field(&m.PublishedAt)
+				c.Field(&m.CreatedAt).OmitOnUpdate()
+			}).
+			WithAfterInsert(func(ctx context.Context, m *Post) error {
+				title := m.Title
+				return gerpo.OnCommit(ctx, func() { published = append(published, title) })
+			}).
+			Build()
+		require.NoError(t, err)
+
+		errAbort := errors.New("abort")
+		err = gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			p := Post{ID: uuid.New(), UserID: seed.users[0].ID, Title: "tx-on-commit-rolled-back", Content: "c", CreatedAt: time.Now().UTC()}
+			if err := repo.Insert(ctx, &p); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		err = gerpo.RunInTx(ctx, ab.adapter, func(ctx context.Context) error {
+			p := Post{ID: uuid.New(), UserID: seed.users[0].ID, Title: "tx-on-commit", Content: "c", CreatedAt: time.Now().UTC()}
+			if err := repo.Insert(ctx, &p); err != nil {
+				return err
+			}
+			assert.Empty(t, published, "the event must wait for the commit")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tx-on-commit"}, published)
+	})
+}
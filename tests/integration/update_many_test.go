@@ -0,0 +1,96 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateMany_RoundTrip — пакетный UPDATE обновляет строки по ключу на всех
+// адаптерах; модель без строки в таблице пропускается и не считается.
+func TestUpdateMany_RoundTrip(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newUserRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		first, second := seed.users[0], seed.users[1]
+		first.Name, first.Age = "Renamed 0", 90
+		second.Name, second.Age = "Renamed 1", 91
+		missing := User{ID: uuid.New(), Name: "ghost", CreatedAt: nowUTC()}
+
+		n, err := repo.UpdateMany(ctx, []*User{&first, &second, &missing}, func(m *User, h query.UpdateManyHelper[User]) {
+			h.Key(&m.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		for _, want := range []User{first, second} {
+			got, err := repo.GetFirst(ctx, func(m *User, h query.GetFirstHelper[User]) {
+				h.Where().Field(&m.ID).EQ(want.ID)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, want.Name, got.Name)
+			assert.Equal(t, want.Age, got.Age)
+		}
+	})
+}
+
+// TestUpdateMany_ReturningAndHooks — хуки видят весь срез, RETURNING пишет
+// значения обратно в модели по ключу.
+func TestUpdateMany_ReturningAndHooks(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		var beforeCalls, afterCalls int
+		repo, err := gerpo.New[User]().
+			Adapter(ab.adapter).
+			Table("users").
+			Columns(func(m *User, c *gerpo.ColumnBuilder[User]) {
+				c.Field(&m.ID).OmitOnUpdate()
+				c.Field(&m.Name)
+				c.Field(&m.Email)
+				c.Field(&m.Age)
+				c.Field(&m.CreatedAt).OmitOnUpdate().ReturnedOnUpdate()
+				c.Field(&m.UpdatedAt).OmitOnInsert()
+				c.Field(&m.DeletedAt).OmitOnInsert()
+			}).
+			WithBeforeUpdateMany(func(_ context.Context, models []*User) error {
+				beforeCalls++
+				now := nowUTC()
+				for _, m := range models {
+					m.UpdatedAt = &now
+				}
+				return nil
+			}).
+			WithAfterUpdateMany(func(_ context.Context, models []*User) error {
+				afterCalls++
+				assert.Len(t, models, 2)
+				return nil
+			}).
+			Build()
+		require.NoError(t, err)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		batch := []*User{{ID: seed.users[2].ID, Name: "a"}, {ID: seed.users[3].ID, Name: "b"}}
+		n, err := repo.UpdateMany(ctx, batch, func(m *User, h query.UpdateManyHelper[User]) {
+			h.Key(&m.ID)
+			h.Only(&m.Name, &m.UpdatedAt)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, 1, beforeCalls)
+		assert.Equal(t, 1, afterCalls)
+		assert.True(t, batch[0].CreatedAt.Equal(seed.users[2].CreatedAt))
+		assert.True(t, batch[1].CreatedAt.Equal(seed.users[3].CreatedAt))
+		assert.NotNil(t, batch[0].UpdatedAt)
+	})
+}
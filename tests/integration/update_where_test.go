@@ -0,0 +1,44 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateWhere_Increment — UPDATE без модели: выражение считается на стороне
+// БД, persistent query (soft delete) продолжает действовать.
+func TestUpdateWhere_Increment(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo := newUserRepo(t, ab)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		target := seed.users[0]
+		_, n, err := repo.UpdateWhere(ctx, func(m *User, h query.UpdateHelper[User]) {
+			h.Increment(&m.Age, 5)
+			h.Where().Field(&m.ID).EQ(target.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		got, err := repo.GetFirst(ctx, func(m *User, h query.GetFirstHelper[User]) {
+			h.Where().Field(&m.ID).EQ(target.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, target.Age+5, got.Age)
+
+		_, _, err = repo.UpdateWhere(ctx, func(m *User, h query.UpdateHelper[User]) {
+			h.Set(&m.Name, "nobody")
+			h.Where().Field(&m.Age).GT(1000)
+		})
+		assert.True(t, errors.Is(err, gerpo.ErrNotFound), "got %v", err)
+	})
+}
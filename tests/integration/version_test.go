@@ -0,0 +1,119 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedPost — проекция posts с колонкой version для optimistic locking.
+type versionedPost struct {
+	ID      uuid.UUID
+	Title   string
+	Version int
+}
+
+// TestVersion_LostUpdate — второй писатель со старой версией получает
+// ErrStaleVersion, а не перезаписывает строку.
+func TestVersion_LostUpdate(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo, err := gerpo.New[versionedPost]().
+			Adapter(ab.adapter).
+			Table("posts").
+			Columns(func(m *versionedPost, c *gerpo.ColumnBuilder[versionedPost]) {
+				c.Field(&m.ID).OmitOnUpdate()
+				c.Field(&m.Title)
+				c.Field(&m.Version).Version()
+			}).
+			Build()
+		require.NoError(t, err)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		byID := func(m *versionedPost, h query.GetFirstHelper[versionedPost]) {
+			h.Where().Field(&m.ID).EQ(seed.posts[0].ID)
+		}
+		first, err := repo.GetFirst(ctx, byID)
+		require.NoError(t, err)
+		second, err := repo.GetFirst(ctx, byID)
+		require.NoError(t, err)
+
+		first.Title = "first writer"
+		_, err = repo.Update(ctx, first, func(m *versionedPost, h query.UpdateHelper[versionedPost]) {
+			h.Where().Field(&m.ID).EQ(first.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, second.Version+1, first.Version)
+
+		second.Title = "second writer"
+		_, err = repo.Update(ctx, second, func(m *versionedPost, h query.UpdateHelper[versionedPost]) {
+			h.Where().Field(&m.ID).EQ(second.ID)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+
+		got, err := repo.GetFirst(ctx, byID)
+		require.NoError(t, err)
+		assert.Equal(t, "first writer", got.Title)
+		assert.Equal(t, first.Version, got.Version)
+	})
+}
+
+// TestVersion_UpdateMany — UpdateMany сверяет версию каждой строки: пачка с
+// устаревшей моделью возвращает ErrStaleVersion, актуальная — поднимает версии.
+func TestVersion_UpdateMany(t *testing.T) {
+	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
+		seed := defaultSeed(t, ab)
+		repo, err := gerpo.New[versionedPost]().
+			Adapter(ab.adapter).
+			Table("posts").
+			Columns(func(m *versionedPost, c *gerpo.ColumnBuilder[versionedPost]) {
+				c.Field(&m.ID).OmitOnUpdate()
+				c.Field(&m.Title)
+				c.Field(&m.Version).Version()
+			}).
+			Build()
+		require.NoError(t, err)
+		ctx, cancel := testCtx(t)
+		defer cancel()
+
+		byKey := func(m *versionedPost, h query.UpdateManyHelper[versionedPost]) { h.Key(&m.ID) }
+		read := func(i int) *versionedPost {
+			got, err := repo.GetFirst(ctx, func(m *versionedPost, h query.GetFirstHelper[versionedPost]) {
+				h.Where().Field(&m.ID).EQ(seed.posts[i].ID)
+			})
+			require.NoError(t, err)
+			return got
+		}
+		first, second := read(0), read(1)
+		stale := read(1)
+
+		first.Title, second.Title = "batch", "batch"
+		_, err = repo.UpdateMany(ctx, []*versionedPost{first, second}, byKey)
+		require.NoError(t, err)
+		assert.Equal(t, stale.Version+1, second.Version)
+		assert.Equal(t, second.Version, read(1).Version)
+
+		stale.Title = "stale"
+		_, err = repo.UpdateMany(ctx, []*versionedPost{stale}, byKey)
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+		assert.Equal(t, "batch", read(1).Title)
+
+		// Устаревшая строка откатывает весь батч: first остаётся в базе и в памяти
+		// на прежней версии, и следующий UpdateMany с ним проходит.
+		first.Title = "mixed"
+		_, err = repo.UpdateMany(ctx, []*versionedPost{first, stale}, byKey)
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+		assert.Equal(t, "batch", read(0).Title)
+		assert.Equal(t, read(0).Version, first.Version)
+		_, err = repo.UpdateMany(ctx, []*versionedPost{first}, byKey)
+		require.NoError(t, err)
+		assert.Equal(t, "mixed", read(0).Title)
+	})
+}
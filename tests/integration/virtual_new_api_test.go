@@ -105,7 +105,7 @@ func newUserRepoAggregate(t *testing.T, ab adapterBundle) gerpo.Repository[User]
 // adapter for a straightforward aggregate expression over a JOIN.
 func TestVirtual_NewAPI_ComputeDropIn(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepoComputeDropIn(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -124,7 +124,7 @@ func TestVirtual_NewAPI_ComputeDropIn(t *testing.T) {
 // produces exactly one row.
 func TestVirtual_NewAPI_ComputeWithBoundArgs(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepoComputeWithArgs(t, ab, "%Post 4%")
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -143,7 +143,7 @@ func TestVirtual_NewAPI_ComputeWithBoundArgs(t *testing.T) {
 // is registered for that operator.
 func TestVirtual_NewAPI_AggregateRejectsAutoFilter(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepoAggregate(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -164,7 +164,7 @@ func TestVirtual_NewAPI_AggregateRejectsAutoFilter(t *testing.T) {
 // — gerpo deliberately leaves SQL validity to the override author).
 func TestVirtual_NewAPI_AggregateAcceptsExplicitFilter(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo, err := gerpo.New[User]().
 			Adapter(ab.adapter).
 			Table("users").
@@ -17,7 +17,7 @@ import (
 // без ошибки, а следующий SELECT возвращает реальное значение (0, т.к. постов нет).
 func TestVirtual_ReadOnlyOnInsert(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -42,7 +42,7 @@ func TestVirtual_ReadOnlyOnInsert(t *testing.T) {
 // TestVirtual_ReadOnlyOnUpdate — попытка обновить PostCount через Update не меняет ничего.
 func TestVirtual_ReadOnlyOnUpdate(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -68,7 +68,7 @@ func TestVirtual_ReadOnlyOnUpdate(t *testing.T) {
 // состояние связанных данных. После удаления поста post_count уменьшается.
 func TestVirtual_ComputedValueReflectsState(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		userRepo := newUserRepo(t, ab)
 		postRepo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
@@ -14,7 +14,7 @@ import (
 // TestWhere_EQ — точное совпадение по uuid и строке.
 func TestWhere_EQ(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -30,7 +30,7 @@ func TestWhere_EQ(t *testing.T) {
 // TestWhere_NEQ — отрицание: все, кроме одной записи.
 func TestWhere_NEQ(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -47,7 +47,7 @@ func TestWhere_NEQ(t *testing.T) {
 // users 0..9 имеют age 20..29 соответственно.
 func TestWhere_LT_LTE_GT_GTE(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -75,7 +75,7 @@ func TestWhere_LT_LTE_GT_GTE(t *testing.T) {
 // TestWhere_IN_NIN — проверка IN/NIN по набору uuid.
 func TestWhere_IN_NIN(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -100,7 +100,7 @@ func TestWhere_IN_NIN(t *testing.T) {
 // users: у чётных индексов есть email, у нечётных — NULL.
 func TestWhere_EQ_Nil(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -115,7 +115,7 @@ func TestWhere_EQ_Nil(t *testing.T) {
 
 func TestWhere_NEQ_Nil(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newUserRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -132,7 +132,7 @@ func TestWhere_NEQ_Nil(t *testing.T) {
 // Формат "Post N" для N = 0..29.
 func TestWhere_LIKE_Operators(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -168,7 +168,7 @@ func TestWhere_LIKE_Operators(t *testing.T) {
 // TestWhere_LIKE_IgnoreCase — регистронезависимые варианты LIKE-операторов.
 func TestWhere_LIKE_IgnoreCase(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		defaultSeed(t)
+		defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -194,7 +194,7 @@ func TestWhere_LIKE_IgnoreCase(t *testing.T) {
 // (bool, uuid, int), а LIKE-семейство тестируется отдельно в TestWhere_LIKE_Operators.
 func TestWhere_AND(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -213,7 +213,7 @@ func TestWhere_AND(t *testing.T) {
 // TestWhere_OR — OR между двумя полями.
 func TestWhere_OR(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
@@ -232,7 +232,7 @@ func TestWhere_OR(t *testing.T) {
 // TestWhere_Group — группировка скобками меняет приоритет логики.
 func TestWhere_Group(t *testing.T) {
 	forEachAdapter(t, func(t *testing.T, ab adapterBundle) {
-		seed := defaultSeed(t)
+		seed := defaultSeed(t, ab)
 		repo := newPostRepo(t, ab)
 		ctx, cancel := testCtx(t)
 		defer cancel()
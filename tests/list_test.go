@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+)
+
+func TestGetList_Keyset(t *testing.T) {
+	type Task struct {
+		ID        uuid.UUID
+		CreatedAt time.Time
+		Title     string
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	repo, err := gerpo.New[Task]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("tasks").
+		Columns(func(m *Task, columns *gerpo.ColumnBuilder[Task]) {
+			columns.Field(&m.ID)
+			columns.Field(&m.CreatedAt)
+			columns.Field(&m.Title)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when building repository", err)
+	}
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	page := func(cursor string, next *string) func(m *Task, h query.GetListHelper[Task]) {
+		return func(m *Task, h query.GetListHelper[Task]) {
+			h.OrderBy().Field(&m.CreatedAt).DESC().Field(&m.ID).ASC()
+			h.Size(2).After(cursor).NextCursor(next)
+		}
+	}
+
+	// First page: no cursor condition, a full page hands out the next cursor.
+	mockDB.ExpectQuery(`SELECT tasks.id, tasks.created_at, tasks.title FROM tasks ORDER BY tasks.created_at DESC, tasks.id ASC LIMIT 2`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "title"}).
+			AddRow(first, createdAt.Add(time.Hour), "a").
+			AddRow(second, createdAt, "b"))
+	var next string
+	if _, err = repo.GetList(context.Background(), page("", &next)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if next == "" {
+		t.Fatal("expected a next cursor after a full page")
+	}
+
+	// Second page: mixed directions expand into an OR chain keyed on the last row.
+	mockDB.ExpectQuery(`SELECT tasks.id, tasks.created_at, tasks.title FROM tasks WHERE \(\(tasks.created_at < \?\) OR \(tasks.created_at = \? AND tasks.id > \?\)\) ORDER BY tasks.created_at DESC, tasks.id ASC LIMIT 2`).
+		WithArgs(createdAt, createdAt, second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "title"}).
+			AddRow(uuid.New(), createdAt.Add(-time.Hour), "c"))
+	cursor := next
+	if _, err = repo.GetList(context.Background(), page(cursor, &next)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if next != "" {
+		t.Fatalf("expected no next cursor after a short page, got %q", next)
+	}
+
+	// A malformed cursor never reaches the database.
+	_, err = repo.GetList(context.Background(), page("garbage", &next))
+	if !errors.Is(err, gerpo.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+
+	// Page and a keyset cursor are mutually exclusive.
+	_, err = repo.GetList(context.Background(), func(m *Task, h query.GetListHelper[Task]) {
+		h.OrderBy().Field(&m.ID).ASC()
+		h.Page(2).Size(2).After(cursor)
+	})
+	if !errors.Is(err, query.ErrApplyKeysetPagination) {
+		t.Fatalf("expected ErrApplyKeysetPagination, got %v", err)
+	}
+
+	if err = mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
 	extypes "github.com/insei/gerpo/executor/types"
 )
@@ -28,6 +29,10 @@ func (m *mockDB) QueryContext(ctx context.Context, query string, args ...any) (e
 	panic("implement me")
 }
 
+func (m *mockDB) Dialect() dialect.Dialect {
+	return dialect.PostgreSQL
+}
+
 type mockRows struct {
 	alwaysNext   bool
 	current, max int
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQL(t *testing.T) {
+	type Post struct {
+		ID        int
+		Title     string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Post]().
+		Adapter(databasesql.NewMySQLAdapter(db)).
+		Table("posts").
+		Columns(func(m *Post, columns *gerpo.ColumnBuilder[Post]) {
+			columns.Field(&m.ID).PrimaryKey().ReadOnly().ReturnedOnInsert()
+			columns.Field(&m.Title)
+			columns.Field(&m.CreatedAt).ReadOnly().ReturnedOnInsert()
+			columns.Field(&m.UpdatedAt).ReadOnly().ReturnedOnUpdate()
+			columns.Field(&m.DeletedAt).ReadOnly()
+		}).
+		WithQuery(func(m *Post, h query.PersistentHelper[Post]) {
+			h.Where().Field(&m.DeletedAt).EQ(nil)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("text operators cast to CHAR", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT .* FROM posts WHERE \(posts.deleted_at IS NULL\) AND \(posts.title COLLATE utf8mb4_bin LIKE CONCAT\('%', CAST\(\? AS CHAR\), '%'\) AND LOWER\(posts.title\) = LOWER\(CAST\(\? AS CHAR\)\)\)`).
+			WithArgs("go", "Go").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at", "updated_at", "deleted_at"}))
+
+		_, err := repo.GetList(context.Background(), func(m *Post, h query.GetListHelper[Post]) {
+			h.Where().Field(&m.Title).Contains("go").AND().Field(&m.Title).EQFold("Go")
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Insert takes the key from LastInsertId and selects the rest", func(t *testing.T) {
+		mockDB.ExpectExec(`^INSERT INTO posts \(title\) VALUES \(\?\)$`).
+			WithArgs("hello").
+			WillReturnResult(sqlmock.NewResult(42, 1))
+		mockDB.ExpectQuery(`^SELECT id, created_at FROM posts WHERE id IN \(\?\)$`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(42, createdAt))
+
+		post := &Post{Title: "hello"}
+		require.NoError(t, repo.Insert(context.Background(), post))
+		assert.Equal(t, 42, post.ID)
+		assert.Equal(t, createdAt, post.CreatedAt)
+	})
+
+	t.Run("Update selects the returned columns by the model key", func(t *testing.T) {
+		mockDB.ExpectExec(`^UPDATE posts SET title = \? WHERE \(posts.deleted_at IS NULL\) AND \(posts.id = \?\)$`).
+			WithArgs("edited", 42).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectQuery(`^SELECT id, updated_at FROM posts WHERE id IN \(\?\)$`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(42, updatedAt))
+
+		post := &Post{ID: 42, Title: "edited"}
+		_, err := repo.Update(context.Background(), post)
+		require.NoError(t, err)
+		assert.Equal(t, updatedAt, post.UpdatedAt)
+	})
+
+	t.Run("UpdateMany joins the rows in and reads back by key", func(t *testing.T) {
+		mockDB.ExpectExec(`^UPDATE posts JOIN \(SELECT id, title FROM posts WHERE false UNION ALL SELECT \?, \? UNION ALL SELECT \?, \?\) AS v ON posts.id = v.id SET posts.title = v.title WHERE \(\(posts.deleted_at IS NULL\)\)$`).
+			WithArgs(1, "a", 2, "b").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mockDB.ExpectQuery(`^SELECT id, updated_at FROM posts WHERE id IN \(\?,\?\)$`).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "updated_at"}).AddRow(2, updatedAt).AddRow(1, updatedAt))
+
+		posts := []*Post{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}}
+		n, err := repo.UpdateMany(context.Background(), posts, func(m *Post, h query.UpdateManyHelper[Post]) {
+			h.Only(&m.Title)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, updatedAt, posts[0].UpdatedAt)
+		assert.Equal(t, updatedAt, posts[1].UpdatedAt)
+	})
+
+	t.Run("InsertMany cannot read back generated keys", func(t *testing.T) {
+		_, err := repo.InsertMany(context.Background(), []*Post{{Title: "a"}, {Title: "b"}})
+		assert.ErrorIs(t, err, gerpo.ErrReturningNotSupported)
+	})
+
+	t.Run("Upsert and OnConflict specs are rejected before any SQL", func(t *testing.T) {
+		err := repo.Upsert(context.Background(), &Post{Title: "a"}, func(m *Post, h query.InsertHelper[Post]) {
+			h.OnConflict(&m.ID).DoUpdate(&m.Title)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+		err = repo.Insert(context.Background(), &Post{Title: "a"}, func(m *Post, h query.InsertHelper[Post]) {
+			h.OnConflict(&m.ID).DoNothing()
+		})
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+		_, err = repo.InsertMany(context.Background(), []*Post{{ID: 1, Title: "a"}}, func(m *Post, h query.InsertManyHelper[Post]) {
+			h.OnConflict(&m.ID).DoNothing()
+		})
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+	})
+
+	t.Run("UpdateWhere with Returning is rejected", func(t *testing.T) {
+		_, _, err := repo.UpdateWhere(context.Background(), func(m *Post, h query.UpdateHelper[Post]) {
+			h.Set(&m.Title, "x")
+			h.Returning(&m.ID)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrReturningNotSupported)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimaryKey(t *testing.T) {
+	type Doc struct {
+		ID        int
+		Title     string
+		DeletedAt *time.Time
+	}
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Doc]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("docs").
+		Columns(func(m *Doc, columns *gerpo.ColumnBuilder[Doc]) {
+			columns.Field(&m.ID).PrimaryKey().OmitOnUpdate()
+			columns.Field(&m.Title)
+			columns.Field(&m.DeletedAt)
+		}).
+		WithQuery(func(m *Doc, h query.PersistentHelper[Doc]) {
+			h.Where().Field(&m.DeletedAt).EQ(nil)
+		}).
+		WithSoftDeletion(func(m *Doc, softDeletion *gerpo.SoftDeletionBuilder[Doc]) {
+			softDeletion.Field(&m.DeletedAt).SetValueFn(func(ctx context.Context) any {
+				return &deletedAt
+			})
+		}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("GetByID matches the key under the persistent query", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT docs.id, docs.title, docs.deleted_at FROM docs WHERE \(docs.deleted_at IS NULL\) AND \(docs.id = \?\) LIMIT 1`).
+			WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow(7, "a", nil))
+
+		doc, err := repo.GetByID(context.Background(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, "a", doc.Title)
+	})
+
+	t.Run("GetByID of a missing row is ErrNotFound", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT .* FROM docs WHERE .*docs.id = \?`).
+			WithArgs(8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}))
+
+		_, err := repo.GetByID(context.Background(), 8)
+		assert.ErrorIs(t, err, gerpo.ErrNotFound)
+	})
+
+	t.Run("GetByIDs renders IN", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT docs.id, docs.title, docs.deleted_at FROM docs WHERE \(docs.deleted_at IS NULL\) AND \(docs.id IN \(\?,\?,\?\)\)`).
+			WithArgs(1, 2, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow(1, "a", nil).AddRow(3, "c", nil))
+
+		docs, err := repo.GetByIDs(context.Background(), 1, 2, 3)
+		require.NoError(t, err)
+		assert.Len(t, docs, 2)
+	})
+
+	t.Run("GetByID checks the number of key values", func(t *testing.T) {
+		_, err := repo.GetByID(context.Background(), 1, 2)
+		assert.ErrorIs(t, err, gerpo.ErrApplyQuery)
+	})
+
+	t.Run("Update without WHERE matches the model key", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET title = \?, deleted_at = \? WHERE \(docs.deleted_at IS NULL\) AND \(docs.id = \?\)`).
+			WithArgs("b", nil, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repo.Update(context.Background(), &Doc{ID: 7, Title: "b"})
+		require.NoError(t, err)
+	})
+
+	t.Run("Update with WHERE keeps it", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET title = \?, deleted_at = \? WHERE \(docs.deleted_at IS NULL\) AND \(docs.title = \?\)`).
+			WithArgs("b", nil, "a").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		_, err := repo.Update(context.Background(), &Doc{ID: 7, Title: "b"}, func(m *Doc, h query.UpdateHelper[Doc]) {
+			h.Where().Field(&m.Title).EQ("a")
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("DeleteByID goes through soft deletion", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET deleted_at = \? WHERE \(docs.deleted_at IS NULL\) AND \(docs.id = \?\)`).
+			WithArgs(&deletedAt, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repo.DeleteByID(context.Background(), 7))
+	})
+
+	t.Run("UpdateMany matches rows on the primary key", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET title = v.title FROM \(SELECT id, title FROM docs WHERE false UNION ALL SELECT \?, \?\) AS v WHERE docs.id = v.id`).
+			WithArgs(7, "c").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repo.UpdateMany(context.Background(), []*Doc{{ID: 7, Title: "c"}}, func(m *Doc, h query.UpdateManyHelper[Doc]) {
+			h.Only(&m.Title)
+		})
+		require.NoError(t, err)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestPrimaryKey_Composite(t *testing.T) {
+	type Member struct {
+		OrgID  int
+		UserID int
+		Role   string
+	}
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Member]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("members").
+		Columns(func(m *Member, columns *gerpo.ColumnBuilder[Member]) {
+			columns.Field(&m.OrgID).PrimaryKey()
+			columns.Field(&m.UserID).PrimaryKey()
+			columns.Field(&m.Role)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("GetByID takes the values in declaration order", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT .* FROM members WHERE \(members.org_id = \? AND members.user_id = \?\) LIMIT 1`).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"org_id", "user_id", "role"}).AddRow(1, 2, "owner"))
+
+		m, err := repo.GetByID(context.Background(), 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "owner", m.Role)
+	})
+
+	t.Run("GetByIDs ORs the key groups", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT .* FROM members WHERE \(\(members.org_id = \? AND members.user_id = \?\) OR \(members.org_id = \? AND members.user_id = \?\)\)`).
+			WithArgs(1, 2, 1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"org_id", "user_id", "role"}).AddRow(1, 2, "owner"))
+
+		ms, err := repo.GetByIDs(context.Background(), []any{1, 2}, []any{1, 3})
+		require.NoError(t, err)
+		assert.Len(t, ms, 1)
+	})
+
+	t.Run("GetByIDs rejects a bare value for a composite key", func(t *testing.T) {
+		_, err := repo.GetByIDs(context.Background(), 1)
+		assert.ErrorIs(t, err, gerpo.ErrApplyQuery)
+	})
+
+	t.Run("DeleteByID", func(t *testing.T) {
+		mockDB.ExpectExec(`DELETE FROM members WHERE \(members.org_id = \? AND members.user_id = \?\)`).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeleteByID(context.Background(), 1, 2), gerpo.ErrNotFound)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestPrimaryKey_Missing(t *testing.T) {
+	type Doc struct {
+		ID int
+	}
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Doc]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("docs").
+		Columns(func(m *Doc, columns *gerpo.ColumnBuilder[Doc]) {
+			columns.Field(&m.ID)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, gerpo.ErrNoPrimaryKey)
+	assert.ErrorIs(t, repo.DeleteByID(context.Background(), 1), gerpo.ErrNoPrimaryKey)
+}
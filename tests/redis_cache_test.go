@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/executor/cache/redis"
+	"github.com/insei/gerpo/query"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCache_ConcurrentMissOfAbsentRow(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+	const readers = 4
+	client := goredis.NewClient(&goredis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	store := redis.NewStore(client, redis.WithFillLock(5*time.Second))
+	t.Cleanup(func() { _ = store.Close() })
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	mockDB.MatchExpectationsInOrder(false)
+	repo, err := gerpo.New[User]().
+		Adapter(databasesql.NewAdapter(db), executor.WithCacheStorage(redis.NewCache[User](store, ""))).
+		Table("users").
+		Columns(func(m *User, columns *gerpo.ColumnBuilder[User]) {
+			columns.Field(&m.ID)
+			columns.Field(&m.Name)
+		}).
+		Build()
+	require.NoError(t, err)
+	for range readers {
+		mockDB.ExpectQuery(`SELECT users.id, users.name FROM users WHERE \(users.id = \?\) LIMIT 1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	}
+
+	// The reader holding the fill lock finds no row and drops the lock, so the
+	// others stop waiting for an entry that never comes.
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, readers)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = repo.GetFirst(context.Background(), func(m *User, h query.GetFirstHelper[User]) {
+				h.Where().Field(&m.ID).EQ(1)
+			})
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.ErrorIs(t, err, gerpo.ErrNotFound)
+	}
+	assert.Less(t, time.Since(start), time.Second, "the misses do not wait out the fill lock")
+}
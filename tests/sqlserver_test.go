@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLServer(t *testing.T) {
+	type Post struct {
+		ID        int
+		Title     string
+		Order     int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Post]().
+		Adapter(databasesql.NewSQLServerAdapter(db)).
+		Table("posts").
+		Columns(func(m *Post, columns *gerpo.ColumnBuilder[Post]) {
+			columns.Field(&m.ID).PrimaryKey().ReadOnly().ReturnedOnInsert()
+			columns.Field(&m.Title)
+			columns.Field(&m.Order).WithColumnName("order")
+			columns.Field(&m.CreatedAt).ReadOnly().ReturnedOnInsert()
+			columns.Field(&m.UpdatedAt).ReadOnly().ReturnedOnUpdate()
+			columns.Field(&m.DeletedAt).ReadOnly()
+		}).
+		WithQuery(func(m *Post, h query.PersistentHelper[Post]) {
+			h.Where().Field(&m.DeletedAt).EQ(nil)
+		}).
+		Build()
+	require.NoError(t, err)
+	selectCols := `posts.id, posts.title, posts.\[order\], posts.created_at, posts.updated_at, posts.deleted_at`
+
+	t.Run("text operators cast to NVARCHAR and compare under a case-sensitive collation", func(t *testing.T) {
+		mockDB.ExpectQuery(`SELECT .* FROM posts WHERE \(posts.deleted_at IS NULL\) AND \(posts.title COLLATE Latin1_General_CS_AS LIKE CONCAT\('%', CAST\(@p1 AS NVARCHAR\(MAX\)\), '%'\) AND LOWER\(posts.title\) = LOWER\(CAST\(@p2 AS NVARCHAR\(MAX\)\)\)\)`).
+			WithArgs("go", "Go").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "order", "created_at", "updated_at", "deleted_at"}))
+
+		_, err := repo.GetList(context.Background(), func(m *Post, h query.GetListHelper[Post]) {
+			h.Where().Field(&m.Title).Contains("go").AND().Field(&m.Title).EQFold("Go")
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetPage pages with OFFSET / FETCH after the ORDER BY", func(t *testing.T) {
+		mockDB.ExpectQuery(`^SELECT ` + selectCols + `, count\(\*\) over\(\) AS count FROM posts WHERE \(posts.deleted_at IS NULL\) ORDER BY posts.id DESC OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "order", "created_at", "updated_at", "deleted_at", "count"}))
+		mockDB.ExpectQuery(`^SELECT ` + selectCols + `, count\(\*\) over\(\) AS count FROM posts WHERE \(posts.deleted_at IS NULL\) ORDER BY posts.id DESC OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY$`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "order", "created_at", "updated_at", "deleted_at", "count"}).
+				AddRow(20, "last", 0, createdAt, updatedAt, nil, 20))
+
+		_, total, err := repo.GetPage(context.Background(), func(m *Post, h query.GetListHelper[Post]) {
+			h.OrderBy().Field(&m.ID).DESC()
+			h.Page(3).Size(10)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20), total)
+	})
+
+	t.Run("Count without ORDER BY orders by nothing", func(t *testing.T) {
+		mockDB.ExpectQuery(`^SELECT count\(\*\) over\(\) AS count FROM posts WHERE \(posts.deleted_at IS NULL\) ORDER BY \(SELECT NULL\) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(20))
+
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20), count)
+	})
+
+	t.Run("GetFirst without ORDER BY gets one too", func(t *testing.T) {
+		mockDB.ExpectQuery(`^SELECT ` + selectCols + ` FROM posts WHERE \(posts.deleted_at IS NULL\) AND \(posts.id = @p1\) ORDER BY \(SELECT NULL\) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY$`).
+			WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "order", "created_at", "updated_at", "deleted_at"}).
+				AddRow(42, "hello", 1, createdAt, updatedAt, nil))
+
+		post, err := repo.GetFirst(context.Background(), func(m *Post, h query.GetFirstHelper[Post]) {
+			h.Where().Field(&m.ID).EQ(42)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", post.Title)
+	})
+
+	t.Run("Insert hands the columns back with OUTPUT INSERTED", func(t *testing.T) {
+		mockDB.ExpectQuery(`^INSERT INTO posts \(title, \[order\]\) OUTPUT INSERTED.id, INSERTED.created_at VALUES \(@p1,@p2\)$`).
+			WithArgs("hello", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(42, createdAt))
+
+		post := &Post{Title: "hello", Order: 1}
+		require.NoError(t, repo.Insert(context.Background(), post))
+		assert.Equal(t, 42, post.ID)
+		assert.Equal(t, createdAt, post.CreatedAt)
+	})
+
+	t.Run("Update puts OUTPUT between SET and WHERE", func(t *testing.T) {
+		mockDB.ExpectQuery(`^UPDATE posts SET title = @p1, \[order\] = @p2 OUTPUT INSERTED.updated_at WHERE \(posts.deleted_at IS NULL\) AND \(posts.id = @p3\)$`).
+			WithArgs("edited", 2, 42).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+
+		post := &Post{ID: 42, Title: "edited", Order: 2}
+		_, err := repo.Update(context.Background(), post)
+		require.NoError(t, err)
+		assert.Equal(t, updatedAt, post.UpdatedAt)
+	})
+
+	t.Run("InsertMany lists OUTPUT before VALUES", func(t *testing.T) {
+		mockDB.ExpectQuery(`^INSERT INTO posts \(title, \[order\]\) OUTPUT INSERTED.id, INSERTED.created_at VALUES \(@p1,@p2\), \(@p3,@p4\)$`).
+			WithArgs("a", 1, "b", 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt).AddRow(2, createdAt))
+
+		posts := []*Post{{Title: "a", Order: 1}, {Title: "b", Order: 2}}
+		n, err := repo.InsertMany(context.Background(), posts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, 2, posts[1].ID)
+	})
+
+	t.Run("Upsert and OnConflict specs are rejected before any SQL", func(t *testing.T) {
+		err := repo.Upsert(context.Background(), &Post{Title: "a"}, func(m *Post, h query.InsertHelper[Post]) {
+			h.OnConflict(&m.ID).DoUpdate(&m.Title)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+		_, err = repo.InsertMany(context.Background(), []*Post{{ID: 1, Title: "a"}}, func(m *Post, h query.InsertManyHelper[Post]) {
+			h.OnConflict(&m.ID).DoNothing()
+		})
+		assert.ErrorIs(t, err, gerpo.ErrUpsertNotSupported)
+	})
+
+	t.Run("UpdateMany joins the rows in with FROM and a 1 = 0 seed", func(t *testing.T) {
+		mockDB.ExpectQuery(`^UPDATE posts SET title = v.title OUTPUT INSERTED.updated_at, INSERTED.id FROM \(SELECT id, title FROM posts WHERE 1 = 0 UNION ALL SELECT @p1, @p2 UNION ALL SELECT @p3, @p4\) AS v WHERE posts.id = v.id AND \(\(posts.deleted_at IS NULL\)\)$`).
+			WithArgs(1, "a", 2, "b").
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "id"}).AddRow(updatedAt, 2).AddRow(updatedAt, 1))
+
+		posts := []*Post{{ID: 1, Title: "a"}, {ID: 2, Title: "b"}}
+		n, err := repo.UpdateMany(context.Background(), posts, func(m *Post, h query.UpdateManyHelper[Post]) {
+			h.Only(&m.Title)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, updatedAt, posts[0].UpdatedAt)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMany(t *testing.T) {
+	type Item struct {
+		ID        int
+		Name      string
+		Price     int
+		UpdatedAt time.Time
+		DeletedAt *time.Time
+	}
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	repo, err := gerpo.New[Item]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("items").
+		Columns(func(m *Item, columns *gerpo.ColumnBuilder[Item]) {
+			columns.Field(&m.ID).OmitOnUpdate()
+			columns.Field(&m.Name)
+			columns.Field(&m.Price)
+			columns.Field(&m.UpdatedAt).ReadOnly().ReturnedOnUpdate()
+			columns.Field(&m.DeletedAt).ReadOnly()
+		}).
+		WithQuery(func(m *Item, h query.PersistentHelper[Item]) {
+			h.Where().Field(&m.DeletedAt).EQ(nil)
+		}).
+		Build()
+	require.NoError(t, err)
+
+	t.Run("RETURNING rows are matched by key, not position", func(t *testing.T) {
+		updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		items := []*Item{{ID: 1, Name: "a", Price: 10}, {ID: 2, Name: "b", Price: 20}, {ID: 3, Name: "c", Price: 30}}
+		// Row 2 does not exist and the others come back reversed.
+		mockDB.ExpectQuery(`UPDATE items SET name = v.name, price = v.price FROM \(SELECT id, name, price FROM items WHERE false UNION ALL SELECT \?, \?, \? UNION ALL SELECT \?, \?, \? UNION ALL SELECT \?, \?, \?\) AS v WHERE items.id = v.id AND \(\(items.deleted_at IS NULL\)\) RETURNING items.updated_at, items.id`).
+			WithArgs(1, "a", 10, 2, "b", 20, 3, "c", 30).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at", "id"}).AddRow(updatedAt, 3).AddRow(updatedAt, 1))
+
+		n, err := repo.UpdateMany(context.Background(), items, func(m *Item, h query.UpdateManyHelper[Item]) {
+			h.Key(&m.ID)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+		assert.Equal(t, updatedAt, items[0].UpdatedAt)
+		assert.True(t, items[1].UpdatedAt.IsZero())
+		assert.Equal(t, updatedAt, items[2].UpdatedAt)
+	})
+
+	t.Run("Only narrows SET and WHERE binds its args after the rows", func(t *testing.T) {
+		items := []*Item{{ID: 1, Price: 15}, {ID: 2, Price: 25}}
+		mockDB.ExpectExec(`UPDATE items SET price = v.price FROM \(SELECT id, price FROM items WHERE false UNION ALL SELECT \?, \? UNION ALL SELECT \?, \?\) AS v WHERE items.id = v.id AND \(\(items.deleted_at IS NULL\) AND \(items.name != \?\)\)`).
+			WithArgs(1, 15, 2, 25, "locked").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		n, err := repo.UpdateMany(context.Background(), items, func(m *Item, h query.UpdateManyHelper[Item]) {
+			h.Key(&m.ID)
+			h.Only(&m.Price)
+			h.Where().Field(&m.Name).NotEQ("locked")
+			h.Returning()
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+
+	t.Run("Key is required", func(t *testing.T) {
+		_, err := repo.UpdateMany(context.Background(), []*Item{{ID: 1}})
+		assert.Error(t, err)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -175,6 +176,24 @@ func TestUpdate(t *testing.T) {
 				})
 			},
 		},
+		{
+			name: "Update with explicit assignments",
+			model: &User{
+				ID:        uuid.New(),
+				Name:      "UpdateTest",
+				CreatedAt: time.Now().UTC(),
+			},
+			setupDb: func(mockDB sqlmock.Sqlmock, dateAt time.Time, m *User) {
+				mockDB.ExpectExec(`UPDATE users SET name = \? WHERE \(users.deleted_at IS NULL\) AND \(users.id = \?\)`).
+					WithArgs("Renamed", m.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			repoUpdateFn: func(repo gerpo.Repository[User], user *User) (int64, error) {
+				return repo.Update(context.Background(), user, func(m *User, h query.UpdateHelper[User]) {
+					h.Where().Field(&m.ID).EQ(user.ID)
+					h.Set(&m.Name, "Renamed")
+				})
+			},
+		},
 		// TODO: make update with join support for WHERE clause
 	}
 	for _, test := range tests {
@@ -188,3 +207,81 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateWhere(t *testing.T) {
+	type Counter struct {
+		ID        int
+		Hits      int
+		Status    string
+		CreatedAt time.Time
+		DeletedAt *time.Time
+	}
+
+	db, mockDB, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	repo, err := gerpo.New[Counter]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("counters").
+		Columns(func(m *Counter, columns *gerpo.ColumnBuilder[Counter]) {
+			columns.Field(&m.ID).OmitOnUpdate()
+			columns.Field(&m.Hits).ReturnedOnUpdate()
+			columns.Field(&m.Status)
+			columns.Field(&m.CreatedAt).OmitOnUpdate()
+			columns.Field(&m.DeletedAt)
+		}).
+		WithQuery(func(m *Counter, h query.PersistentHelper[Counter]) {
+			h.Where().Field(&m.DeletedAt).EQ(nil)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when building repository", err)
+	}
+
+	// Assignments bind first, WHERE args after them; RETURNING fills fresh models.
+	mockDB.ExpectQuery(`UPDATE counters SET hits = hits \+ \?, status = upper\(\?\) WHERE \(counters.deleted_at IS NULL\) AND \(counters.status = \?\) RETURNING hits`).
+		WithArgs(1, "done", "open").
+		WillReturnRows(sqlmock.NewRows([]string{"hits"}).AddRow(4).AddRow(9))
+	models, n, err := repo.UpdateWhere(context.Background(), func(m *Counter, h query.UpdateHelper[Counter]) {
+		h.Increment(&m.Hits, 1)
+		h.SetExpr(&m.Status, "upper(?)", "done")
+		h.Where().Field(&m.Status).EQ("open")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 2 || len(models) != 2 || models[0].Hits != 4 || models[1].Hits != 9 {
+		t.Fatalf("unexpected result: n=%d models=%v", n, models)
+	}
+
+	// Without RETURNING only the count comes back; no matching rows is ErrNotFound.
+	mockDB.ExpectExec(`UPDATE counters SET status = \? WHERE \(counters.deleted_at IS NULL\)`).
+		WithArgs("archived").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, _, err = repo.UpdateWhere(context.Background(), func(m *Counter, h query.UpdateHelper[Counter]) {
+		h.Set(&m.Status, "archived")
+		h.Returning()
+	})
+	if !errors.Is(err, gerpo.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// OmitOnUpdate columns cannot be assigned.
+	_, _, err = repo.UpdateWhere(context.Background(), func(m *Counter, h query.UpdateHelper[Counter]) {
+		h.Set(&m.CreatedAt, time.Now())
+	})
+	if !errors.Is(err, query.ErrApplySetClause) {
+		t.Fatalf("expected ErrApplySetClause, got %v", err)
+	}
+
+	// At least one assignment is required.
+	_, _, err = repo.UpdateWhere(context.Background())
+	if !errors.Is(err, gerpo.ErrNoAssignments) {
+		t.Fatalf("expected ErrNoAssignments, got %v", err)
+	}
+
+	if err = mockDB.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/insei/gerpo"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+	"github.com/insei/gerpo/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	type Doc struct {
+		ID        int
+		Title     string
+		Version   int
+		DeletedAt *time.Time
+	}
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db, mockDB, err := sqlmock.New()
+	require.NoError(t, err)
+	adapter := databasesql.NewAdapter(db)
+	repo, err := gerpo.New[Doc]().
+		Adapter(adapter).
+		Table("docs").
+		Columns(func(m *Doc, columns *gerpo.ColumnBuilder[Doc]) {
+			columns.Field(&m.ID).OmitOnUpdate()
+			columns.Field(&m.Title)
+			columns.Field(&m.Version).Version()
+			columns.Field(&m.DeletedAt)
+		}).
+		WithSoftDeletion(func(m *Doc, softDeletion *gerpo.SoftDeletionBuilder[Doc]) {
+			softDeletion.Field(&m.DeletedAt).SetValueFn(func(ctx context.Context) any {
+				return &deletedAt
+			})
+		}).
+		Build()
+	require.NoError(t, err)
+	byID := func(id int) func(m *Doc, h query.UpdateHelper[Doc]) {
+		return func(m *Doc, h query.UpdateHelper[Doc]) {
+			h.Where().Field(&m.ID).EQ(id)
+		}
+	}
+
+	t.Run("Update matches the current version and writes the next one back", func(t *testing.T) {
+		doc := &Doc{ID: 1, Title: "a", Version: 3}
+		mockDB.ExpectExec(`UPDATE docs SET title = \?, deleted_at = \?, version = \? WHERE \(docs.id = \?\) AND \(docs.version = \?\)`).
+			WithArgs("a", nil, 4, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repo.Update(context.Background(), doc, byID(1))
+		require.NoError(t, err)
+		assert.Equal(t, 4, doc.Version)
+	})
+
+	t.Run("Update of a stale version is ErrStaleVersion and keeps the model", func(t *testing.T) {
+		doc := &Doc{ID: 1, Title: "a", Version: 3}
+		mockDB.ExpectExec(`UPDATE docs SET title = \?, deleted_at = \?, version = \? WHERE \(docs.id = \?\) AND \(docs.version = \?\)`).
+			WithArgs("a", nil, 4, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repo.Update(context.Background(), doc, byID(1))
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+		assert.NotErrorIs(t, err, gerpo.ErrNotFound)
+		assert.Equal(t, 3, doc.Version)
+	})
+
+	t.Run("UpdateWhere bumps the version in SQL", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET title = \?, version = version \+ 1 WHERE \(docs.id = \?\)`).
+			WithArgs("b", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, _, err := repo.UpdateWhere(context.Background(), func(m *Doc, h query.UpdateHelper[Doc]) {
+			h.Set(&m.Title, "b")
+			h.Where().Field(&m.ID).EQ(1)
+		})
+		require.NoError(t, err)
+	})
+
+	byKey := func(m *Doc, h query.UpdateManyHelper[Doc]) { h.Key(&m.ID) }
+
+	t.Run("UpdateMany matches every row on its version and writes the next ones back", func(t *testing.T) {
+		docs := []*Doc{{ID: 1, Title: "a", Version: 3}, {ID: 2, Title: "b", Version: 7}}
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec(`UPDATE docs SET title = v.title, deleted_at = v.deleted_at, version = v.version \+ 1 FROM \(SELECT id, title, deleted_at, version FROM docs WHERE false UNION ALL SELECT \?, \?, \?, \? UNION ALL SELECT \?, \?, \?, \?\) AS v WHERE docs.id = v.id AND docs.version = v.version`).
+			WithArgs(1, "a", nil, 3, 2, "b", nil, 7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mockDB.ExpectCommit()
+
+		_, err := repo.UpdateMany(context.Background(), docs, byKey)
+		require.NoError(t, err)
+		assert.Equal(t, 4, docs[0].Version)
+		assert.Equal(t, 8, docs[1].Version)
+	})
+
+	t.Run("UpdateMany with a stale row rolls back the others and keeps the models", func(t *testing.T) {
+		docs := []*Doc{{ID: 1, Title: "a", Version: 3}, {ID: 2, Title: "b", Version: 7}}
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec(`UPDATE docs SET .* AND docs.version = v.version`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectRollback()
+
+		count, err := repo.UpdateMany(context.Background(), docs, byKey)
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+		assert.Equal(t, int64(0), count)
+		assert.Equal(t, 3, docs[0].Version)
+		assert.Equal(t, 7, docs[1].Version)
+	})
+
+	t.Run("UpdateMany inside a transaction rolls back to a savepoint on a stale row", func(t *testing.T) {
+		docs := []*Doc{{ID: 1, Title: "a", Version: 3}, {ID: 2, Title: "b", Version: 7}}
+		mockDB.ExpectBegin()
+		mockDB.ExpectExec(`SAVEPOINT gerpo_sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectExec(`UPDATE docs SET .* AND docs.version = v.version`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mockDB.ExpectExec(`ROLLBACK TO SAVEPOINT gerpo_sp_1`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mockDB.ExpectRollback()
+
+		err := gerpo.RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			_, err := repo.UpdateMany(ctx, docs, byKey)
+			return err
+		})
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+		assert.Equal(t, 3, docs[0].Version)
+		assert.Equal(t, 7, docs[1].Version)
+	})
+
+	t.Run("Soft Delete with ExpectVersion", func(t *testing.T) {
+		mockDB.ExpectExec(`UPDATE docs SET deleted_at = \?, version = version \+ 1 WHERE \(docs.id = \?\) AND \(docs.version = \?\)`).
+			WithArgs(&deletedAt, 1, 5).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		_, err := repo.Delete(context.Background(), func(m *Doc, h query.DeleteHelper[Doc]) {
+			h.Where().Field(&m.ID).EQ(1)
+			h.ExpectVersion(5)
+		})
+		assert.ErrorIs(t, err, gerpo.ErrStaleVersion)
+	})
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestVersion_Validation(t *testing.T) {
+	type Doc struct {
+		ID       int
+		Version  int
+		Revision int
+	}
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	_, err = gerpo.New[Doc]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("docs").
+		Columns(func(m *Doc, columns *gerpo.ColumnBuilder[Doc]) {
+			columns.Field(&m.ID)
+			columns.Field(&m.Version).Version()
+			columns.Field(&m.Revision).Version()
+		}).
+		Build()
+	assert.Error(t, err, "a table has at most one version column")
+
+	// ExpectVersion needs a version column.
+	repo, err := gerpo.New[Doc]().
+		Adapter(databasesql.NewAdapter(db)).
+		Table("docs").
+		Columns(func(m *Doc, columns *gerpo.ColumnBuilder[Doc]) {
+			columns.Field(&m.ID)
+			columns.Field(&m.Version)
+		}).
+		Build()
+	require.NoError(t, err)
+	_, err = repo.Delete(context.Background(), func(m *Doc, h query.DeleteHelper[Doc]) {
+		h.ExpectVersion(1)
+	})
+	assert.ErrorIs(t, err, query.ErrApplyVersionCondition)
+}
@@ -2,9 +2,16 @@ package gerpo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/insei/gerpo/dialect"
 	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/internal/sqlstate"
 )
 
 // WithTx returns a context carrying tx. Every Repository operation invoked with
@@ -18,24 +25,169 @@ import (
 // responsibility — WithTx does not lifecycle the transaction.
 var WithTx = executor.WithTx
 
+// TxOption configures RunInTx: TxOptions for the transaction itself, a
+// RetryPolicy for running it again after a retryable failure.
+type TxOption interface {
+	applyTx(cfg *txConfig) error
+}
+
+type txConfig struct {
+	options *executor.TxOptions
+	retry   *RetryPolicy
+}
+
+// TxOptions configures the transaction RunInTx begins: isolation level,
+// read-only and deferrable mode. The zero value is the database default.
+type TxOptions executor.TxOptions
+
+func (o TxOptions) applyTx(cfg *txConfig) error {
+	if cfg.options != nil {
+		return fmt.Errorf("more than one TxOptions")
+	}
+	opts := executor.TxOptions(o)
+	cfg.options = &opts
+	return nil
+}
+
+// IsolationLevel is the isolation level of TxOptions.
+type IsolationLevel = executor.IsolationLevel
+
+const (
+	LevelDefault         = executor.LevelDefault
+	LevelReadUncommitted = executor.LevelReadUncommitted
+	LevelReadCommitted   = executor.LevelReadCommitted
+	LevelRepeatableRead  = executor.LevelRepeatableRead
+	LevelSerializable    = executor.LevelSerializable
+)
+
+// RetryPolicy makes RunInTx run fn again, in a fresh transaction, when an
+// attempt fails with a retryable error — by default a serialization failure
+// or a deadlock (see IsSerializationFailure). The failed transaction is rolled
+// back before the next attempt; the error of the last attempt is returned.
+//
+// fn must be safe to run more than once: side effects outside the database
+// are not rolled back.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, the first one included; below 2
+	// there is no retry.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt; it doubles for every
+	// attempt after that, up to MaxBackoff when that is set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Jitter, between 0 and 1, is the share of every delay drawn at random:
+	// with 0.5 a delay of 100ms becomes anything from 50ms to 100ms, so
+	// conflicting transactions do not retry in lockstep.
+	Jitter float64
+	// Retryable classifies the error of an attempt; nil means
+	// IsSerializationFailure.
+	Retryable func(err error) bool
+}
+
+func (p RetryPolicy) applyTx(cfg *txConfig) error {
+	if cfg.retry != nil {
+		return fmt.Errorf("more than one RetryPolicy")
+	}
+	cfg.retry = &p
+	return nil
+}
+
+// delay is the pause before attempt number attempt, counted from 1.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 2; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsSerializationFailure(err)
+}
+
+// IsSerializationFailure reports whether err, or an error it wraps, is a
+// serialization failure (SQLSTATE 40001) or a detected deadlock (40P01) —
+// the errors after which PostgreSQL expects the whole transaction to be
+// retried. It understands the errors of the pgx adapters (*pgconn.PgError)
+// and of lib/pq behind database/sql (*pq.Error).
+func IsSerializationFailure(err error) bool {
+	switch sqlstate.Of(err) {
+	case "40001", "40P01":
+		return true
+	}
+	return false
+}
+
 // RunInTx runs fn inside a transaction started on adapter. The transaction is
 // committed when fn returns nil, rolled back otherwise. The passed-through ctx
 // inside fn already carries the tx (via WithTx), so every Repository call made
 // with that ctx is transactional — no manual wiring needed.
 //
+// opts take TxOptions, which open the transaction with an isolation level,
+// read-only or deferrable — the adapter must implement
+// executor.TxOptionsBeginner, as every bundled one does — and a RetryPolicy,
+// which runs fn again in a fresh transaction after a retryable failure. Each
+// of them may be passed once.
+//
+// When ctx carries a transaction already, RunInTx does not begin a second one:
+// fn runs in a savepoint of the outer transaction instead (see
+// RunInSavepoint), so functions that open a transaction compose without
+// knowing whether they are nested. The savepoint shares the mode of the outer
+// transaction and opts are ignored there: a retryable error reaches the
+// outermost RunInTx, which retries the whole transaction.
+//
 // If fn panics, RollbackUnlessCommitted runs; the panic is then re-raised.
 func RunInTx(
 	ctx context.Context,
 	adapter executor.Adapter,
 	fn func(ctx context.Context) error,
-) (err error) {
+	opts ...TxOption,
+) error {
 	if adapter == nil {
 		return fmt.Errorf("gerpo: RunInTx: adapter is nil")
 	}
 	if fn == nil {
 		return fmt.Errorf("gerpo: RunInTx: fn is nil")
 	}
-	tx, err := adapter.BeginTx(ctx)
+	cfg := txConfig{}
+	for _, opt := range opts {
+		if err := opt.applyTx(&cfg); err != nil {
+			return fmt.Errorf("gerpo: RunInTx: %w", err)
+		}
+	}
+	if _, ok := executor.TxFromContext(ctx); ok {
+		return RunInSavepoint(ctx, fn)
+	}
+	if cfg.retry == nil {
+		return runInTx(ctx, adapter, fn, cfg.options)
+	}
+	for attempt := 1; ; attempt++ {
+		err := runInTx(ctx, adapter, fn, cfg.options)
+		if err == nil || attempt >= cfg.retry.MaxAttempts || !cfg.retry.retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(cfg.retry.delay(attempt + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// runInTx is one attempt of RunInTx.
+func runInTx(ctx context.Context, adapter executor.Adapter, fn func(ctx context.Context) error, opts *executor.TxOptions) (err error) {
+	tx, err := beginTx(ctx, adapter, opts)
 	if err != nil {
 		return fmt.Errorf("gerpo: RunInTx: begin: %w", err)
 	}
@@ -46,8 +198,190 @@ func RunInTx(
 	}()
 
 	ctx = WithTx(ctx, tx)
+	if d := adapter.Dialect(); d != nil {
+		// RunInSavepoint renders the savepoint statements for it.
+		ctx = dialect.WithContext(ctx, d)
+	}
 	if err = fn(ctx); err != nil {
 		return err
 	}
 	return tx.Commit()
 }
+
+// beginTx begins a transaction on adapter, with opts when they are set.
+func beginTx(ctx context.Context, adapter executor.Adapter, opts *executor.TxOptions) (executor.Tx, error) {
+	if opts == nil || *opts == (executor.TxOptions{}) {
+		return adapter.BeginTx(ctx)
+	}
+	beginner, ok := adapter.(executor.TxOptionsBeginner)
+	if !ok {
+		return nil, fmt.Errorf("adapter does not support transaction options")
+	}
+	return beginner.BeginTxWithOptions(ctx, *opts)
+}
+
+// OnCommit registers fn to run once the transaction carried by ctx has
+// committed — the place to publish events for the rows written in it, so
+// consumers never see events of a transaction that was rolled back. Inside
+// RunInSavepoint, fn belongs to the savepoint: it is dropped when the
+// savepoint is rolled back to.
+//
+// fn runs after Commit returns, outside the transaction. ctx without a
+// transaction, or with one whose adapter does not implement
+// executor.TxCallbacks, is an error; every bundled adapter does.
+func OnCommit(ctx context.Context, fn func()) error {
+	return registerTxCallback(ctx, "OnCommit", fn, true)
+}
+
+// OnRollback registers fn to run once the transaction carried by ctx has been
+// rolled back, or the savepoint of RunInSavepoint fn was registered in. It
+// follows the rules of OnCommit.
+func OnRollback(ctx context.Context, fn func()) error {
+	return registerTxCallback(ctx, "OnRollback", fn, false)
+}
+
+func registerTxCallback(ctx context.Context, op string, fn func(), onCommit bool) error {
+	if fn == nil {
+		return fmt.Errorf("gerpo: %s: fn is nil", op)
+	}
+	tx, ok := executor.TxFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("gerpo: %s: ctx carries no transaction", op)
+	}
+	callbacks, ok := tx.(executor.TxCallbacks)
+	if !ok {
+		return fmt.Errorf("gerpo: %s: transaction does not support callbacks", op)
+	}
+	if sp, ok := ctx.Value(savepointKey{}).(*savepoint); ok {
+		sp.register(fn, onCommit)
+		return nil
+	}
+	if onCommit {
+		callbacks.OnCommit(fn)
+	} else {
+		callbacks.OnRollback(fn)
+	}
+	return nil
+}
+
+// savepointKey stashes the innermost savepoint of RunInSavepoint in ctx.
+type savepointKey struct{}
+
+// savepoint is a RunInSavepoint call in progress: its nesting depth and the
+// OnCommit / OnRollback callbacks registered inside it, which wait for the
+// savepoint to be released or rolled back to.
+type savepoint struct {
+	parent *savepoint
+	depth  int
+
+	mu         sync.Mutex
+	onCommit   []func()
+	onRollback []func()
+}
+
+func (sp *savepoint) register(fn func(), onCommit bool) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if onCommit {
+		sp.onCommit = append(sp.onCommit, fn)
+	} else {
+		sp.onRollback = append(sp.onRollback, fn)
+	}
+}
+
+// release hands the callbacks over to the enclosing savepoint, or to the
+// transaction at the top level.
+func (sp *savepoint) release(tx executor.Tx) {
+	sp.mu.Lock()
+	onCommit, onRollback := sp.onCommit, sp.onRollback
+	sp.onCommit, sp.onRollback = nil, nil
+	sp.mu.Unlock()
+	if sp.parent != nil {
+		for _, fn := range onCommit {
+			sp.parent.register(fn, true)
+		}
+		for _, fn := range onRollback {
+			sp.parent.register(fn, false)
+		}
+		return
+	}
+	callbacks, ok := tx.(executor.TxCallbacks)
+	if !ok {
+		return
+	}
+	for _, fn := range onCommit {
+		callbacks.OnCommit(fn)
+	}
+	for _, fn := range onRollback {
+		callbacks.OnRollback(fn)
+	}
+}
+
+// rollback runs the OnRollback callbacks and drops the OnCommit ones: the
+// work they were registered with is undone.
+func (sp *savepoint) rollback() {
+	sp.mu.Lock()
+	onRollback := sp.onRollback
+	sp.onCommit, sp.onRollback = nil, nil
+	sp.mu.Unlock()
+	for _, fn := range onRollback {
+		fn()
+	}
+}
+
+// RunInSavepoint runs fn inside a savepoint of the transaction carried by
+// ctx. The savepoint is released when fn returns nil and rolled back to
+// otherwise, so an error undoes only the work of fn and leaves the outer
+// transaction usable; the error of fn is returned, joined with the rollback
+// error if that fails too. A savepoint whose release fails is rolled back to
+// as well. Savepoints nest, and each gets a name from its
+// depth: gerpo_sp_1, gerpo_sp_2, and so on.
+//
+// The statements follow the dialect RunInTx bound to ctx; a transaction
+// installed with WithTx gets the PostgreSQL form, which MySQL and SQLite
+// share. ctx without a transaction is an error.
+//
+// If fn panics, the savepoint is rolled back to; the panic is then re-raised.
+func RunInSavepoint(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if fn == nil {
+		return fmt.Errorf("gerpo: RunInSavepoint: fn is nil")
+	}
+	tx, ok := executor.TxFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("gerpo: RunInSavepoint: ctx carries no transaction")
+	}
+	sp := &savepoint{depth: 1}
+	if parent, ok := ctx.Value(savepointKey{}).(*savepoint); ok {
+		sp.parent, sp.depth = parent, parent.depth+1
+	}
+	name := "gerpo_sp_" + strconv.Itoa(sp.depth)
+	d := dialect.FromContext(ctx)
+
+	if _, err = tx.ExecContext(ctx, d.Savepoint(name)); err != nil {
+		return fmt.Errorf("gerpo: RunInSavepoint: set %s: %w", name, err)
+	}
+	released := false
+	defer func() {
+		if released {
+			return
+		}
+		if _, rbErr := tx.ExecContext(ctx, d.RollbackToSavepoint(name)); rbErr != nil {
+			err = errors.Join(err, fmt.Errorf("gerpo: RunInSavepoint: rollback to %s: %w", name, rbErr))
+		}
+		sp.rollback()
+	}()
+
+	if err = fn(context.WithValue(ctx, savepointKey{}, sp)); err != nil {
+		return err
+	}
+	// The callbacks move to the outer transaction only once the savepoint is
+	// released; a failed RELEASE rolls back to it like an error of fn.
+	if release := d.ReleaseSavepoint(name); release != "" {
+		if _, err = tx.ExecContext(ctx, release); err != nil {
+			return fmt.Errorf("gerpo: RunInSavepoint: release %s: %w", name, err)
+		}
+	}
+	released = true
+	sp.release(tx)
+	return nil
+}
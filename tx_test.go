@@ -0,0 +1,444 @@
+package gerpo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/insei/gerpo/executor"
+	"github.com/insei/gerpo/executor/adapters/databasesql"
+)
+
+func TestRunInTx_Nested(t *testing.T) {
+	errInner := errors.New("inner")
+
+	t.Run("Nested RunInTx becomes a released savepoint", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_2$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_2$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		adapter := databasesql.NewAdapter(db)
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			return RunInTx(ctx, adapter, func(ctx context.Context) error {
+				return RunInSavepoint(ctx, func(ctx context.Context) error { return nil })
+			})
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("An error rolls back to the savepoint only", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^ROLLBACK TO SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		adapter := databasesql.NewAdapter(db)
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			innerErr := RunInSavepoint(ctx, func(ctx context.Context) error { return errInner })
+			assert.ErrorIs(t, innerErr, errInner)
+			return RunInSavepoint(ctx, func(ctx context.Context) error { return nil })
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A panic rolls back to the savepoint and the transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^ROLLBACK TO SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		adapter := databasesql.NewAdapter(db)
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+				return RunInSavepoint(ctx, func(ctx context.Context) error { panic("boom") })
+			})
+		})
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SQL Server savepoints are never released", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVE TRANSACTION gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^ROLLBACK TRANSACTION gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^SAVE TRANSACTION gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		adapter := databasesql.NewSQLServerAdapter(db)
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			_ = RunInSavepoint(ctx, func(ctx context.Context) error { return errInner })
+			return RunInSavepoint(ctx, func(ctx context.Context) error { return nil })
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("RunInSavepoint needs a transaction", func(t *testing.T) {
+		err := RunInSavepoint(context.Background(), func(ctx context.Context) error { return nil })
+		assert.Error(t, err)
+	})
+}
+
+// optsAdapter is an Adapter whose BeginTxWithOptions records the options and
+// hands out the tx of the wrapped adapter.
+type optsAdapter struct {
+	executor.Adapter
+	opts []executor.TxOptions
+}
+
+func (a *optsAdapter) BeginTxWithOptions(ctx context.Context, opts executor.TxOptions) (executor.Tx, error) {
+	a.opts = append(a.opts, opts)
+	return a.BeginTx(ctx)
+}
+
+func TestRunInTx_Options(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	t.Run("Options reach an adapter that accepts them", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		adapter := &optsAdapter{Adapter: databasesql.NewAdapter(db)}
+		opts := TxOptions{Isolation: LevelSerializable, ReadOnly: true}
+		require.NoError(t, RunInTx(context.Background(), adapter, noop, opts))
+		require.NoError(t, RunInTx(context.Background(), adapter, noop, TxOptions{}))
+		assert.Equal(t, []executor.TxOptions{executor.TxOptions(opts)}, adapter.opts, "zero options begin a plain transaction")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("An adapter without options support is rejected", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		adapter := struct{ executor.Adapter }{databasesql.NewAdapter(db)}
+		err = RunInTx(context.Background(), adapter, noop, TxOptions{ReadOnly: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("database/sql has no deferrable mode", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), noop, TxOptions{Isolation: LevelSerializable, ReadOnly: true, Deferrable: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("More than one TxOptions is an error", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), noop, TxOptions{}, TxOptions{})
+		assert.Error(t, err)
+	})
+}
+
+// stateError carries a SQLSTATE the way *pgconn.PgError does.
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+// fieldError carries a SQLSTATE the way *pq.Error does.
+type fieldError string
+
+func (e fieldError) Error() string { return "pq: " + string(e) }
+func (e fieldError) Get(k byte) string {
+	if k == 'C' {
+		return string(e)
+	}
+	return ""
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, IsSerializationFailure(stateError("40001")))
+	assert.True(t, IsSerializationFailure(fmt.Errorf("wrapped: %w", stateError("40P01"))))
+	assert.True(t, IsSerializationFailure(fieldError("40001")))
+	assert.False(t, IsSerializationFailure(stateError("23505")))
+	assert.False(t, IsSerializationFailure(errors.New("40001")))
+	assert.False(t, IsSerializationFailure(nil))
+}
+
+func TestRunInTx_Retry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+
+	t.Run("A serialization failure reruns fn in a fresh transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		attempts := 0
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("update: %w", stateError("40001"))
+			}
+			return nil
+		}, policy)
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A failed commit is retried too", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(fieldError("40001"))
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error { return nil }, policy)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Attempts stop at MaxAttempts", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		for range 3 {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+
+		attempts := 0
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			attempts++
+			return stateError("40P01")
+		}, policy)
+		assert.True(t, IsSerializationFailure(err))
+		assert.Equal(t, 3, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Other errors are not retried", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		errFn := errors.New("fn")
+		attempts := 0
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			attempts++
+			return errFn
+		}, policy)
+		assert.ErrorIs(t, err, errFn)
+		assert.Equal(t, 1, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A custom classifier decides what is retried", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		errBusy := errors.New("busy")
+		attempts := 0
+		custom := policy
+		custom.Retryable = func(err error) bool { return errors.Is(err, errBusy) }
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return errBusy
+			}
+			return nil
+		}, custom)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A cancelled ctx stops the backoff", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err = RunInTx(ctx, databasesql.NewAdapter(db), func(ctx context.Context) error {
+			cancel()
+			return stateError("40001")
+		}, RetryPolicy{MaxAttempts: 3, Backoff: time.Hour})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, IsSerializationFailure(err))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A nested RunInTx leaves the retry to the outer one", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^ROLLBACK TO SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		adapter := databasesql.NewAdapter(db)
+		attempts := 0
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			return RunInTx(ctx, adapter, func(ctx context.Context) error {
+				attempts++
+				if attempts == 1 {
+					return stateError("40001")
+				}
+				return nil
+			}, policy)
+		}, policy)
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Backoff doubles up to MaxBackoff", func(t *testing.T) {
+		p := RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}
+		assert.Equal(t, 10*time.Millisecond, p.delay(2))
+		assert.Equal(t, 20*time.Millisecond, p.delay(3))
+		assert.Equal(t, 25*time.Millisecond, p.delay(4))
+		assert.Equal(t, 25*time.Millisecond, p.delay(40))
+
+		p.Jitter = 0.5
+		for range 20 {
+			d := p.delay(2)
+			assert.GreaterOrEqual(t, d, 5*time.Millisecond)
+			assert.LessOrEqual(t, d, 10*time.Millisecond)
+		}
+	})
+
+	t.Run("More than one RetryPolicy is an error", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error { return nil }, policy, policy)
+		assert.Error(t, err)
+	})
+}
+
+func TestOnCommit(t *testing.T) {
+	errFn := errors.New("fn")
+
+	t.Run("Callbacks wait for the outcome of RunInTx", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		adapter := databasesql.NewAdapter(db)
+		var calls []string
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			require.NoError(t, OnCommit(ctx, func() { calls = append(calls, "committed") }))
+			require.NoError(t, OnRollback(ctx, func() { calls = append(calls, "rolled back") }))
+			assert.Empty(t, calls, "nothing runs before the transaction ends")
+			return nil
+		})
+		require.NoError(t, err)
+		err = RunInTx(context.Background(), adapter, func(ctx context.Context) error {
+			require.NoError(t, OnCommit(ctx, func() { calls = append(calls, "committed") }))
+			require.NoError(t, OnRollback(ctx, func() { calls = append(calls, "rolled back") }))
+			return errFn
+		})
+		assert.ErrorIs(t, err, errFn)
+		assert.Equal(t, []string{"committed", "rolled back"}, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A savepoint rolled back to drops its OnCommit callbacks", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_2$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_2$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^ROLLBACK TO SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var calls []string
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			_ = RunInSavepoint(ctx, func(ctx context.Context) error {
+				require.NoError(t, RunInSavepoint(ctx, func(ctx context.Context) error {
+					require.NoError(t, OnCommit(ctx, func() { calls = append(calls, "inner committed") }))
+					return OnRollback(ctx, func() { calls = append(calls, "inner rolled back") })
+				}))
+				return errFn
+			})
+			assert.Equal(t, []string{"inner rolled back"}, calls, "rolling back the outer savepoint undoes the released inner one")
+			return RunInSavepoint(ctx, func(ctx context.Context) error {
+				return OnCommit(ctx, func() { calls = append(calls, "committed") })
+			})
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"inner rolled back", "committed"}, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("A failed RELEASE rolls back to the savepoint and drops its OnCommit callbacks", func(t *testing.T) {
+		errRelease := errors.New("release")
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec(`^SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`^RELEASE SAVEPOINT gerpo_sp_1$`).WillReturnError(errRelease)
+		mock.ExpectExec(`^ROLLBACK TO SAVEPOINT gerpo_sp_1$`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var calls []string
+		err = RunInTx(context.Background(), databasesql.NewAdapter(db), func(ctx context.Context) error {
+			spErr := RunInSavepoint(ctx, func(ctx context.Context) error {
+				require.NoError(t, OnCommit(ctx, func() { calls = append(calls, "committed") }))
+				return OnRollback(ctx, func() { calls = append(calls, "rolled back") })
+			})
+			assert.ErrorIs(t, spErr, errRelease)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"rolled back"}, calls)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ctx needs a transaction with callbacks", func(t *testing.T) {
+		noop := func() {}
+		assert.Error(t, OnCommit(context.Background(), noop))
+		assert.Error(t, OnRollback(context.Background(), noop))
+
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		tx, err := databasesql.NewAdapter(db).BeginTx(context.Background())
+		require.NoError(t, err)
+		plain := struct{ executor.Tx }{tx}
+		assert.Error(t, OnCommit(WithTx(context.Background(), plain), noop))
+		assert.Error(t, OnCommit(WithTx(context.Background(), tx), nil))
+		assert.NoError(t, OnCommit(WithTx(context.Background(), tx), noop))
+	})
+}
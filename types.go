@@ -3,6 +3,7 @@ package gerpo
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"github.com/insei/gerpo/executor"
 	"github.com/insei/gerpo/query"
@@ -13,6 +14,55 @@ var (
 	ErrNotFound             = executor.ErrNoRows
 	ErrApplyQuery           = fmt.Errorf("failed to apply query")
 	ErrApplyPersistentQuery = fmt.Errorf("failed to apply persistent query")
+	// ErrInsertConflict is returned by Insert / Upsert when the row was skipped
+	// by its ON CONFLICT clause — DO NOTHING, or a DO UPDATE whose WHERE did not
+	// match the existing row.
+	ErrInsertConflict = executor.ErrInsertConflict
+	// ErrCopyNotSupported is returned by BulkCopy when the adapter (or the ctx
+	// transaction) cannot run COPY FROM. The bundled pgx v5 and pgx v4
+	// adapters support it; database/sql does not.
+	ErrCopyNotSupported = executor.ErrCopyNotSupported
+	// ErrInvalidCursor is returned by GetList when the keyset cursor passed to
+	// After is malformed or does not match the request's OrderBy.
+	ErrInvalidCursor = query.ErrInvalidCursor
+	// ErrNoAssignments is returned by UpdateWhere when the query function did
+	// not assign any field through Set / Increment / SetExpr.
+	ErrNoAssignments = fmt.Errorf("update has no SET assignments")
+	// ErrStaleVersion is returned by Update on a repository with a Version()
+	// column, and by Delete with ExpectVersion, when no row is at the expected
+	// version — it was changed or removed concurrently. Map it to 409 Conflict.
+	ErrStaleVersion = fmt.Errorf("stale version: the row was changed or removed concurrently")
+	// ErrNoPrimaryKey is returned by GetByID, GetByIDs and DeleteByID on a
+	// repository without PrimaryKey() columns.
+	ErrNoPrimaryKey = fmt.Errorf("no column is marked as PrimaryKey()")
+	// ErrReturningNotSupported is returned on dialects without RETURNING
+	// (MySQL) when the returned columns cannot be read back: the repository
+	// has no PrimaryKey() column, InsertMany would need generated keys, or the
+	// call is UpdateWhere.
+	ErrReturningNotSupported = executor.ErrReturningNotSupported
+	// ErrUpsertNotSupported is returned by Upsert, and by Insert and
+	// InsertMany with an OnConflict spec, on dialects without ON CONFLICT
+	// (MySQL, SQL Server). Nothing is sent to the database.
+	ErrUpsertNotSupported = fmt.Errorf("upsert is not supported by the dialect")
+)
+
+// Constraint violations. The bundled adapters hand the driver errors of
+// broken constraints back as these types, so one errors.As works whatever the
+// driver; the driver error stays reachable through Unwrap:
+//
+//	var dup *gerpo.ErrUniqueViolation
+//	if errors.As(err, &dup) && dup.Field == "Email" {
+//		return ErrEmailTaken
+//	}
+//
+// Every type embeds ConstraintViolation — constraint, table, column and the
+// field the column maps to, filled by the repository of that table.
+type (
+	ConstraintViolation    = executor.ConstraintViolation
+	ErrUniqueViolation     = executor.ErrUniqueViolation
+	ErrForeignKeyViolation = executor.ErrForeignKeyViolation
+	ErrCheckViolation      = executor.ErrCheckViolation
+	ErrNotNullViolation    = executor.ErrNotNullViolation
 )
 
 // Repository represents a generic data repository interface for managing models in the database.
@@ -21,30 +71,124 @@ type Repository[TModel any] interface {
 	GetColumns() types.ColumnsStorage
 	// GetFirst retrieves the first record matching the query conditions.
 	GetFirst(ctx context.Context, qFns ...func(m *TModel, h query.GetFirstHelper[TModel])) (model *TModel, err error)
+	// GetByID retrieves the record with the given primary key, values in the
+	// declaration order of the PrimaryKey() columns. The persistent query
+	// applies; when no record matches, the error is ErrNotFound.
+	GetByID(ctx context.Context, key ...any) (model *TModel, err error)
+	// GetByIDs retrieves the records with the given primary keys: a value per
+	// key for a single-column key, a []any per key for a composite one. Keys
+	// without a record are skipped and the order of the result is unspecified.
+	// Long key lists are split into several statements; for an empty list
+	// GetByIDs returns (nil, nil) without touching the database.
+	GetByIDs(ctx context.Context, keys ...any) (models []*TModel, err error)
 	// GetList retrieves a list of records matching the query conditions.
 	GetList(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) (models []*TModel, err error)
+	// GetPage retrieves one page of records together with the total number of
+	// records matching the query conditions — the GetList and Count of a list
+	// endpoint in one call, one query closure and, normally, one SQL statement:
+	// the total rides along as a count(*) over() window column, so the page
+	// and the total come from the same snapshot. Only a page past the end
+	// costs a second round-trip to learn the total. Keyset cursors (After) are
+	// rejected: the window would count only the rows after the cursor.
+	GetPage(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) (models []*TModel, total uint64, err error)
+	// Iterate streams the records matching the query conditions one model at a
+	// time instead of materialising the whole list — for exports, reindex jobs
+	// and other reads too large for GetList. The SQL runs when the sequence is
+	// ranged over; rows are closed when the loop ends, early break included.
+	//
+	// The AfterSelect hook runs on batches of WithIterateBatchSize models (100
+	// by default) before they are yielded. The cache is bypassed. Errors,
+	// including a cancelled ctx, are yielded once as (nil, err) and end the
+	// sequence.
+	//
+	//	for user, err := range repo.Iterate(ctx, qFn) {
+	//	    if err != nil {
+	//	        return err
+	//	    }
+	//	    ...
+	//	}
+	Iterate(ctx context.Context, qFns ...func(m *TModel, h query.GetListHelper[TModel])) iter.Seq2[*TModel, error]
 	// Count returns the count of records matching the query conditions.
 	Count(ctx context.Context, qFns ...func(m *TModel, h query.CountHelper[TModel])) (count uint64, err error)
 	// Insert adds a new record to the database using the provided model and query options.
 	Insert(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.InsertHelper[TModel])) (err error)
+	// Upsert inserts the model or resolves the conflict with an existing row
+	// through the ON CONFLICT spec configured by the query function
+	// (h.OnConflict(...).DoUpdate(...) / .DoNothing()). The spec is mandatory.
+	// RETURNING write-back works as in Insert; when the row is skipped (DO
+	// NOTHING, or DO UPDATE ... WHERE not matching) the error is
+	// ErrInsertConflict and the After-hook does not run.
+	Upsert(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.InsertHelper[TModel])) (err error)
 	// InsertMany bulk-inserts the given slice as a single multi-row INSERT
-	// statement. The call is transparently chunked at the driver's placeholder
-	// limit (PostgreSQL: 65535 bound params per query), so arbitrarily large
-	// slices are safe. Returns the total number of rows written.
+	// statement. The call is transparently chunked at the dialect's placeholder
+	// limit (PostgreSQL: 65535 bound params per query, SQL Server: 2100 params
+	// and 1000 rows), so arbitrarily large slices are safe. Returns the total number of rows written.
 	//
 	// RETURNING — when the repository has columns marked ReturnedOnInsert (or
 	// the per-request helper calls Returning(...)), scanned values are written
-	// back into each model by position. For an empty slice, InsertMany returns
-	// (0, nil) without touching the database or running hooks.
+	// back into each model by position. With an OnConflict spec rows skipped
+	// by the conflict rule are not counted, and RETURNING rows are matched to
+	// models by the conflict target instead. For an empty slice, InsertMany
+	// returns (0, nil) without touching the database or running hooks.
 	//
 	// Failure mid-batch leaves the rows already committed by prior chunks in
 	// place — wrap the call in gerpo.RunInTx if atomicity across chunks is
 	// required.
 	InsertMany(ctx context.Context, models []*TModel, qFns ...func(m *TModel, h query.InsertManyHelper[TModel])) (count int64, err error)
+	// BulkCopy loads the slice with PostgreSQL COPY FROM — the fast path for
+	// imports of tens of thousands of rows and more. It writes the same column
+	// set as Insert and runs the WithBeforeInsertMany / WithAfterInsertMany
+	// hooks around the load.
+	//
+	// There is no RETURNING: COPY reports the row count only, so server
+	// generated values (ReturnedOnInsert columns, DB defaults) are not written
+	// back into the models. Requires an adapter with COPY support, otherwise
+	// ErrCopyNotSupported. COPY is a single statement — the load either lands
+	// completely or not at all.
+	BulkCopy(ctx context.Context, models []*TModel) (count int64, err error)
 	// Update modifies an existing record in the database based on the provided model and query options.
+	//
+	// Without a WHERE from the query functions the row is matched on the
+	// model's PrimaryKey() columns; a repository without a primary key then
+	// updates every row.
+	//
+	// With a Version() column the row is matched on the model's current
+	// version as well, the version is SET one higher and written back into the
+	// model on success; when no row matches, the error is ErrStaleVersion.
 	Update(ctx context.Context, model *TModel, qFns ...func(m *TModel, h query.UpdateHelper[TModel])) (count int64, err error)
+	// UpdateWhere updates every record matching the query conditions without a
+	// model: the SET clause comes from h.Set / h.Increment / h.SetExpr, at
+	// least one is required (ErrNoAssignments). The persistent query applies
+	// as in Update, the single-row Update hooks do not run. When no record
+	// matches, the error is ErrNotFound.
+	//
+	// With RETURNING (ReturnedOnUpdate columns or h.Returning(...)) every
+	// updated record comes back as a model with only the returned fields
+	// filled; without it models is nil and only count is reported.
+	UpdateWhere(ctx context.Context, qFns ...func(m *TModel, h query.UpdateHelper[TModel])) (models []*TModel, count int64, err error)
+	// UpdateMany writes the slice back in one UPDATE statement per chunk,
+	// matching every model to its row by the PrimaryKey() columns, or by the
+	// key the query function declares with h.Key(...). The column set, persistent
+	// query and WHERE conditions apply as in Update. Chunking at the
+	// placeholder limit works as in InsertMany. Returns the number of rows
+	// updated; models without a matching row are skipped, and ErrNotFound is
+	// returned when no row matched at all.
+	//
+	// RETURNING — ReturnedOnUpdate columns (or h.Returning(...)) are written
+	// back into the models, matched by the key. For an empty slice UpdateMany
+	// returns (0, nil) without touching the database or running hooks.
+	//
+	// Failure mid-batch leaves the rows already updated by prior chunks in
+	// place — wrap the call in gerpo.RunInTx if atomicity across chunks is
+	// required. With a Version column the batch runs in a transaction (or a
+	// savepoint of the one ctx carries) and is rolled back as a whole when a
+	// row is stale: the error is ErrStaleVersion, the count 0.
+	UpdateMany(ctx context.Context, models []*TModel, qFns ...func(m *TModel, h query.UpdateManyHelper[TModel])) (count int64, err error)
 	// Delete removes records from the database based on the query conditions and returns the count of deleted records.
 	Delete(ctx context.Context, qFns ...func(m *TModel, h query.DeleteHelper[TModel])) (count int64, err error)
+	// DeleteByID removes the record with the given primary key (soft deletion
+	// applies as in Delete). When no record matches, the error is ErrNotFound.
+	DeleteByID(ctx context.Context, key ...any) (err error)
 }
 
 // Builder represents a generic interface for building and configuring a repository for a specific model type.
@@ -62,6 +206,10 @@ type Builder[TModel any] interface {
 	// WithBeforeUpdate registers a hook called before the UPDATE SQL. Returning a
 	// non-nil error aborts the call; the SQL does not run.
 	WithBeforeUpdate(fn func(ctx context.Context, m *TModel) error) Builder[TModel]
+	// WithBeforeUpdateMany registers a hook called before the batched UPDATE
+	// statement with the full slice. Returning a non-nil error aborts the call;
+	// the SQL does not run.
+	WithBeforeUpdateMany(fn func(ctx context.Context, models []*TModel) error) Builder[TModel]
 	// WithAfterSelect registers a hook called after GetFirst/GetList with the
 	// scanned models. A non-nil error is surfaced after the rows are already
 	// fetched.
@@ -79,10 +227,16 @@ type Builder[TModel any] interface {
 	// WithAfterUpdate registers a hook called after a successful UPDATE.
 	// Same error contract as WithAfterInsert.
 	WithAfterUpdate(fn func(ctx context.Context, m *TModel) error) Builder[TModel]
+	// WithAfterUpdateMany registers a hook called after a successful batched
+	// UPDATE with the full slice. Same error contract as WithAfterInsertMany.
+	WithAfterUpdateMany(fn func(ctx context.Context, models []*TModel) error) Builder[TModel]
 	// WithErrorTransformer allows customizing or wrapping errors during repository operations.
 	WithErrorTransformer(fn func(err error) error) Builder[TModel]
 	// WithTracer installs a tracing hook called around every Repository operation.
 	WithTracer(tracer Tracer) Builder[TModel]
+	// WithIterateBatchSize sets how many streamed models Iterate passes to the
+	// AfterSelect hook at once (default 100).
+	WithIterateBatchSize(size int) Builder[TModel]
 	// WithSoftDeletion configures soft deletion behavior for the model using the provided function and SoftDeletionBuilder.
 	WithSoftDeletion(fn func(m *TModel, softDeletion *SoftDeletionBuilder[TModel])) Builder[TModel]
 	// Build finalizes and constructs the configured repository for the model.
@@ -47,6 +47,14 @@ type ColumnBase struct {
 	// column in a RETURNING clause (server-generated PKs, DB DEFAULTs, trigger-
 	// updated columns). Populated via column.ReturnedOnInsert / .ReturnedOnUpdate.
 	ReturnedActions []SQLAction
+
+	// Version marks the optimistic-locking version counter of the table.
+	// Populated via column.Version.
+	Version bool
+
+	// PrimaryKey marks the column as (a part of) the table's primary key.
+	// Populated via column.PrimaryKey.
+	PrimaryKey bool
 }
 
 // IsAllowedAction determines if a given SQLAction is allowed for the column.
@@ -72,3 +80,15 @@ func (c *ColumnBase) HasFilterOverride(op Operation) bool {
 func (c *ColumnBase) IsReturned(action SQLAction) bool {
 	return slices.Contains(c.ReturnedActions, action)
 }
+
+// IsVersion reports whether the column is the table's optimistic-locking
+// version counter.
+func (c *ColumnBase) IsVersion() bool {
+	return c.Version
+}
+
+// IsPrimaryKey reports whether the column is (a part of) the table's primary
+// key.
+func (c *ColumnBase) IsPrimaryKey() bool {
+	return c.PrimaryKey
+}
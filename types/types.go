@@ -55,6 +55,22 @@ type Column interface {
 	// for the given action. Today only SQLActionInsert and SQLActionUpdate make
 	// sense — other actions return false.
 	IsReturned(action SQLAction) bool
+
+	// IsVersion reports whether the column is the table's optimistic-locking
+	// version counter (column.Version).
+	IsVersion() bool
+
+	// IsPrimaryKey reports whether the column is (a part of) the table's
+	// primary key (column.PrimaryKey).
+	IsPrimaryKey() bool
+}
+
+// DependentColumn is an optional capability of a Column: the tables its SQL
+// reads besides the one it belongs to, declared with virtual.DependsOn. The
+// repository hands them to its cache storage, so that a write to one of them
+// drops the cached reads of the column.
+type DependentColumn interface {
+	Dependencies() []string
 }
 
 // ColumnsGetter is an interface for retrieving a list of Column objects representing database table columns.
@@ -327,6 +343,25 @@ type OrderTarget interface {
 	Column(col Column) OrderOperation
 }
 
+// ConflictAction picks what an INSERT does when it hits the conflict target
+// configured through OnConflict / OnConflictConstraint.
+type ConflictAction interface {
+
+	// DoNothing skips the conflicting row (ON CONFLICT ... DO NOTHING).
+	DoNothing()
+
+	// DoUpdate overwrites the listed fields of the conflicting row with the values proposed for insertion.
+	// Called without fields, every inserted column that is allowed on UPDATE and is not part of the target is overwritten.
+	DoUpdate(fieldsPtr ...any) ConflictUpdate
+}
+
+// ConflictUpdate narrows the DO UPDATE branch of an upsert.
+type ConflictUpdate interface {
+
+	// Where restricts DO UPDATE to rows that match the condition; conflicting rows that do not match are left as is.
+	Where() WhereTarget
+}
+
 // GroupTarget represents an interface for configuring grouping targets in a structured query or operation.
 type GroupTarget interface {
 
@@ -0,0 +1,85 @@
+package gerpo
+
+import (
+	"reflect"
+
+	"github.com/insei/gerpo/dialect"
+	"github.com/insei/gerpo/sqlstmt"
+	"github.com/insei/gerpo/types"
+)
+
+// lockVersion arms the optimistic lock of a model-bound Update: the statement
+// matches the row on the model's current version and SETs the next one. The
+// returned function writes the next version into the model — call it once the
+// row is known to be updated.
+func lockVersion(stmt *sqlstmt.Update, col types.Column, model any) (commit func(), err error) {
+	field, next := nextVersion(col, model)
+	current := field.Interface()
+	stmt.SetVersion(col, "?", next.Interface())
+
+	where := stmt.Where()
+	where.StartGroup()
+	if err = where.AppendCondition(col, types.OperationEQ, current); err != nil {
+		return nil, err
+	}
+	where.EndGroup()
+	return func() { field.Set(next) }, nil
+}
+
+// bumpVersion makes a statement that is not bound to a model increment the
+// version column in SQL, so writers still holding the old version go stale.
+func bumpVersion(stmt *sqlstmt.Update, col types.Column) {
+	name, _ := col.Name()
+	stmt.SetVersion(col, dialect.Ident(dialect.FromContext(stmt.Ctx()), name)+" + 1")
+}
+
+// lockVersions arms the optimistic lock of an UpdateMany: every row matches
+// on the current version of its model and SETs the next one. The returned
+// function writes the next versions into the models — call it once every row
+// is known to be updated, and the update committed with them.
+func lockVersions[TModel any](stmt *sqlstmt.UpdateBatch, col types.Column, models []*TModel) (commit func()) {
+	stmt.SetVersion(col)
+	fields := make([]reflect.Value, len(models))
+	nexts := make([]reflect.Value, len(models))
+	for i, model := range models {
+		fields[i], nexts[i] = nextVersion(col, model)
+	}
+	return func() {
+		for i, field := range fields {
+			field.Set(nexts[i])
+		}
+	}
+}
+
+// snapshotColumns saves the values of cols in every model. The returned
+// function puts them back.
+func snapshotColumns[TModel any](cols []types.Column, models []*TModel) (restore func()) {
+	fields := make([]reflect.Value, 0, len(cols)*len(models))
+	saved := make([]reflect.Value, 0, len(cols)*len(models))
+	for _, model := range models {
+		for _, c := range cols {
+			field := reflect.ValueOf(c.GetPtr(model)).Elem()
+			v := reflect.New(field.Type()).Elem()
+			v.Set(field)
+			fields, saved = append(fields, field), append(saved, v)
+		}
+	}
+	return func() {
+		for i, field := range fields {
+			field.Set(saved[i])
+		}
+	}
+}
+
+// nextVersion returns the version field of model and the value following it.
+func nextVersion(col types.Column, model any) (field, next reflect.Value) {
+	field = reflect.ValueOf(col.GetPtr(model)).Elem()
+	next = reflect.New(field.Type()).Elem()
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(field.Int() + 1)
+	default:
+		next.SetUint(field.Uint() + 1)
+	}
+	return field, next
+}
@@ -45,6 +45,14 @@ func (b *Builder) Filter(op types.Operation, spec FilterSpec) *Builder {
 	return b
 }
 
+// DependsOn declares the tables the expression reads besides the repository
+// table — those of a subquery, for instance — so that a cache storage drops the
+// reads of the column when one of them is written.
+func (b *Builder) DependsOn(tables ...string) *Builder {
+	b.opts = append(b.opts, WithDependsOn(tables...))
+	return b
+}
+
 // Build constructs and returns an instance of types.Column based on the current field and options in the Builder.
 func (b *Builder) Build() (types.Column, error) {
 	return New(b.field, b.opts...)
@@ -183,3 +183,19 @@ func TestBuilder_Filter_MatchSpec_BoolBranches(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM tokens WHERE user_id = users.id)", sqlFalse)
 }
+
+func TestBuilder_DependsOn(t *testing.T) {
+	fields, _ := fmap.Get[TestModel]()
+	field := fields.MustFind("NonBool")
+
+	col, err := NewBuilder(field).
+		Compute("SELECT count(*) FROM posts WHERE posts.user_id = users.id").
+		DependsOn("posts", "").
+		DependsOn("comments").
+		Build()
+	require.NoError(t, err)
+
+	dependent, ok := col.(types.DependentColumn)
+	require.True(t, ok, "virtual columns implement types.DependentColumn")
+	assert.Equal(t, []string{"posts", "comments"}, dependent.Dependencies())
+}
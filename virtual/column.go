@@ -11,7 +11,8 @@ import (
 )
 
 type column struct {
-	base *types.ColumnBase
+	base         *types.ColumnBase
+	dependencies []string
 }
 
 func (c *column) GetAvailableFilterOperations() []types.Operation {
@@ -75,6 +76,24 @@ func (c *column) IsReturned(_ types.SQLAction) bool {
 	return false
 }
 
+// IsVersion always reports false: a computed expression cannot be a version
+// counter.
+func (c *column) IsVersion() bool {
+	return false
+}
+
+// IsPrimaryKey always reports false: a computed expression cannot identify a
+// row.
+func (c *column) IsPrimaryKey() bool {
+	return false
+}
+
+// Dependencies returns the tables declared with DependsOn; it implements
+// types.DependentColumn.
+func (c *column) Dependencies() []string {
+	return c.dependencies
+}
+
 func New(field fmap.Field, opts ...Option) (types.Column, error) {
 	if field == nil {
 		return nil, fmt.Errorf("field is nil")
@@ -51,6 +51,18 @@ func WithAggregate() Option {
 	})
 }
 
+// WithDependsOn declares the tables the expression reads besides the repository
+// table, reported by Dependencies. Empty names are skipped.
+func WithDependsOn(tables ...string) Option {
+	return columnOptionFn(func(c *column) {
+		for _, table := range tables {
+			if table != "" {
+				c.dependencies = append(c.dependencies, table)
+			}
+		}
+	})
+}
+
 // WithFilter registers a custom filter for one operation. spec is a FilterSpec —
 // see virtual.SQL / Bound / SQLArgs / Match / Func. Other operators keep their
 // auto-derived implementations (unless the column is Aggregate).